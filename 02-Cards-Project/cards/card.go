@@ -0,0 +1,112 @@
+/**
+ * @file: Describes what a single Card is in the public cards library.
+ */
+
+// Package
+// *******
+
+// Package cards is a library for French-suited playing cards: A 52-card Deck of Card values
+// that can be created, shuffled, dealt, saved and loaded.
+//
+// A deck is saved in its text form: Cards written as "<value> of <suit>" and separated by "|",
+// such as "A of Spade|2 of Spade|3 of Spade".
+package cards
+
+// Imports
+// *******
+import (
+	"fmt"
+	"strings"
+)
+
+// Deck Composition
+// ****************
+
+// Suits of a deck, in the order of New().
+var suits = [4]string{"Spade", "Diamond", "Heart", "Club"}
+
+// Values of a deck, in the order of New(), from the Ace (rank 1) to the King (rank 13).
+var values = [13]string{"A", "2", "3", "4", "5", "6", "7", "8", "9", "10", "J", "Q", "K"}
+
+// Suits returns the suits of a deck, in the order of New(). The array is a copy: Changing it changes no deck.
+func Suits() [4]string {
+	return suits
+}
+
+// Values returns the values of a deck, in the order of New(), from the Ace (rank 1) to the King (rank 13).
+// The array is a copy: Changing it changes no deck.
+func Values() [13]string {
+	return values
+}
+
+// Type Declaration
+// ****************
+
+// A Card is a playing card, such as the "A of Spade".
+type Card struct {
+	Value string
+	Suit  string
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// ParseCard parses the text form of a card, "<value> of <suit>".
+// Both the value and the suit must belong to the deck.
+func ParseCard(s string) (Card, error) {
+	// Split on the " of " separator
+	value, suit, found := strings.Cut(s, " of ")
+	if !found {
+		return Card{}, fmt.Errorf("invalid card %q: expected \"<value> of <suit>\"", s)
+	}
+
+	c := Card{Value: value, Suit: suit}
+
+	// Make sure that both parts are known to the deck
+	if c.Rank() == 0 {
+		return Card{}, fmt.Errorf("invalid card %q: unknown value %q", s, value)
+	}
+	if c.SuitIndex() < 0 {
+		return Card{}, fmt.Errorf("invalid card %q: unknown suit %q", s, suit)
+	}
+
+	return c, nil
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// String returns the text form of the card, "<value> of <suit>".
+func (c Card) String() string {
+	return fmt.Sprintf("%s of %s", c.Value, c.Suit)
+}
+
+// Rank returns the rank of the card, from 1 (Ace) to 13 (King), or 0 for an unknown value.
+func (c Card) Rank() int {
+	for i, value := range values {
+		if value == c.Value {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// SuitIndex returns the position of the card's suit in Suits(), or -1 for an unknown suit.
+func (c Card) SuitIndex() int {
+	for i, suit := range suits {
+		if suit == c.Suit {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsRed tells if the card is a Diamond or a Heart.
+func (c Card) IsRed() bool {
+	return c.Suit == "Diamond" || c.Suit == "Heart"
+}
+
+// Index returns the position of the card in a new deck, from 0 (A of Spade) to 51 (K of Club).
+func (c Card) Index() int {
+	return c.SuitIndex()*len(values) + c.Rank() - 1
+}
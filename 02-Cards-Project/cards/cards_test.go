@@ -0,0 +1,142 @@
+/**
+ * @file: Unit tests and examples for the cards library
+ */
+
+// Package
+// *******
+package cards
+
+// Imports
+// *******
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// Test Cases for New() and ParseCard()
+// ************************************
+//   - A new deck should hold 52 cards, from the A of Spade to the K of Club
+//   - Cards should parse back from their text form, and unknown cards should be refused
+//   - Changing the suits and values returned should change no deck
+
+func Test_New(t *testing.T) {
+	// TEST CASE 1: 52 cards in order
+	// ------------------------------
+	d := New()
+	if len(d) != 52 || d[0].String() != "A of Spade" || d[51].String() != "K of Club" {
+		t.Errorf("Test Case 1: Expected 52 cards from A of Spade to K of Club. Got %d cards", len(d))
+	}
+	for i, c := range d {
+		if c.Index() != i {
+			t.Errorf("Test Case 1: Expected %s at index %d. Got %d", c, i, c.Index())
+		}
+	}
+
+	// TEST CASE 2: Parse cards
+	// ------------------------
+	c, err := ParseCard("Q of Heart")
+	if err != nil || c.Rank() != 12 || !c.IsRed() {
+		t.Errorf("Test Case 2: Expected a red Queen. Got %v (%v)", c, err)
+	}
+	for _, s := range []string{"Q of Hearts", "1 of Spade", "Joker"} {
+		if _, err := ParseCard(s); err == nil {
+			t.Errorf("Test Case 2: Expected an error parsing %q", s)
+		}
+	}
+
+	// TEST CASE 3: Suits() and Values() are copies
+	// --------------------------------------------
+	suits, values := Suits(), Values()
+	suits[0], values[0] = "Star", "Z"
+	if d := New(); d[0].String() != "A of Spade" || Suits()[0] != "Spade" || Values()[0] != "A" {
+		t.Errorf("Test Case 3: Expected the deck unchanged. Got %s", d[0])
+	}
+	if _, err := ParseCard("Z of Star"); err == nil {
+		t.Errorf("Test Case 3: Expected an error parsing a card of the changed copies")
+	}
+}
+
+// Test Cases for Shuffle(), Deal(), Save() and Load()
+// ***************************************************
+//   - A seeded shuffle should keep every card and be reproducible
+//   - Decks of 0 or 1 card should shuffle as they are
+//   - Dealing should split the deck, and refuse to deal more cards than the deck holds
+//   - Saving then loading should give back the same deck
+
+func Test_Deck(t *testing.T) {
+	// TEST CASE 1: Shuffle
+	// --------------------
+	d1, d2 := New(), New()
+	d1.Shuffle(rand.New(rand.NewSource(3)), 2)
+	d2.Shuffle(rand.New(rand.NewSource(3)), 2)
+	if !slices.Equal(d1, d2) || slices.Equal(d1, New()) {
+		t.Errorf("Test Case 1: Expected the same shuffled order for the same seed")
+	}
+	sorted := slices.Clone(d1)
+	slices.SortFunc(sorted, func(a, b Card) int { return a.Index() - b.Index() })
+	if !slices.Equal(sorted, New()) {
+		t.Errorf("Test Case 1: Expected the shuffled deck to hold the same 52 cards")
+	}
+	for _, small := range []Deck{{}, New()[:1]} {
+		shuffled := slices.Clone(small)
+		shuffled.Shuffle(rand.New(rand.NewSource(3)), 2)
+		if !slices.Equal(shuffled, small) {
+			t.Errorf("Test Case 1: Expected a deck of %d cards to shuffle as is. Got %v", len(small), shuffled)
+		}
+	}
+
+	// TEST CASE 2: Deal
+	// -----------------
+	hand, rest, err := d1.Deal(5)
+	if err != nil || len(hand) != 5 || !slices.Equal(slices.Concat(hand, rest), d1) {
+		t.Errorf("Test Case 2: Expected a hand of 5 and the rest of the deck (%v)", err)
+	}
+	if _, _, err := d1.Deal(53); !errors.Is(err, ErrDeckTooSmall) {
+		t.Errorf("Test Case 2: Expected an error dealing 53 cards. Got %v", err)
+	}
+
+	// TEST CASE 3: Save and Load
+	// --------------------------
+	filename := filepath.Join(t.TempDir(), "deck.sav")
+	if err := d1.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(filename)
+	if err != nil || !slices.Equal(loaded, d1) {
+		t.Errorf("Test Case 3: Expected the same deck back (%v)", err)
+	}
+	var buf bytes.Buffer
+	d1.WriteTo(&buf)
+	if read, err := Read(&buf); err != nil || !slices.Equal(read, d1) {
+		t.Errorf("Test Case 3: Expected the same deck back from a stream (%v)", err)
+	}
+	if _, err := Parse("A of Spade|Joker"); err == nil {
+		t.Errorf("Test Case 3: Expected an error loading an unknown card")
+	}
+}
+
+// Examples
+// ********
+
+func ExampleNew() {
+	d := New()
+	fmt.Println(len(d), d[0], d[51])
+	// Output: 52 A of Spade K of Club
+}
+
+func ExampleDeck_Deal() {
+	hand, rest, err := New().Deal(3)
+	fmt.Println(hand, len(rest), err)
+	// Output: A of Spade|2 of Spade|3 of Spade 49 <nil>
+}
+
+func ExampleParse() {
+	d, err := Parse("K of Heart|A of Club")
+	fmt.Println(d[0].Rank(), d[1].Suit, err)
+	// Output: 13 Club <nil>
+}
@@ -0,0 +1,179 @@
+/**
+ * @file: Describes what a Deck is in the public cards library, and how it works.
+ */
+
+// Package
+// *******
+package cards
+
+// Imports
+// *******
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strings"
+)
+
+// Constants
+// *********
+
+// Separator between cards in the text form of a deck.
+const Separator = "|"
+
+// Errors
+// ******
+
+// ErrDeckTooSmall is returned when a deck does not hold enough cards for an operation.
+var ErrDeckTooSmall = errors.New("not enough cards in the deck")
+
+// Type Declaration
+// ****************
+
+// A Deck is an ordered pile of cards. The top of the deck is the first card.
+type Deck []Card
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// New returns a new 52-card deck, ordered by suit then by value: From the A of Spade to the K of Club.
+func New() Deck {
+	d := make(Deck, 0, len(suits)*len(values))
+	for _, suit := range suits {
+		for _, value := range values {
+			d = append(d, Card{Value: value, Suit: suit})
+		}
+	}
+	return d
+}
+
+// Parse parses the text form of a deck, as written by Deck.String(). An empty text is an empty deck.
+func Parse(s string) (Deck, error) {
+	return Read(strings.NewReader(s))
+}
+
+// Read reads a deck from r, one card at a time, as written by Deck.WriteTo().
+func Read(r io.Reader) (Deck, error) {
+	d := Deck{}
+	br := bufio.NewReader(r)
+	for {
+		s, err := br.ReadString(Separator[0])
+		last := errors.Is(err, io.EOF)
+		if err != nil && !last {
+			return nil, err
+		}
+		if last && s == "" && len(d) == 0 {
+			// Empty stream: Empty deck
+			return d, nil
+		}
+
+		c, err := ParseCard(strings.TrimSuffix(s, Separator))
+		if err != nil {
+			return nil, fmt.Errorf("card %d: %w", len(d)+1, err)
+		}
+		d = append(d, c)
+		if last {
+			return d, nil
+		}
+	}
+}
+
+// Load loads a deck from a file written by Deck.Save().
+func Load(filename string) (Deck, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// Shuffle shuffles the deck in place with the given Random Number Generator, at least once.
+// A generator built from a fixed seed makes the shuffle reproducible.
+// It runs the Legacy() algorithm, so that seeded deals stay the same: Use ShuffleWith() for another strategy.
+func (d Deck) Shuffle(randGen *rand.Rand, times uint) {
+	Shuffle(d, randGen, times)
+}
+
+// ShuffleWith shuffles the deck in place with a strategy, at least once, such as
+// Strategies[StrategyFisherYates] for a fair shuffle. The same seed gives another order than Shuffle().
+func (d Deck) ShuffleWith(strategy Strategy, randGen *rand.Rand, times uint) {
+	for range max(times, 1) {
+		strategy(d, randGen)
+	}
+}
+
+// Deal splits the deck into a hand of handSize cards and the remaining deck.
+// Both share the memory of the deck.
+func (d Deck) Deal(handSize int) (Deck, Deck, error) {
+	return Deal(d, handSize)
+}
+
+// Strings returns the text form of each card.
+func (d Deck) Strings() []string {
+	strs := make([]string, 0, len(d))
+	for _, c := range d {
+		strs = append(strs, c.String())
+	}
+	return strs
+}
+
+// String returns the text form of the deck: Its cards separated by "|".
+func (d Deck) String() string {
+	return strings.Join(d.Strings(), Separator)
+}
+
+// WriteTo writes the text form of the deck to w, one card at a time. It implements io.WriterTo.
+func (d Deck) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	var n int64
+	for i, c := range d {
+		if i > 0 {
+			written, err := bw.WriteString(Separator)
+			n += int64(written)
+			if err != nil {
+				return n, err
+			}
+		}
+		written, err := bw.WriteString(c.String())
+		n += int64(written)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, bw.Flush()
+}
+
+// Save saves the text form of the deck to a file.
+func (d Deck) Save(filename string) error {
+	return os.WriteFile(filename, []byte(d.String()), 0o666)
+}
+
+// Helper Functions
+// ****************
+
+// Shuffle shuffles any slice of cards in place with the given Random Number Generator, at least once.
+// Each pass is the Legacy() algorithm: It is biased, but seeded deals and replays depend on its order.
+func Shuffle[T any](cards []T, randGen *rand.Rand, times uint) {
+	// Suffle whatever times was passed in: At least once
+	if times == 0 {
+		times = 1
+	}
+	for range times {
+		Legacy(cards, randGen)
+	}
+}
+
+// Deal splits any slice of cards into a hand of handSize cards and the remaining cards.
+func Deal[S ~[]T, T any](cards S, handSize int) (S, S, error) {
+	if handSize < 0 || handSize > len(cards) {
+		return nil, cards, fmt.Errorf("%w: cannot deal %d from %d", ErrDeckTooSmall, handSize, len(cards))
+	}
+	return cards[:handSize], cards[handSize:], nil
+}
@@ -0,0 +1,300 @@
+/**
+ * @file: Describes the probability and odds calculator for partial decks.
+ *
+ * Odds are computed from what is left in the deck:
+ *   - Exactly, by enumerating every possible draw, when there are few enough of them.
+ *   - By Monte Carlo sampling otherwise.
+ */
+
+// Package
+// *******
+package cards
+
+// Imports
+// *******
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"slices"
+)
+
+// Constants
+// *********
+
+const (
+	// Default number of draws that a calculator enumerates before it switches to sampling
+	DefaultMaxCombinations = 250_000
+	// Default number of Monte Carlo trials
+	DefaultTrials = 100_000
+	// Total the dealer stands on in Blackjack
+	dealerStand = 17
+)
+
+// Errors
+// ******
+
+// ErrInvalidOdds is returned when the question asked to the calculator does not make sense for the deck.
+var ErrInvalidOdds = errors.New("invalid odds question")
+
+// Type Declaration
+// ****************
+
+// Odds is the probability of an outcome, and how it was computed.
+type Odds struct {
+	// Probability of the outcome, in [0, 1]
+	Probability float64
+	// True if every possible draw was enumerated, false if sampled
+	Exact bool
+	// Number of draws enumerated or sampled
+	Samples int
+}
+
+// A Calculator computes the odds of outcomes from partial decks.
+type Calculator struct {
+	// Largest number of draws enumerated exactly
+	MaxCombinations int
+	// Number of Monte Carlo trials beyond MaxCombinations
+	Trials int
+	// Random Number Generator used for Monte Carlo trials
+	Rand *rand.Rand
+}
+
+// An Event tells if an outcome happened, given the known cards followed by the drawn cards.
+type Event func(cards Deck) bool
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// NewCalculator returns a calculator with the default limits, sampling with the given Random Number Generator.
+func NewCalculator(randGen *rand.Rand) *Calculator {
+	return &Calculator{MaxCombinations: DefaultMaxCombinations, Trials: DefaultTrials, Rand: randGen}
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// Outcome computes the odds that the event happens once draws more cards come out of the remaining deck,
+// given the known cards. Draws are enumerated when there are at most MaxCombinations of them, sampled otherwise.
+// A single possible draw, such as drawing no card, is always enumerated: Even a zero Calculator answers it,
+// with a probability of 1 or 0.
+func (calc *Calculator) Outcome(known Deck, remaining Deck, draws int, event Event) (Odds, error) {
+	if draws < 0 || draws > len(remaining) {
+		return Odds{}, fmt.Errorf("%w: cannot draw %d from %d", ErrInvalidOdds, draws, len(remaining))
+	}
+
+	combinations := binomial(len(remaining), draws)
+	if combinations.IsInt64() && combinations.Int64() <= int64(max(calc.MaxCombinations, 1)) {
+		return enumerate(known, remaining, draws, event), nil
+	}
+	if calc.Rand == nil || calc.Trials <= 0 {
+		return Odds{}, fmt.Errorf("%w: %s draws to enumerate, and no sampling configured", ErrInvalidOdds, combinations)
+	}
+	return calc.sample(known, remaining, draws, event), nil
+}
+
+// Flush computes the odds of holding five cards of the same suit once draws more cards are out.
+// In Texas Hold'em after the flop, known is the hand and the board, and draws is 2 to reach the river.
+func (calc *Calculator) Flush(known Deck, remaining Deck, draws int) (Odds, error) {
+	return calc.Outcome(known, remaining, draws, IsFlush)
+}
+
+// Straight computes the odds of holding five cards of consecutive ranks once draws more cards are out.
+func (calc *Calculator) Straight(known Deck, remaining Deck, draws int) (Odds, error) {
+	return calc.Outcome(known, remaining, draws, IsStraight)
+}
+
+// sample estimates the odds with Monte Carlo trials: Each trial draws at random from the remaining deck.
+func (calc *Calculator) sample(known Deck, remaining Deck, draws int, event Event) Odds {
+	pool := slices.Clone(remaining)
+	cards := make(Deck, len(known), len(known)+draws)
+	copy(cards, known)
+
+	hits := 0
+	for range calc.Trials {
+		// Partial Fisher-Yates: The first draws cards of the pool are a uniform random draw
+		for i := range draws {
+			j := i + calc.Rand.Intn(len(pool)-i)
+			pool[i], pool[j] = pool[j], pool[i]
+		}
+		if event(append(cards[:len(known)], pool[:draws]...)) {
+			hits++
+		}
+	}
+	return Odds{Probability: float64(hits) / float64(calc.Trials), Samples: calc.Trials}
+}
+
+// Helper Functions
+// ****************
+
+// AtLeast returns the exact probability of drawing at least k successes in draws cards from a population
+// holding successes cards of interest: The upper tail of the hypergeometric distribution.
+func AtLeast(population int, successes int, draws int, k int) (float64, error) {
+	if population < 0 || successes < 0 || successes > population || draws < 0 || draws > population {
+		return 0, fmt.Errorf("%w: %d successes and %d draws in %d cards", ErrInvalidOdds, successes, draws, population)
+	}
+
+	// P(X >= k) = sum over i >= k of C(successes, i) * C(population - successes, draws - i) / C(population, draws)
+	favorable := new(big.Int)
+	for i := max(k, 0); i <= min(successes, draws); i++ {
+		favorable.Add(favorable, new(big.Int).Mul(binomial(successes, i), binomial(population-successes, draws-i)))
+	}
+	p, _ := new(big.Rat).SetFrac(favorable, binomial(population, draws)).Float64()
+	return p, nil
+}
+
+// SuitAtLeast returns the exact probability of drawing at least k cards of the suit in draws cards
+// from the remaining deck.
+func SuitAtLeast(remaining Deck, suit string, draws int, k int) (float64, error) {
+	if !slices.Contains(suits[:], suit) {
+		return 0, fmt.Errorf("%w: unknown suit %q", ErrInvalidOdds, suit)
+	}
+	inSuit := 0
+	for _, c := range remaining {
+		if c.Suit == suit {
+			inSuit++
+		}
+	}
+	return AtLeast(len(remaining), inSuit, draws, k)
+}
+
+// DealerBust returns the exact probability that a Blackjack dealer showing upCard goes over 21,
+// drawing the hole card and every hit from the remaining deck. The dealer hits below 17 and stands
+// on every 17, soft or hard.
+func DealerBust(upCard Card, remaining Deck) (float64, error) {
+	if upCard.Rank() == 0 {
+		return 0, fmt.Errorf("%w: unknown up card %v", ErrInvalidOdds, upCard)
+	}
+	if len(remaining) == 0 {
+		return 0, fmt.Errorf("%w: no hole card left to draw", ErrInvalidOdds)
+	}
+
+	// Only the Blackjack value of each card matters: Count them from 1 (Ace) to 10 (10 and faces)
+	var counts [11]int
+	for _, c := range remaining {
+		counts[blackjackValue(c)]++
+	}
+	total, soft := dealerAdd(0, false, blackjackValue(upCard))
+	return dealerBust(total, soft, &counts, len(remaining), true), nil
+}
+
+// IsFlush tells if the cards hold at least five cards of the same suit.
+func IsFlush(cards Deck) bool {
+	var bySuit [len(suits)]int
+	for _, c := range cards {
+		if i := c.SuitIndex(); i >= 0 {
+			bySuit[i]++
+			if bySuit[i] >= 5 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsStraight tells if the cards hold five cards of consecutive ranks. The Ace plays low (A-2-3-4-5) or high (10-J-Q-K-A).
+func IsStraight(cards Deck) bool {
+	// has[1..13] by rank, and has[14] for the Ace played high
+	var has [len(values) + 2]bool
+	for _, c := range cards {
+		if r := c.Rank(); r > 0 {
+			has[r] = true
+			if r == 1 {
+				has[len(values)+1] = true
+			}
+		}
+	}
+	run := 0
+	for _, present := range has[1:] {
+		if !present {
+			run = 0
+			continue
+		}
+		if run++; run >= 5 {
+			return true
+		}
+	}
+	return false
+}
+
+// enumerate computes the exact odds by going through every combination of draws cards.
+func enumerate(known Deck, remaining Deck, draws int, event Event) Odds {
+	cards := make(Deck, len(known)+draws)
+	copy(cards, known)
+	hits, total := 0, 0
+
+	var choose func(next int, depth int)
+	choose = func(next int, depth int) {
+		if depth == draws {
+			total++
+			if event(cards) {
+				hits++
+			}
+			return
+		}
+		// Leave enough cards for the remaining depths
+		for i := next; i <= len(remaining)-(draws-depth); i++ {
+			cards[len(known)+depth] = remaining[i]
+			choose(i+1, depth+1)
+		}
+	}
+	choose(0, 0)
+
+	return Odds{Probability: float64(hits) / float64(total), Exact: true, Samples: total}
+}
+
+// binomial returns the number of ways to choose k items among n, or 0 when k is out of [0, n].
+func binomial(n int, k int) *big.Int {
+	if k < 0 || k > n {
+		return new(big.Int)
+	}
+	return new(big.Int).Binomial(int64(n), int64(k))
+}
+
+// blackjackValue returns the Blackjack value of a card: 1 for the Ace, 10 for the 10 and the faces.
+func blackjackValue(c Card) int {
+	return min(c.Rank(), 10)
+}
+
+// dealerAdd adds a card value to a dealer total. A soft total counts one Ace as 11.
+func dealerAdd(total int, soft bool, value int) (int, bool) {
+	total += value
+	if value == 1 && total+10 <= 21 && !soft {
+		// Count this Ace as 11
+		return total + 10, true
+	}
+	if soft && total > 21 {
+		// The Ace counted as 11 now counts as 1
+		return total - 10, false
+	}
+	return total, soft
+}
+
+// dealerBust returns the probability that the dealer busts from the given total, drawing from the counts.
+// The first draw is the hole card: The dealer always takes it, whatever the total.
+func dealerBust(total int, soft bool, counts *[11]int, left int, hole bool) float64 {
+	if total > 21 {
+		return 1
+	}
+	if !hole && total >= dealerStand {
+		return 0
+	}
+	if left == 0 {
+		// The deck ran out: The dealer stands
+		return 0
+	}
+
+	p := 0.0
+	for value := 1; value <= 10; value++ {
+		if counts[value] == 0 {
+			continue
+		}
+		weight := float64(counts[value]) / float64(left)
+		counts[value]--
+		next, nextSoft := dealerAdd(total, soft, value)
+		p += weight * dealerBust(next, nextSoft, counts, left-1, false)
+		counts[value]++
+	}
+	return p
+}
@@ -0,0 +1,163 @@
+/**
+ * @file: Unit tests for the probability and odds calculator
+ */
+
+// Package
+// *******
+package cards
+
+// Imports
+// *******
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// Test Helpers
+// ************
+
+// without returns the deck without the given cards.
+func without(d Deck, cards ...string) Deck {
+	return slices.DeleteFunc(slices.Clone(d), func(c Card) bool { return slices.Contains(cards, c.String()) })
+}
+
+// mustParse parses a deck, failing the test on error.
+func mustParse(t *testing.T, s string) Deck {
+	t.Helper()
+	d, err := Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// near tells if two probabilities are within tolerance.
+func near(a float64, b float64, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+// Test Cases for AtLeast() and SuitAtLeast()
+// ******************************************
+//   - Hypergeometric tails should match their closed forms
+//   - Impossible questions should be refused
+
+func Test_AtLeast(t *testing.T) {
+	// TEST CASE 1: At least one Ace in 5 cards: 1 - C(48,5)/C(52,5)
+	// -------------------------------------------------------------
+	p, err := AtLeast(52, 4, 5, 1)
+	if err != nil || !near(p, 1-1712304.0/2598960.0, 1e-12) {
+		t.Errorf("Test Case 1: Expected %f. Got %f (%v)", 1-1712304.0/2598960.0, p, err)
+	}
+
+	// TEST CASE 2: At least 2 Hearts in 2 draws with 10 Hearts left in 47 cards: C(10,2)/C(47,2)
+	// ------------------------------------------------------------------------------------------
+	remaining := without(New(), "A of Heart", "K of Heart", "2 of Heart", "7 of Spade", "9 of Club")
+	p, err = SuitAtLeast(remaining, "Heart", 2, 2)
+	if err != nil || !near(p, 45.0/1081.0, 1e-12) {
+		t.Errorf("Test Case 2: Expected %f. Got %f (%v)", 45.0/1081.0, p, err)
+	}
+
+	// TEST CASE 3: Impossible questions
+	// ---------------------------------
+	if _, err := AtLeast(10, 11, 2, 1); !errors.Is(err, ErrInvalidOdds) {
+		t.Errorf("Test Case 3: Expected an error with more successes than cards. Got %v", err)
+	}
+	if _, err := SuitAtLeast(remaining, "Star", 2, 1); !errors.Is(err, ErrInvalidOdds) {
+		t.Errorf("Test Case 3: Expected an error with an unknown suit. Got %v", err)
+	}
+}
+
+// Test Cases for Calculator
+// *************************
+//   - Flush and straight draws should match the count of outs, exactly
+//   - Monte Carlo should agree with the exact odds when enumeration is turned off
+//   - Zero draws should be certain, or impossible, even for a zero Calculator
+
+func Test_Calculator(t *testing.T) {
+	calc := NewCalculator(rand.New(rand.NewSource(1)))
+
+	// TEST CASE 1: Flush draw after the flop: 9 outs, 2 cards to come
+	// ---------------------------------------------------------------
+	known := mustParse(t, "A of Heart|K of Heart|2 of Heart|7 of Heart|9 of Club")
+	remaining := without(New(), known.Strings()...)
+	odds, err := calc.Flush(known, remaining, 2)
+	if err != nil || !odds.Exact || odds.Samples != 1081 || !near(odds.Probability, 1-703.0/1081.0, 1e-12) {
+		t.Errorf("Test Case 1: Expected exactly %f. Got %+v (%v)", 1-703.0/1081.0, odds, err)
+	}
+
+	// TEST CASE 2: Open-ended straight draw: 8 outs, 2 cards to come
+	// --------------------------------------------------------------
+	known = mustParse(t, "5 of Spade|6 of Heart|7 of Club|8 of Diamond|K of Spade")
+	remaining = without(New(), known.Strings()...)
+	odds, err = calc.Straight(known, remaining, 2)
+	if err != nil || !near(odds.Probability, 1-741.0/1081.0, 1e-12) {
+		t.Errorf("Test Case 2: Expected exactly %f. Got %+v (%v)", 1-741.0/1081.0, odds, err)
+	}
+	if !IsStraight(mustParse(t, "A of Spade|2 of Heart|3 of Club|4 of Diamond|5 of Spade")) ||
+		!IsStraight(mustParse(t, "10 of Spade|J of Heart|Q of Club|K of Diamond|A of Spade")) ||
+		IsStraight(mustParse(t, "Q of Spade|K of Heart|A of Club|2 of Diamond|3 of Spade")) {
+		t.Errorf("Test Case 2: Expected the Ace to play low or high, without wrapping around")
+	}
+
+	// TEST CASE 3: Monte Carlo
+	// ------------------------
+	calc.MaxCombinations = 0
+	odds, err = calc.Straight(known, remaining, 2)
+	if err != nil || odds.Exact || odds.Samples != DefaultTrials || !near(odds.Probability, 1-741.0/1081.0, 0.01) {
+		t.Errorf("Test Case 3: Expected about %f. Got %+v (%v)", 1-741.0/1081.0, odds, err)
+	}
+	if _, err := calc.Outcome(known, remaining, 48, IsFlush); !errors.Is(err, ErrInvalidOdds) {
+		t.Errorf("Test Case 3: Expected an error drawing 48 cards from 47. Got %v", err)
+	}
+
+	// TEST CASE 4: Zero draws
+	// -----------------------
+	var zero Calculator
+	flush := mustParse(t, "A of Heart|K of Heart|2 of Heart|7 of Heart|9 of Heart")
+	odds, err = zero.Flush(flush, without(New(), flush.Strings()...), 0)
+	if err != nil || !odds.Exact || odds.Samples != 1 || odds.Probability != 1 {
+		t.Errorf("Test Case 4: Expected a certain flush. Got %+v (%v)", odds, err)
+	}
+	odds, err = zero.Straight(known, remaining, 0)
+	if err != nil || !odds.Exact || odds.Probability != 0 {
+		t.Errorf("Test Case 4: Expected an impossible straight. Got %+v (%v)", odds, err)
+	}
+}
+
+// Test Cases for DealerBust()
+// ***************************
+//   - The dealer should bust about 42% of the time showing a 6, and rarely showing an Ace
+//   - Known decks should give certain outcomes
+
+func Test_DealerBust(t *testing.T) {
+	six := Card{Value: "6", Suit: "Club"}
+	ace := Card{Value: "A", Suit: "Club"}
+
+	// TEST CASE 1: Full decks
+	// -----------------------
+	p, err := DealerBust(six, without(New(), "6 of Club"))
+	if err != nil || !near(p, 0.42, 0.02) {
+		t.Errorf("Test Case 1: Expected about 0.42 showing a 6. Got %f (%v)", p, err)
+	}
+	p, err = DealerBust(ace, without(New(), "A of Club"))
+	if err != nil || !near(p, 0.12, 0.03) {
+		t.Errorf("Test Case 1: Expected about 0.12 showing an Ace. Got %f (%v)", p, err)
+	}
+
+	// TEST CASE 2: Known decks
+	// ------------------------
+	// 6 + 10 = 16: Hit, 26
+	if p, _ := DealerBust(six, mustParse(t, "10 of Spade|J of Heart|Q of Club")); p != 1 {
+		t.Errorf("Test Case 2: Expected a certain bust. Got %f", p)
+	}
+	// 6 + A = soft 17: Stand
+	if p, _ := DealerBust(six, mustParse(t, "A of Spade|A of Heart")); p != 0 {
+		t.Errorf("Test Case 2: Expected the dealer to stand on soft 17. Got %f", p)
+	}
+	if _, err := DealerBust(six, Deck{}); !errors.Is(err, ErrInvalidOdds) {
+		t.Errorf("Test Case 2: Expected an error without a hole card. Got %v", err)
+	}
+}
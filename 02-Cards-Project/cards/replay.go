@@ -0,0 +1,267 @@
+/**
+ * @file: Describes replay files: The record of a Cards game, step by step.
+ *
+ * A replay holds the seed, the initial deck order and every action taken on the table.
+ * Replaying the actions rebuilds the table state at any step. Since every shuffle draws from
+ * the Random Number Generator of the seed, a replay can be checked against its seed.
+ */
+
+// Package
+// *******
+package cards
+
+// Imports
+// *******
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Constants
+// *********
+
+// Format version of the replay files written by Replay.Save().
+const ReplayVersion = 1
+
+// Kinds of actions recorded in a replay.
+const (
+	// A player is dealt Count cards from the top of the deck
+	ActionDeal = "deal"
+	// A player draws the top card of the deck
+	ActionDraw = "draw"
+	// A player plays Card from their hand onto the discard pile
+	ActionPlay = "play"
+	// The deck is shuffled with the Random Number Generator of the seed, giving Deck
+	ActionShuffle = "shuffle"
+)
+
+// Errors
+// ******
+
+// ErrReplayInconsistent is returned when a replay does not match its seed, or has an impossible action.
+var ErrReplayInconsistent = errors.New("replay is inconsistent")
+
+// Type Declaration
+// ****************
+
+// A Replay is the record of a game: Its seed, its initial deck order and its actions.
+type Replay struct {
+	Version int      `json:"version"`
+	Seed    int64    `json:"seed"`
+	Players int      `json:"players"`
+	Deck    string   `json:"deck"`
+	Actions []Action `json:"actions"`
+}
+
+// An Action is one step of a game.
+type Action struct {
+	Kind   string `json:"kind"`
+	Player int    `json:"player,omitempty"`
+	Count  int    `json:"count,omitempty"`
+	Card   string `json:"card,omitempty"`
+	Deck   string `json:"deck,omitempty"`
+}
+
+// A Table is the state of a game at one step: The deck, each player's hand and the discard pile.
+type Table struct {
+	Deck    Deck
+	Hands   []Deck
+	Discard Deck
+}
+
+// A Recorder plays a game on a table and records each action into a replay.
+type Recorder struct {
+	Replay  Replay
+	Table   Table
+	randGen *rand.Rand
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// NewRecorder starts recording a game: A new deck is shuffled with the seed, and the players have empty hands.
+func NewRecorder(seed int64, players int) *Recorder {
+	randGen := rand.New(rand.NewSource(seed))
+	d := New()
+	d.Shuffle(randGen, 1)
+	return &Recorder{
+		Replay:  Replay{Version: ReplayVersion, Seed: seed, Players: players, Deck: d.String(), Actions: []Action{}},
+		Table:   Table{Deck: d, Hands: make([]Deck, players), Discard: Deck{}},
+		randGen: randGen,
+	}
+}
+
+// LoadReplay loads a replay from a file written by Replay.Save().
+func LoadReplay(filename string) (Replay, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return Replay{}, err
+	}
+	var r Replay
+	if err := json.Unmarshal(b, &r); err != nil {
+		return Replay{}, fmt.Errorf("%w: %v", ErrReplayInconsistent, err)
+	}
+	if r.Version != ReplayVersion {
+		return Replay{}, fmt.Errorf("%w: version %d, expected %d", ErrReplayInconsistent, r.Version, ReplayVersion)
+	}
+	return r, nil
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// Deal deals count cards from the top of the deck to the player, and records it.
+func (rec *Recorder) Deal(player int, count int) error {
+	return rec.record(Action{Kind: ActionDeal, Player: player, Count: count})
+}
+
+// Draw draws the top card of the deck for the player, and records it.
+func (rec *Recorder) Draw(player int) error {
+	return rec.record(Action{Kind: ActionDraw, Player: player})
+}
+
+// Play plays a card from the player's hand onto the discard pile, and records it.
+func (rec *Recorder) Play(player int, c Card) error {
+	return rec.record(Action{Kind: ActionPlay, Player: player, Card: c.String()})
+}
+
+// Shuffle shuffles the deck with the Random Number Generator of the seed, and records the new order.
+func (rec *Recorder) Shuffle() error {
+	return rec.record(Action{Kind: ActionShuffle})
+}
+
+// record applies an action to the table, then adds it to the replay.
+func (rec *Recorder) record(a Action) error {
+	if a.Kind == ActionShuffle {
+		rec.Table.Deck.Shuffle(rec.randGen, 1)
+		a.Deck = rec.Table.Deck.String()
+	} else if err := rec.Table.apply(a); err != nil {
+		return err
+	}
+	rec.Replay.Actions = append(rec.Replay.Actions, a)
+	return nil
+}
+
+// Save saves the replay to a file, as JSON.
+func (r Replay) Save(filename string) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(b, '\n'), 0o666)
+}
+
+// States replays the game and returns the table at each step: Before any action, then after each one.
+// It fails with ErrReplayInconsistent if the initial deck or a shuffle does not match the seed,
+// or if an action is impossible.
+func (r Replay) States() ([]Table, error) {
+	if r.Players < 1 {
+		return nil, fmt.Errorf("%w: %d players", ErrReplayInconsistent, r.Players)
+	}
+
+	// The initial deck must be the one shuffled from the seed
+	randGen := rand.New(rand.NewSource(r.Seed))
+	expected := New()
+	expected.Shuffle(randGen, 1)
+	if r.Deck != expected.String() {
+		return nil, fmt.Errorf("%w: the initial deck does not match seed %d", ErrReplayInconsistent, r.Seed)
+	}
+
+	table := Table{Deck: expected, Hands: make([]Deck, r.Players), Discard: Deck{}}
+	states := []Table{table.clone()}
+	for i, a := range r.Actions {
+		if a.Kind == ActionShuffle {
+			table.Deck.Shuffle(randGen, 1)
+			if table.Deck.String() != a.Deck {
+				return states, fmt.Errorf("%w: step %d: the shuffle does not match seed %d", ErrReplayInconsistent, i+1, r.Seed)
+			}
+		} else if err := table.apply(a); err != nil {
+			return states, fmt.Errorf("step %d: %w", i+1, err)
+		}
+		states = append(states, table.clone())
+	}
+	return states, nil
+}
+
+// Verify checks that the replay is consistent with its seed, and that every action is possible.
+func (r Replay) Verify() error {
+	_, err := r.States()
+	return err
+}
+
+// String describes the action, such as "Player 2 plays Q of Heart".
+func (a Action) String() string {
+	switch a.Kind {
+	case ActionDeal:
+		return fmt.Sprintf("Player %d is dealt %d cards", a.Player+1, a.Count)
+	case ActionDraw:
+		return fmt.Sprintf("Player %d draws", a.Player+1)
+	case ActionPlay:
+		return fmt.Sprintf("Player %d plays %s", a.Player+1, a.Card)
+	case ActionShuffle:
+		return "The deck is shuffled"
+	}
+	return fmt.Sprintf("Unknown action %q", a.Kind)
+}
+
+// String renders the table: The deck, each hand and the discard pile, one per line.
+func (t Table) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Deck (%d): %s\n", len(t.Deck), t.Deck)
+	for i, hand := range t.Hands {
+		fmt.Fprintf(&sb, "Player %d (%d): %s\n", i+1, len(hand), hand)
+	}
+	fmt.Fprintf(&sb, "Discard (%d): %s\n", len(t.Discard), t.Discard)
+	return sb.String()
+}
+
+// apply plays an action other than a shuffle on the table.
+func (t *Table) apply(a Action) error {
+	if a.Player < 0 || a.Player >= len(t.Hands) {
+		return fmt.Errorf("%w: %s: no such player", ErrReplayInconsistent, a)
+	}
+
+	switch a.Kind {
+	case ActionDeal, ActionDraw:
+		count := a.Count
+		if a.Kind == ActionDraw {
+			count = 1
+		}
+		hand, rest, err := Deal(t.Deck, count)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrReplayInconsistent, a, err)
+		}
+		t.Hands[a.Player] = append(t.Hands[a.Player], hand...)
+		t.Deck = rest
+
+	case ActionPlay:
+		c, err := ParseCard(a.Card)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrReplayInconsistent, a, err)
+		}
+		i := slices.Index(t.Hands[a.Player], c)
+		if i < 0 {
+			return fmt.Errorf("%w: %s: the card is not in the hand", ErrReplayInconsistent, a)
+		}
+		t.Hands[a.Player] = slices.Delete(t.Hands[a.Player], i, i+1)
+		t.Discard = append(t.Discard, c)
+
+	default:
+		return fmt.Errorf("%w: unknown action %q", ErrReplayInconsistent, a.Kind)
+	}
+	return nil
+}
+
+// clone returns a copy of the table that later actions cannot change.
+func (t Table) clone() Table {
+	hands := make([]Deck, len(t.Hands))
+	for i, hand := range t.Hands {
+		hands[i] = slices.Clone(hand)
+	}
+	return Table{Deck: slices.Clone(t.Deck), Hands: hands, Discard: slices.Clone(t.Discard)}
+}
@@ -0,0 +1,109 @@
+/**
+ * @file: Unit tests for replay files
+ */
+
+// Package
+// *******
+package cards
+
+// Imports
+// *******
+import (
+	"errors"
+	"math/rand"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Test Helpers
+// ************
+
+// recordGame records a short game with a shuffle in the middle.
+func recordGame(t *testing.T, seed int64) *Recorder {
+	t.Helper()
+	rec := NewRecorder(seed, 2)
+	steps := []error{rec.Deal(0, 3), rec.Deal(1, 3), rec.Shuffle()}
+	steps = append(steps, rec.Play(1, rec.Table.Hands[1][2]), rec.Draw(1))
+	for _, err := range steps {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rec
+}
+
+// Test Cases for Recorder and Replay
+// **********************************
+//   - Replaying a saved game should rebuild the table at every step
+//   - Replays that do not match their seed, or hold impossible actions, should be refused
+//   - Replays should be shuffled with the legacy algorithm of Shuffle(), and unknown versions refused
+
+func Test_Replay(t *testing.T) {
+	rec := recordGame(t, 7)
+
+	// TEST CASE 1: Save, load and replay
+	// ----------------------------------
+	filename := filepath.Join(t.TempDir(), "game.replay.json")
+	if err := rec.Replay.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+	r, err := LoadReplay(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	states, err := r.States()
+	if err != nil || len(states) != 6 {
+		t.Fatalf("Test Case 1: Expected 6 states. Got %d (%v)", len(states), err)
+	}
+	last := states[5]
+	if !slices.Equal(last.Deck, rec.Table.Deck) || !slices.Equal(last.Hands[1], rec.Table.Hands[1]) || len(last.Discard) != 1 {
+		t.Errorf("Test Case 1: Expected the last state to be the recorded table.\n%s", last)
+	}
+	if len(states[0].Deck) != 52 || len(states[1].Hands[0]) != 3 || len(states[1].Deck) != 49 {
+		t.Errorf("Test Case 1: Expected earlier states to be kept as they were")
+	}
+	if !strings.Contains(last.String(), "Player 2 (3): ") {
+		t.Errorf("Test Case 1: Expected the rendered table to show the hands.\n%s", last)
+	}
+
+	// TEST CASE 2: Another seed does not match
+	// ----------------------------------------
+	wrongSeed := r
+	wrongSeed.Seed = 8
+	if err := wrongSeed.Verify(); !errors.Is(err, ErrReplayInconsistent) {
+		t.Errorf("Test Case 2: Expected an inconsistent replay with another seed. Got %v", err)
+	}
+
+	// TEST CASE 3: An edited shuffle or an impossible play
+	// ----------------------------------------------------
+	edited := r
+	edited.Actions = slices.Clone(r.Actions)
+	edited.Actions[2].Deck = New().String()[:len(edited.Actions[2].Deck)]
+	if err := edited.Verify(); !errors.Is(err, ErrReplayInconsistent) {
+		t.Errorf("Test Case 3: Expected an edited shuffle to be refused. Got %v", err)
+	}
+	edited.Actions = append(slices.Clone(r.Actions), Action{Kind: ActionPlay, Player: 0, Card: rec.Table.Hands[1][0].String()})
+	if states, err := edited.States(); !errors.Is(err, ErrReplayInconsistent) || len(states) != 6 {
+		t.Errorf("Test Case 3: Expected a play of another player's card to be refused after 5 steps. Got %v", err)
+	}
+
+	// TEST CASE 4: Versions
+	// ---------------------
+	d := New()
+	Legacy(d, rand.New(rand.NewSource(7)))
+	if r.Deck != d.String() {
+		t.Errorf("Test Case 4: Expected the initial deck shuffled with the legacy algorithm. Got %s", r.Deck)
+	}
+	for _, version := range []int{0, ReplayVersion + 1} {
+		unknown := r
+		unknown.Version = version
+		if err := unknown.Save(filename); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadReplay(filename); !errors.Is(err, ErrReplayInconsistent) {
+			t.Errorf("Test Case 4: Expected version %d to be refused. Got %v", version, err)
+		}
+	}
+}
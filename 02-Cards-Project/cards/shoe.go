@@ -0,0 +1,175 @@
+/**
+ * @file: Describes deck types that are safe to share between goroutines:
+ *   - Shoe:   A deck behind a mutex. Every draw, deal and shuffle is atomic.
+ *   - Dealer: A goroutine that owns a deck and hands cards out on request, over channels.
+ */
+
+// Package
+// *******
+package cards
+
+// Imports
+// *******
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sync"
+)
+
+// Errors
+// ******
+
+// ErrDealerClosed is returned when asking cards from a dealer that was closed.
+var ErrDealerClosed = errors.New("dealer is closed")
+
+// Type Declaration
+// ****************
+
+// A Shoe is a deck that several goroutines can draw from at the same time.
+// The zero value is an empty shoe.
+type Shoe struct {
+	mu    sync.Mutex
+	cards Deck
+}
+
+// A Dealer is a goroutine that owns a deck and hands cards out on request.
+type Dealer struct {
+	requests chan dealRequest
+	done     chan struct{}
+	once     sync.Once
+}
+
+// dealRequest asks the dealer for count cards. The dealer answers on reply.
+type dealRequest struct {
+	count   int
+	shuffle *rand.Rand
+	reply   chan dealReply
+}
+
+// dealReply holds the cards dealt, or why none were.
+type dealReply struct {
+	cards Deck
+	left  int
+	err   error
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// NewShoe returns a shoe holding the given decks, one after the other. The decks are copied.
+func NewShoe(decks ...Deck) *Shoe {
+	return &Shoe{cards: slices.Concat(decks...)}
+}
+
+// NewDealer starts a dealer goroutine that owns a copy of the deck. Close it once done.
+func NewDealer(d Deck) *Dealer {
+	dealer := &Dealer{requests: make(chan dealRequest), done: make(chan struct{})}
+	go dealer.run(slices.Clone(d))
+	return dealer
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// Draw takes the top card of the shoe.
+func (s *Shoe) Draw() (Card, error) {
+	hand, err := s.Deal(1)
+	if err != nil {
+		return Card{}, err
+	}
+	return hand[0], nil
+}
+
+// Deal takes the top handSize cards of the shoe, all at once: No other goroutine can draw in between.
+func (s *Shoe) Deal(handSize int) (Deck, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hand, rest, err := Deal(s.cards, handSize)
+	if err != nil {
+		return nil, err
+	}
+	s.cards = rest
+	// The hand is a copy: The shoe may reuse its memory when cards are returned
+	return slices.Clone(hand), nil
+}
+
+// Shuffle shuffles the cards left in the shoe with the given Random Number Generator.
+// A shoe down to 0 or 1 card is left as it is.
+func (s *Shoe) Shuffle(randGen *rand.Rand, times uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.cards) < 2 {
+		return
+	}
+	s.cards.Shuffle(randGen, times)
+}
+
+// Return puts cards back at the bottom of the shoe.
+func (s *Shoe) Return(cards ...Card) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cards = append(s.cards, cards...)
+}
+
+// Len returns the number of cards left in the shoe.
+func (s *Shoe) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.cards)
+}
+
+// Deal asks the dealer for the top handSize cards. Returns the cards and the number of cards left.
+func (dealer *Dealer) Deal(handSize int) (Deck, int, error) {
+	reply := dealer.ask(dealRequest{count: handSize})
+	return reply.cards, reply.left, reply.err
+}
+
+// Shuffle asks the dealer to shuffle the cards left with the given Random Number Generator.
+func (dealer *Dealer) Shuffle(randGen *rand.Rand) error {
+	return dealer.ask(dealRequest{shuffle: randGen}).err
+}
+
+// Close stops the dealer goroutine. Later requests fail with ErrDealerClosed.
+func (dealer *Dealer) Close() {
+	dealer.once.Do(func() { close(dealer.done) })
+}
+
+// ask sends a request to the dealer goroutine and waits for its reply.
+func (dealer *Dealer) ask(req dealRequest) dealReply {
+	req.reply = make(chan dealReply, 1)
+	select {
+	case dealer.requests <- req:
+		return <-req.reply
+	case <-dealer.done:
+		return dealReply{err: ErrDealerClosed}
+	}
+}
+
+// run is the dealer goroutine: The only one to ever touch the deck.
+func (dealer *Dealer) run(d Deck) {
+	for {
+		select {
+		case <-dealer.done:
+			return
+		case req := <-dealer.requests:
+			if req.shuffle != nil {
+				// Down to 0 or 1 card: Nothing to shuffle
+				if len(d) > 1 {
+					d.Shuffle(req.shuffle, 1)
+				}
+				req.reply <- dealReply{left: len(d)}
+				continue
+			}
+			hand, rest, err := Deal(d, req.count)
+			if err != nil {
+				req.reply <- dealReply{left: len(d), err: fmt.Errorf("dealer: %w", err)}
+				continue
+			}
+			d = rest
+			req.reply <- dealReply{cards: slices.Clone(hand), left: len(d)}
+		}
+	}
+}
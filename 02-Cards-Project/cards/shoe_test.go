@@ -0,0 +1,162 @@
+/**
+ * @file: Unit tests for the Shoe and the Dealer, meant to run with the race detector:
+ * go test -race ./02-Cards-Project/cards
+ */
+
+// Package
+// *******
+package cards
+
+// Imports
+// *******
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// Test Helpers
+// ************
+
+// Number of goroutines dealing at the same time.
+const dealers = 16
+
+// checkAllDealt fails the test unless every card of the decks was dealt exactly once.
+func checkAllDealt(t *testing.T, hands []Deck, decks int) {
+	t.Helper()
+	seen := map[Card]int{}
+	total := 0
+	for _, hand := range hands {
+		for _, c := range hand {
+			seen[c]++
+			total++
+		}
+	}
+	if total != 52*decks {
+		t.Errorf("Expected %d cards dealt. Got %d", 52*decks, total)
+	}
+	for c, n := range seen {
+		if n != decks {
+			t.Errorf("Expected %s to be dealt %d times. Got %d", c, decks, n)
+		}
+	}
+}
+
+// Test Cases for Shoe
+// *******************
+//   - Goroutines drawing and dealing from one shoe should share out every card exactly once
+//   - Shuffles and returns in between should not lose any card
+//   - A shoe down to its last card should still shuffle
+
+func Test_Shoe(t *testing.T) {
+	// TEST CASE 1: Concurrent draws and deals from a 6-deck shoe
+	// ----------------------------------------------------------
+	shoe := NewShoe(New(), New(), New(), New(), New(), New())
+	shoe.Shuffle(rand.New(rand.NewSource(1)), 1)
+	hands := make([]Deck, dealers)
+	var wg sync.WaitGroup
+	for i := range dealers {
+		wg.Go(func() {
+			for {
+				var hand Deck
+				var err error
+				if i%2 == 0 {
+					var c Card
+					c, err = shoe.Draw()
+					hand = Deck{c}
+				} else {
+					hand, err = shoe.Deal(3)
+					if errors.Is(err, ErrDeckTooSmall) && shoe.Len() > 0 {
+						// Fewer than 3 cards left: Draw them one at a time
+						continue
+					}
+				}
+				if err != nil {
+					return
+				}
+				hands[i] = append(hands[i], hand...)
+			}
+		})
+	}
+	wg.Wait()
+	checkAllDealt(t, hands, 6)
+
+	// TEST CASE 2: Returns and shuffles while dealing
+	// -----------------------------------------------
+	shoe = NewShoe(New())
+	for range dealers {
+		wg.Go(func() {
+			for range 100 {
+				if hand, err := shoe.Deal(2); err == nil {
+					shoe.Return(hand...)
+				}
+			}
+		})
+	}
+	wg.Go(func() {
+		randGen := rand.New(rand.NewSource(2))
+		for range 100 {
+			shoe.Shuffle(randGen, 1)
+		}
+	})
+	wg.Wait()
+	all, _ := shoe.Deal(shoe.Len())
+	checkAllDealt(t, []Deck{all}, 1)
+
+	// TEST CASE 3: Last card
+	// ----------------------
+	shoe = NewShoe(New())
+	shoe.Deal(51)
+	shoe.Shuffle(rand.New(rand.NewSource(3)), 1)
+	if c, err := shoe.Draw(); err != nil || c.String() != "K of Club" {
+		t.Errorf("Test Case 3: Expected the K of Club left. Got %v (%v)", c, err)
+	}
+	shoe.Shuffle(rand.New(rand.NewSource(3)), 1)
+	if shoe.Len() != 0 {
+		t.Errorf("Test Case 3: Expected an empty shoe. Got %d cards", shoe.Len())
+	}
+}
+
+// Test Cases for Dealer
+// *********************
+//   - Goroutines asking one dealer should share out every card exactly once, then still shuffle
+//   - A closed dealer should refuse requests
+
+func Test_Dealer(t *testing.T) {
+	// TEST CASE 1: Concurrent requests
+	// --------------------------------
+	dealer := NewDealer(New())
+	if err := dealer.Shuffle(rand.New(rand.NewSource(3))); err != nil {
+		t.Fatal(err)
+	}
+	hands := make([]Deck, dealers)
+	var wg sync.WaitGroup
+	for i := range dealers {
+		wg.Go(func() {
+			for {
+				hand, left, err := dealer.Deal(1)
+				if err != nil {
+					return
+				}
+				hands[i] = append(hands[i], hand...)
+				if left == 0 {
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+	checkAllDealt(t, hands, 1)
+	if err := dealer.Shuffle(rand.New(rand.NewSource(3))); err != nil {
+		t.Errorf("Test Case 1: Expected an empty dealer to shuffle. Got %v", err)
+	}
+
+	// TEST CASE 2: Closed dealer
+	// --------------------------
+	dealer.Close()
+	dealer.Close()
+	if _, _, err := dealer.Deal(1); !errors.Is(err, ErrDealerClosed) {
+		t.Errorf("Test Case 2: Expected a closed dealer error. Got %v", err)
+	}
+}
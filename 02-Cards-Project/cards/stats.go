@@ -0,0 +1,254 @@
+/**
+ * @file: Describes shuffle strategies and the statistics that audit their randomness.
+ *
+ * A fair shuffle gives every order of the deck the same chance. Over many runs, the report checks:
+ *   - Position bias: Each card should land in each position 1 time in 52.
+ *   - Adjacency: Cards next to each other before the shuffle should rarely stay next to each other.
+ *   - Rising sequences: A fair shuffle has (n+1)/2 of them on average. Too few betray riffles.
+ *   - Chi-square: How likely the position counts are for a fair shuffle (the p-value).
+ */
+
+// Package
+// *******
+package cards
+
+// Imports
+// *******
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+)
+
+// Constants
+// *********
+
+// Names of the shuffle strategies.
+const (
+	// The algorithm of Shuffle(): Swaps each card with one at random in [0, n-2]
+	StrategyLegacy = "legacy"
+	// Fisher-Yates: Swaps each card with one at random among the cards not placed yet
+	StrategyFisherYates = "fisher-yates"
+	// Seven riffle shuffles, as done by hand (Gilbert-Shannon-Reeds model)
+	StrategyRiffle = "riffle"
+)
+
+// A p-value below this is reported as a failure: The shuffle is very unlikely to be fair.
+const Significance = 0.01
+
+// Type Declaration
+// ****************
+
+// A Strategy shuffles a deck in place with the given Random Number Generator.
+type Strategy func(d Deck, randGen *rand.Rand)
+
+// Strategies to compare, by name.
+var Strategies = map[string]Strategy{
+	StrategyLegacy:      func(d Deck, randGen *rand.Rand) { Legacy(d, randGen) },
+	StrategyFisherYates: func(d Deck, randGen *rand.Rand) { FisherYates(d, randGen) },
+	StrategyRiffle: func(d Deck, randGen *rand.Rand) {
+		for range 7 {
+			Riffle(d, randGen)
+		}
+	},
+}
+
+// A ShuffleReport holds the randomness statistics of a strategy over many runs.
+type ShuffleReport struct {
+	Strategy string `json:"strategy"`
+	Runs     int    `json:"runs"`
+
+	// Chi-square test of the card-by-position counts
+	ChiSquare        float64 `json:"chi_square"`
+	DegreesOfFreedom int     `json:"degrees_of_freedom"`
+	PValue           float64 `json:"p_value"`
+
+	// Position bias of each card: The largest relative gap between its count in a position and the expected count
+	PositionBias map[string]float64 `json:"position_bias"`
+	// Card with the largest position bias, and that bias
+	WorstCard string  `json:"worst_card"`
+	WorstBias float64 `json:"worst_bias"`
+
+	// Originally adjacent pairs still adjacent, in the same order, per shuffle: Observed and expected
+	Adjacency         float64 `json:"adjacency"`
+	ExpectedAdjacency float64 `json:"expected_adjacency"`
+
+	// Rising sequences per shuffle: Observed and expected
+	RisingSequences         float64 `json:"rising_sequences"`
+	ExpectedRisingSequences float64 `json:"expected_rising_sequences"`
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// NewShuffleReport shuffles a new deck runs times with the strategy, and computes its statistics.
+func NewShuffleReport(name string, strategy Strategy, runs int, randGen *rand.Rand) (ShuffleReport, error) {
+	if runs < 1 {
+		return ShuffleReport{}, fmt.Errorf("cannot report on %d runs", runs)
+	}
+
+	original := New()
+	n := len(original)
+	counts := make([][]int, n)
+	for i := range counts {
+		counts[i] = make([]int, n)
+	}
+	adjacent, rising := 0, 0
+
+	// position[i] is where the card originally at i lands
+	position := make([]int, n)
+	for range runs {
+		d := slices.Clone(original)
+		strategy(d, randGen)
+		for pos, c := range d {
+			position[c.Index()] = pos
+			counts[c.Index()][pos]++
+		}
+		for i := range n - 1 {
+			if position[i+1] == position[i]+1 {
+				adjacent++
+			}
+		}
+		rising += risingSequences(position)
+	}
+
+	// Chi-square over the card-by-position table: Each cell expects runs/n
+	expected := float64(runs) / float64(n)
+	report := ShuffleReport{
+		Strategy:                name,
+		Runs:                    runs,
+		DegreesOfFreedom:        (n - 1) * (n - 1),
+		PositionBias:            map[string]float64{},
+		Adjacency:               float64(adjacent) / float64(runs),
+		ExpectedAdjacency:       float64(n-1) / float64(n),
+		RisingSequences:         float64(rising) / float64(runs),
+		ExpectedRisingSequences: float64(n+1) / 2,
+	}
+	for i, c := range original {
+		bias := 0.0
+		for _, observed := range counts[i] {
+			gap := float64(observed) - expected
+			report.ChiSquare += gap * gap / expected
+			bias = max(bias, math.Abs(gap)/expected)
+		}
+		report.PositionBias[c.String()] = bias
+		if bias > report.WorstBias {
+			report.WorstCard, report.WorstBias = c.String(), bias
+		}
+	}
+	report.PValue = ChiSquarePValue(report.ChiSquare, report.DegreesOfFreedom)
+	return report, nil
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// Fair tells if the p-value is above the significance level: Nothing shows that the shuffle is unfair.
+func (r ShuffleReport) Fair() bool {
+	return r.PValue >= Significance
+}
+
+// Helper Functions
+// ****************
+
+// FisherYates shuffles any slice of cards in place: Every order has the same chance.
+func FisherYates[T any](cards []T, randGen *rand.Rand) {
+	for i := len(cards) - 1; i > 0; i-- {
+		j := randGen.Intn(i + 1)
+		cards[i], cards[j] = cards[j], cards[i]
+	}
+}
+
+// Legacy shuffles any slice of cards in place with the algorithm of Shuffle():
+// Each card is swapped with one at random, never the last. It is biased, but seeded deals depend on it.
+func Legacy[T any](cards []T, randGen *rand.Rand) {
+	// Nothing to shuffle with fewer than 2 cards
+	if len(cards) < 2 {
+		return
+	}
+	for i := range cards {
+		j := randGen.Intn(len(cards) - 1)
+		cards[i], cards[j] = cards[j], cards[i]
+	}
+}
+
+// Riffle riffles any slice of cards once: Cut it in two about the middle, then let the cards drop
+// from each half in proportion to its size (Gilbert-Shannon-Reeds model).
+func Riffle[T any](cards []T, randGen *rand.Rand) {
+	// The cut follows a binomial distribution
+	cut := 0
+	for range cards {
+		cut += randGen.Intn(2)
+	}
+	left, right := slices.Clone(cards[:cut]), slices.Clone(cards[cut:])
+
+	for i := range cards {
+		if randGen.Intn(len(left)+len(right)) < len(left) {
+			cards[i], left = left[0], left[1:]
+		} else {
+			cards[i], right = right[0], right[1:]
+		}
+	}
+}
+
+// ChiSquarePValue returns the probability that a chi-square statistic with the degrees of freedom
+// is at least x: The upper regularized incomplete gamma function Q(df/2, x/2).
+func ChiSquarePValue(x float64, df int) float64 {
+	if x <= 0 {
+		return 1
+	}
+	a, z := float64(df)/2, x/2
+	lgamma, _ := math.Lgamma(a)
+	prefix := math.Exp(a*math.Log(z) - z - lgamma)
+
+	if z < a+1 {
+		// Series for the lower function P(a, z), then Q = 1 - P
+		sum, term := 1/a, 1/a
+		for n := 1.0; n < 100_000; n++ {
+			term *= z / (a + n)
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-15 {
+				break
+			}
+		}
+		return max(0, 1-sum*prefix)
+	}
+
+	// Continued fraction for Q(a, z) (modified Lentz's method)
+	const tiny = 1e-300
+	b := z + 1 - a
+	c, d := 1/tiny, 1/b
+	h := d
+	for i := 1.0; i < 100_000; i++ {
+		an := -i * (i - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return prefix * h
+}
+
+// risingSequences counts the rising sequences of a shuffle: Runs of originally consecutive cards
+// that still come in order. position[i] is where the card originally at i lands.
+func risingSequences(position []int) int {
+	sequences := 1
+	for i := 1; i < len(position); i++ {
+		if position[i] < position[i-1] {
+			sequences++
+		}
+	}
+	return sequences
+}
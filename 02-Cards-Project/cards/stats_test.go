@@ -0,0 +1,99 @@
+/**
+ * @file: Unit tests for the shuffle strategies and their randomness statistics
+ */
+
+// Package
+// *******
+package cards
+
+// Imports
+// *******
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// Test Cases for ChiSquarePValue()
+// ********************************
+//   - p-values should match known values of the chi-square distribution
+
+func Test_ChiSquarePValue(t *testing.T) {
+	// TEST CASE 1: Closed forms and tables
+	// ------------------------------------
+	cases := []struct {
+		x        float64
+		df       int
+		expected float64
+	}{
+		{2, 2, math.Exp(-1)},
+		{10, 10, 0.440493},
+		{3.841459, 1, 0.05},
+		{0, 5, 1},
+	}
+	for _, c := range cases {
+		if p := ChiSquarePValue(c.x, c.df); math.Abs(p-c.expected) > 1e-5 {
+			t.Errorf("Test Case 1: Expected p(%g, %d) = %f. Got %f", c.x, c.df, c.expected, p)
+		}
+	}
+}
+
+// Test Cases for NewShuffleReport()
+// *********************************
+//   - Fisher-Yates, and ShuffleWith() with it, should look fair
+//   - The legacy shuffle of Shuffle() should be caught: It never leaves a card in some positions
+//   - Seven riffles should show too few rising sequences
+
+func Test_NewShuffleReport(t *testing.T) {
+	reports := map[string]ShuffleReport{}
+	for _, name := range []string{StrategyLegacy, StrategyFisherYates, StrategyRiffle} {
+		report, err := NewShuffleReport(name, Strategies[name], 10_000, rand.New(rand.NewSource(1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		reports[name] = report
+	}
+
+	// TEST CASE 1: Fisher-Yates
+	// -------------------------
+	fy := reports[StrategyFisherYates]
+	if !fy.Fair() || fy.WorstBias > 0.3 || math.Abs(fy.RisingSequences-fy.ExpectedRisingSequences) > 0.1 {
+		t.Errorf("Test Case 1: Expected Fisher-Yates to look fair. Got %+v", fy)
+	}
+	shuffleWith := func(d Deck, randGen *rand.Rand) { d.ShuffleWith(Strategies[StrategyFisherYates], randGen, 1) }
+	fair, err := NewShuffleReport(StrategyFisherYates, shuffleWith, 10_000, rand.New(rand.NewSource(1)))
+	if err != nil || !reflect.DeepEqual(fair, fy) {
+		t.Errorf("Test Case 1: Expected ShuffleWith() to give the report of Fisher-Yates. Got p-value %g (%v)", fair.PValue, err)
+	}
+
+	// TEST CASE 2: Legacy
+	// -------------------
+	legacy := reports[StrategyLegacy]
+	if legacy.Fair() || legacy.WorstBias < 0.99 {
+		t.Errorf("Test Case 2: Expected the legacy shuffle to be caught. Got p-value %g and worst bias %f", legacy.PValue, legacy.WorstBias)
+	}
+	shuffle, err := NewShuffleReport(StrategyLegacy, func(d Deck, randGen *rand.Rand) { d.Shuffle(randGen, 1) }, 10_000, rand.New(rand.NewSource(1)))
+	if err != nil || !reflect.DeepEqual(shuffle, legacy) {
+		t.Errorf("Test Case 2: Expected Shuffle() to keep the legacy algorithm. Got p-value %g (%v)", shuffle.PValue, err)
+	}
+
+	// TEST CASE 3: Riffles
+	// --------------------
+	riffle := reports[StrategyRiffle]
+	if riffle.RisingSequences > riffle.ExpectedRisingSequences-1 || riffle.Adjacency < riffle.ExpectedAdjacency {
+		t.Errorf("Test Case 3: Expected riffles to keep too much order. Got %f rising sequences and %f adjacency", riffle.RisingSequences, riffle.Adjacency)
+	}
+
+	// TEST CASE 4: Shuffles keep every card
+	// -------------------------------------
+	for name, strategy := range Strategies {
+		d := New()
+		strategy(d, rand.New(rand.NewSource(2)))
+		slices.SortFunc(d, func(a, b Card) int { return a.Index() - b.Index() })
+		if !slices.Equal(d, New()) {
+			t.Errorf("Test Case 4: Expected %s to keep every card", name)
+		}
+	}
+}
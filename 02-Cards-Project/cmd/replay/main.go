@@ -0,0 +1,160 @@
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/maevadevs/Go-Developer-Foundation/02-Cards-Project/cards"
+)
+
+// Command Structure
+// *****************
+// replay - Loads a replay file and steps through it, printing the table at each step
+//   -check         Only check that the replay is consistent with its seed, then exit
+//   -record SEED   Record a sample 2-player game with the seed (0 included) into the file, then exit
+//
+// While viewing:
+//   n (or Enter)   Next step
+//   p              Previous step
+//   g N            Go to step N
+//   q              Quit
+
+// Functions
+// *********
+
+// This is the main entry of the command.
+func main() {
+	check := flag.Bool("check", false, "only check that the replay is consistent with its seed")
+	record := flag.Int64("record", 0, "record a sample game with this seed (0 included) into the file")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: replay [-check] [-record SEED] FILE")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	filename := flag.Arg(0)
+
+	// Record a sample game: Any seed is valid, so -record is told apart by being set
+	recording := false
+	flag.Visit(func(f *flag.Flag) { recording = recording || f.Name == "record" })
+	if recording {
+		r, err := recordSample(*record)
+		if err != nil {
+			fail(err)
+		}
+		if err := r.Save(filename); err != nil {
+			fail(err)
+		}
+		fmt.Printf("Recorded a sample game with seed %d in %s\n", *record, filename)
+		return
+	}
+
+	r, err := cards.LoadReplay(filename)
+	if err != nil {
+		fail(err)
+	}
+
+	// Replaying checks every shuffle against the seed: Stop at the first inconsistency
+	states, err := r.States()
+	if *check {
+		if err != nil {
+			fail(err)
+		}
+		fmt.Printf("%s: %d steps, consistent with seed %d\n", filename, len(r.Actions), r.Seed)
+		return
+	}
+	if err != nil {
+		fmt.Printf("Warning: %v\nOnly the first %d steps can be viewed.\n\n", err, len(states)-1)
+	}
+
+	view(os.Stdin, os.Stdout, r, states)
+}
+
+// view steps through the states with the commands read from in.
+func view(in io.Reader, out io.Writer, r cards.Replay, states []cards.Table) {
+	reader := bufio.NewReader(in)
+	step := 0
+	for {
+		// Print the current step
+		if step == 0 {
+			fmt.Fprintf(out, "Step 0/%d: Deck shuffled with seed %d\n", len(states)-1, r.Seed)
+		} else {
+			fmt.Fprintf(out, "Step %d/%d: %s\n", step, len(states)-1, r.Actions[step-1])
+		}
+		fmt.Fprint(out, states[step])
+		fmt.Fprint(out, "[n]ext, [p]revious, [g]o N, [q]uit > ")
+
+		line, err := reader.ReadString('\n')
+		if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+			fmt.Fprintln(out)
+			return
+		}
+		command, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+		switch command {
+		case "", "n":
+			step = min(step+1, len(states)-1)
+		case "p":
+			step = max(step-1, 0)
+		case "g":
+			if n, err := strconv.Atoi(arg); err == nil && n >= 0 && n < len(states) {
+				step = n
+			} else {
+				fmt.Fprintf(out, "No step %q: Steps go from 0 to %d\n", arg, len(states)-1)
+			}
+		case "q":
+			return
+		default:
+			fmt.Fprintf(out, "Unknown command %q\n", command)
+		}
+		fmt.Fprintln(out)
+	}
+}
+
+// recordSample records a short 2-player game: Deal 5 cards each, then play and draw in turns,
+// with a shuffle of the deck halfway. Returns the first action that could not be recorded as an error.
+func recordSample(seed int64) (cards.Replay, error) {
+	rec := cards.NewRecorder(seed, 2)
+	for player := range 2 {
+		if err := rec.Deal(player, 5); err != nil {
+			return cards.Replay{}, err
+		}
+	}
+	for turn := range 10 {
+		player := turn % 2
+		if turn == 5 {
+			if err := rec.Shuffle(); err != nil {
+				return cards.Replay{}, err
+			}
+		}
+		if err := rec.Play(player, rec.Table.Hands[player][0]); err != nil {
+			return cards.Replay{}, err
+		}
+		if err := rec.Draw(player); err != nil {
+			return cards.Replay{}, err
+		}
+	}
+	return rec.Replay, nil
+}
+
+// fail prints the error and exits.
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "Error:", err)
+	os.Exit(1)
+}
+
+// To run:                 go run ./02-Cards-Project/cmd/replay -record 42 game.replay.json
+//                         go run ./02-Cards-Project/cmd/replay game.replay.json
+// To check a replay:      go run ./02-Cards-Project/cmd/replay -check game.replay.json
@@ -0,0 +1,95 @@
+/**
+ * @file: Unit tests for the replay viewer, run on the replay files in testdata
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/maevadevs/Go-Developer-Foundation/02-Cards-Project/cards"
+)
+
+// Test Cases for view()
+// *********************
+//   - Stepping forward should stop at the last step
+//   - Going to a step and back should print the table of each step
+//   - Unknown commands and steps should be reported, and the current step printed again
+//   - The end of the input should end the viewer, even without a quit
+//   - An inconsistent replay should only be viewed up to the step before the inconsistency
+
+func Test_view(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		input   string
+		// Expected in the output, in this order
+		want []string
+	}{
+		{
+			name:    "Forward to the end",
+			fixture: "seed42.replay.json",
+			input:   strings.Repeat("n\n", 30) + "q\n",
+			want:    []string{"Step 0/23: Deck shuffled with seed 42", "Step 13/23: The deck is shuffled", "Step 23/23: Player 2 draws", "Step 23/23: Player 2 draws"},
+		},
+		{
+			name:    "Go to a step and back",
+			fixture: "seed42.replay.json",
+			input:   "g 2\np\nq\n",
+			want: []string{
+				"Step 0/23: Deck shuffled with seed 42", "Player 1 (0): \n",
+				"Step 2/23: Player 2 is dealt 5 cards", "Player 2 (5): K of Diamond|3 of Diamond|2 of Diamond|5 of Diamond|5 of Spade\n",
+				"Step 1/23: Player 1 is dealt 5 cards", "Player 2 (0): \n",
+			},
+		},
+		{
+			name:    "Unknown commands and steps",
+			fixture: "seed42.replay.json",
+			input:   "g 99\ng x\nx\nq\n",
+			want:    []string{`No step "99": Steps go from 0 to 23`, `No step "x": Steps go from 0 to 23`, `Unknown command "x"`, "Step 0/23"},
+		},
+		{
+			name:    "End of input",
+			fixture: "seed42.replay.json",
+			input:   "n",
+			want:    []string{"Step 0/23", "Step 1/23", "[q]uit > \n"},
+		},
+		{
+			name:    "Inconsistent replay",
+			fixture: "tampered.replay.json",
+			input:   "g 13\ng 12\nn\nq\n",
+			want:    []string{"Step 0/12", `No step "13": Steps go from 0 to 12`, "Step 12/12: Player 1 draws", "Step 12/12: Player 1 draws"},
+		},
+	}
+
+	for i, test := range tests {
+		r, err := cards.LoadReplay(filepath.Join("testdata", test.fixture))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// As main() does: The states up to an inconsistency can be viewed
+		states, err := r.States()
+		if err != nil && !errors.Is(err, cards.ErrReplayInconsistent) {
+			t.Fatal(err)
+		}
+
+		var out strings.Builder
+		view(strings.NewReader(test.input), &out, r, states)
+		rest := out.String()
+		for _, want := range test.want {
+			found := strings.Index(rest, want)
+			if found < 0 {
+				t.Errorf("Test Case %d: %s: Expected %q in the output, after what came before. Got:\n%s", i+1, test.name, want, out.String())
+				break
+			}
+			rest = rest[found+len(want):]
+		}
+	}
+}
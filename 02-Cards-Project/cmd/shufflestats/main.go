@@ -0,0 +1,109 @@
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/maevadevs/Go-Developer-Foundation/02-Cards-Project/cards"
+)
+
+// Command Structure
+// *****************
+// shufflestats - Shuffles a deck N times with each strategy and reports the quality of its randomness
+//   -n COUNT           Number of shuffles per strategy (default 10000)
+//   -strategy NAMES    Comma-separated strategies: legacy, fisher-yates, riffle (default: all of them)
+//   -seed SEED         Seed of the Random Number Generator (default: time-based)
+//   -json              Print the full reports as JSON, including the position bias of every card
+//
+// The command exits with status 1 if a strategy fails the chi-square test.
+
+// Functions
+// *********
+
+// This is the main entry of the command.
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command with its arguments, and returns its exit status.
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("shufflestats", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	runs := flags.Int("n", 10_000, "number of shuffles per strategy")
+	names := flags.String("strategy", "", "comma-separated strategies (default: all of them)")
+	seed := flags.Int64("seed", time.Now().UnixNano(), "seed of the Random Number Generator")
+	asJSON := flags.Bool("json", false, "print the full reports as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	// Select the strategies
+	selected := slices.Sorted(maps.Keys(cards.Strategies))
+	if *names != "" {
+		selected = strings.Split(*names, ",")
+	}
+
+	reports := []cards.ShuffleReport{}
+	for _, name := range selected {
+		strategy, found := cards.Strategies[name]
+		if !found {
+			fmt.Fprintf(stderr, "Error: unknown strategy %q\n", name)
+			return 2
+		}
+		report, err := cards.NewShuffleReport(name, strategy, *runs, rand.New(rand.NewSource(*seed)))
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		reports = append(reports, report)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(reports)
+	} else {
+		printTable(stdout, reports, *seed)
+	}
+
+	for _, report := range reports {
+		if !report.Fair() {
+			return 1
+		}
+	}
+	return 0
+}
+
+// printTable prints one line per strategy to out.
+func printTable(out io.Writer, reports []cards.ShuffleReport, seed int64) {
+	fmt.Fprintf(out, "Shuffle quality report (seed %d, p-value threshold %g)\n\n", seed, cards.Significance)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STRATEGY\tRUNS\tCHI-SQUARE (DF)\tP-VALUE\tWORST POSITION BIAS\tADJACENCY (EXPECTED)\tRISING SEQUENCES (EXPECTED)\tVERDICT")
+	for _, r := range reports {
+		verdict := "PASS"
+		if !r.Fair() {
+			verdict = "FAIL"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%.1f (%d)\t%.4g\t%.1f%% (%s)\t%.3f (%.3f)\t%.2f (%.2f)\t%s\n",
+			r.Strategy, r.Runs, r.ChiSquare, r.DegreesOfFreedom, r.PValue,
+			100*r.WorstBias, r.WorstCard, r.Adjacency, r.ExpectedAdjacency,
+			r.RisingSequences, r.ExpectedRisingSequences, verdict)
+	}
+	w.Flush()
+}
+
+// To run:                 go run ./02-Cards-Project/cmd/shufflestats -n 20000 -seed 1
+// To output JSON:         go run ./02-Cards-Project/cmd/shufflestats -strategy legacy,fisher-yates -json
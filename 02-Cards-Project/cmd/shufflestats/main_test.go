@@ -0,0 +1,81 @@
+/**
+ * @file: Unit tests for the shuffle quality report, checked against the reports in testdata
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"encoding/json"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/maevadevs/Go-Developer-Foundation/02-Cards-Project/cards"
+)
+
+// Test Cases for run()
+// ********************
+//   - The table should match the report in testdata, and fail if a strategy is unfair
+//   - Only the selected strategies should be reported
+//   - Unknown strategies, invalid runs and invalid flags should be refused with status 2
+//   - The JSON output should hold the full reports
+
+func Test_run(t *testing.T) {
+	// The reports in testdata come from: go run ./cmd/shufflestats ARGS > testdata/FILE
+	tests := []struct {
+		name       string
+		args       []string
+		wantStatus int
+		// Report in testdata, or text expected in the errors
+		golden     string
+		wantStderr string
+	}{
+		{name: "All strategies", args: []string{"-n", "2000", "-seed", "1"}, wantStatus: 1, golden: "all.txt"},
+		{name: "Fair strategies", args: []string{"-n", "2000", "-seed", "1", "-strategy", "fisher-yates,riffle"}, wantStatus: 0, golden: "fair.txt"},
+		{name: "Unknown strategy", args: []string{"-strategy", "fisher-yates,bogo"}, wantStatus: 2, wantStderr: `unknown strategy "bogo"`},
+		{name: "No runs", args: []string{"-n", "0"}, wantStatus: 2, wantStderr: "cannot report on 0 runs"},
+		{name: "Invalid flag", args: []string{"-runs", "10"}, wantStatus: 2, wantStderr: "flag provided but not defined: -runs"},
+	}
+
+	for i, test := range tests {
+		var stdout, stderr strings.Builder
+		status := run(test.args, &stdout, &stderr)
+		if status != test.wantStatus {
+			t.Errorf("Test Case %d: %s: Expected status %d. Got %d (%s)", i+1, test.name, test.wantStatus, status, stderr.String())
+		}
+		if test.golden != "" {
+			want, err := os.ReadFile(filepath.Join("testdata", test.golden))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stdout.String() != string(want) {
+				t.Errorf("Test Case %d: %s: Expected the report of testdata/%s. Got:\n%s", i+1, test.name, test.golden, stdout.String())
+			}
+		}
+		if !strings.Contains(stderr.String(), test.wantStderr) {
+			t.Errorf("Test Case %d: %s: Expected %q in the errors. Got %q", i+1, test.name, test.wantStderr, stderr.String())
+		}
+	}
+
+	// TEST CASE 6: JSON
+	// -----------------
+	var stdout, stderr strings.Builder
+	if status := run([]string{"-n", "500", "-seed", "3", "-strategy", "legacy", "-json"}, &stdout, &stderr); status != 1 {
+		t.Errorf("Test Case 6: Expected status 1 for the legacy shuffle. Got %d (%s)", status, stderr.String())
+	}
+	var reports []cards.ShuffleReport
+	if err := json.Unmarshal([]byte(stdout.String()), &reports); err != nil {
+		t.Fatalf("Test Case 6: Expected JSON. Got %v", err)
+	}
+	want, _ := cards.NewShuffleReport(cards.StrategyLegacy, cards.Strategies[cards.StrategyLegacy], 500, rand.New(rand.NewSource(3)))
+	if len(reports) != 1 || !reflect.DeepEqual(reports[0], want) {
+		t.Errorf("Test Case 6: Expected the full report of the legacy shuffle. Got %+v", reports)
+	}
+}
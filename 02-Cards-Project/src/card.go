@@ -0,0 +1,93 @@
+/**
+ * @file: Describes what a single Card is, parsed from its deck string representation.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"github.com/maevadevs/Go-Developer-Foundation/02-Cards-Project/cards"
+)
+
+// Type Declaration
+// ****************
+
+// A card is the structured form of a deck entry such as "A of Spade".
+// It is kept for compatibility: New code should use the cards library (cards.Card).
+type card struct {
+	value string
+	suit  string
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// parseCard()
+// Parses the string representation of a card ("<value> of <suit>") into a card.
+func parseCard(s string) (card, error) {
+	// The cards library checks that both parts are known to the deck
+	c, err := cards.ParseCard(s)
+	if err != nil {
+		return card{}, err
+	}
+	return card{value: c.Value, suit: c.Suit}, nil
+}
+
+// parseCards()
+// Parses every card of a deck, stopping at the first invalid one.
+func parseCards(d deck) ([]card, error) {
+	parsed := make([]card, 0, len(d))
+	for _, s := range d {
+		c, err := parseCard(s)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, c)
+	}
+	return parsed, nil
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// card.public()
+// Receiver Function that converts the card into its cards library form.
+func (c card) public() cards.Card {
+	return cards.Card{Value: c.value, Suit: c.suit}
+}
+
+// card.toString()
+// Receiver Function to convert a card back into its deck string representation.
+func (c card) toString() string {
+	return c.public().String()
+}
+
+// card.rank()
+// Receiver Function that returns the rank of the card: A=1, 2..10, J=11, Q=12, K=13.
+// Returns 0 if the value is unknown.
+func (c card) rank() int {
+	return c.public().Rank()
+}
+
+// card.suitIndex()
+// Receiver Function that returns the position of the suit in deckSuits.
+// Returns -1 if the suit is unknown.
+func (c card) suitIndex() int {
+	return c.public().SuitIndex()
+}
+
+// card.isRed()
+// Receiver Function that tells if the card is red (Diamond or Heart) or black (Spade or Club).
+func (c card) isRed() bool {
+	return c.public().IsRed()
+}
+
+// card.index()
+// Receiver Function that returns a unique number for the card in [0, 51].
+// Useful as a compact key.
+func (c card) index() int {
+	return c.public().Index()
+}
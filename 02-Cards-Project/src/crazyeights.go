@@ -0,0 +1,290 @@
+/**
+ * @file: Describes the game of Crazy Eights for 2 to 5 players.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+)
+
+// Constants
+// *********
+
+const (
+	// Penalty of an 8 left in hand
+	crazyEightsEightPoints = 50
+	// Penalty of a face card (J, Q, K) left in hand
+	crazyEightsFacePoints = 10
+	// Safety net: No game of Crazy Eights needs this many turns
+	crazyEightsMaxTurns = 10000
+)
+
+// Interfaces
+// **********
+
+// crazyEightsPlayer is a seat at a game of Crazy Eights.
+type crazyEightsPlayer interface {
+	// Card to discard among the legal ones, or false to draw a card instead
+	discard(view crazyEightsView, legal []card) (card, bool)
+	// Suit named after playing an 8
+	chooseSuit(view crazyEightsView) string
+}
+
+// Type Declaration
+// ****************
+
+// A crazyEightsView is what a player can see on their turn.
+type crazyEightsView struct {
+	seat      int
+	hand      []card
+	top       card
+	suit      string
+	handSizes []int
+	stock     int
+}
+
+// A crazyEightsGame holds the hands, the stock, the discard pile and the suit to follow.
+type crazyEightsGame struct {
+	players []crazyEightsPlayer
+	hands   [][]card
+	stock   []card
+	discard []card
+	suit    string
+	turn    int
+	turns   int
+	passes  int
+}
+
+// A crazyEightsBot discards the first legal card, keeping its 8s for last, and names its longest suit.
+type crazyEightsBot struct{}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newCrazyEightsGame()
+// Initializes a game of Crazy Eights: 7 cards each for 2 players, 5 cards each for more.
+// The top card of the stock starts the discard pile.
+func newCrazyEightsGame(players []crazyEightsPlayer, seed int64) (*crazyEightsGame, error) {
+	if len(players) < 2 || len(players) > 5 {
+		return nil, fmt.Errorf("crazy eights needs 2 to 5 players, got %d", len(players))
+	}
+
+	handSize := 5
+	if len(players) == 2 {
+		handSize = 7
+	}
+	hands, stock, err := dealHands(len(players), handSize, rand.New(rand.NewSource(seed)))
+	if err != nil {
+		return nil, err
+	}
+
+	starter := stock[0]
+	return &crazyEightsGame{
+		players: players,
+		hands:   hands,
+		stock:   stock[1:],
+		discard: []card{starter},
+		suit:    starter.suit,
+	}, nil
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// crazyEightsBot.discard()
+// Receiver Function that discards the first legal card that is not an 8, or an 8 as a last resort.
+func (crazyEightsBot) discard(_ crazyEightsView, legal []card) (card, bool) {
+	if len(legal) == 0 {
+		return card{}, false
+	}
+	for _, c := range legal {
+		if c.value != "8" {
+			return c, true
+		}
+	}
+	return legal[0], true
+}
+
+// crazyEightsBot.chooseSuit()
+// Receiver Function that names the suit the bot holds the most of.
+func (crazyEightsBot) chooseSuit(view crazyEightsView) string {
+	counts := map[string]int{}
+	best := view.suit
+	for _, c := range view.hand {
+		if c.value == "8" {
+			continue
+		}
+		counts[c.suit]++
+		if counts[c.suit] > counts[best] {
+			best = c.suit
+		}
+	}
+	return best
+}
+
+// consolePlayer.discard()
+// Receiver Function that asks the human for the number of the card to discard, or "d" to draw.
+func (p *consolePlayer) discard(view crazyEightsView, legal []card) (card, bool) {
+	fmt.Fprintf(p.out, "Top of the discard pile: %s (suit to follow: %s)\n", view.top.toString(), view.suit)
+	p.printHand(view.hand)
+
+	for {
+		line, ok := p.readLine("Card to discard (or d to draw): ")
+		if !ok {
+			// No more input: Let a bot decide
+			return crazyEightsBot{}.discard(view, legal)
+		}
+		if line == "d" {
+			return card{}, false
+		}
+		if i, ok := parseChoice(line, len(view.hand)); ok && slices.Contains(legal, view.hand[i]) {
+			return view.hand[i], true
+		}
+		fmt.Fprintln(p.out, "Invalid choice: Match the suit or the value, or play an 8")
+	}
+}
+
+// consolePlayer.chooseSuit()
+// Receiver Function that asks the human to name a suit after playing an 8.
+func (p *consolePlayer) chooseSuit(view crazyEightsView) string {
+	for {
+		line, ok := p.readLine(fmt.Sprintf("Name a suit (%s): ", strings.Join(deckSuits[:], ", ")))
+		if !ok {
+			return crazyEightsBot{}.chooseSuit(view)
+		}
+		if slices.Contains(deckSuits[:], line) {
+			return line
+		}
+		fmt.Fprintln(p.out, "Invalid suit")
+	}
+}
+
+// crazyEightsGame.view()
+// Receiver Function that builds what a seat can see.
+func (g *crazyEightsGame) view(seat int) crazyEightsView {
+	v := crazyEightsView{
+		seat:  seat,
+		hand:  slices.Clone(g.hands[seat]),
+		top:   g.discard[len(g.discard)-1],
+		suit:  g.suit,
+		stock: len(g.stock),
+	}
+	for _, hand := range g.hands {
+		v.handSizes = append(v.handSizes, len(hand))
+	}
+	return v
+}
+
+// crazyEightsGame.legalPlays()
+// Receiver Function that lists the cards of a hand matching the suit or value on top, plus any 8.
+func (g *crazyEightsGame) legalPlays(seat int) []card {
+	top := g.discard[len(g.discard)-1]
+	legal := []card{}
+	for _, c := range g.hands[seat] {
+		if c.value == "8" || c.suit == g.suit || c.value == top.value {
+			legal = append(legal, c)
+		}
+	}
+	return legal
+}
+
+// crazyEightsGame.takeTurn()
+// Receiver Function that plays the turn of the current seat.
+// A player who cannot (or will not) discard draws until they can, or passes once the stock is empty.
+func (g *crazyEightsGame) takeTurn() error {
+	seat := g.turn
+	g.turns++
+	g.turn = (seat + 1) % len(g.players)
+
+	for {
+		legal := g.legalPlays(seat)
+		c, ok := g.players[seat].discard(g.view(seat), legal)
+
+		if !ok {
+			// Draw a card, or pass when the stock is empty
+			if len(g.stock) == 0 {
+				g.passes++
+				return nil
+			}
+			g.hands[seat] = append(g.hands[seat], g.stock[0])
+			g.stock = g.stock[1:]
+			continue
+		}
+
+		if !slices.Contains(legal, c) {
+			return fmt.Errorf("seat %d: cannot discard %s on %s", seat, c.toString(), g.view(seat).top.toString())
+		}
+
+		i := slices.Index(g.hands[seat], c)
+		g.hands[seat] = slices.Delete(g.hands[seat], i, i+1)
+		g.discard = append(g.discard, c)
+		g.suit = c.suit
+		g.passes = 0
+
+		// An 8 is wild: The player names the next suit
+		if c.value == "8" {
+			suit := g.players[seat].chooseSuit(g.view(seat))
+			if !slices.Contains(deckSuits[:], suit) {
+				return fmt.Errorf("seat %d: invalid suit %q", seat, suit)
+			}
+			g.suit = suit
+		}
+		return nil
+	}
+}
+
+// crazyEightsGame.isOver()
+// Receiver Function that tells if a player emptied their hand, or if every player passed in a row.
+func (g *crazyEightsGame) isOver() bool {
+	for _, hand := range g.hands {
+		if len(hand) == 0 {
+			return true
+		}
+	}
+	return g.passes >= len(g.players)
+}
+
+// crazyEightsGame.play()
+// Receiver Function that plays until a hand is empty or the game is blocked.
+// Each seat scores the penalty points left in its hand: The lowest score wins.
+func (g *crazyEightsGame) play() (gameSummary, error) {
+	for !g.isOver() && g.turns < crazyEightsMaxTurns {
+		if err := g.takeTurn(); err != nil {
+			return gameSummary{}, err
+		}
+	}
+
+	summary := gameSummary{game: "Crazy Eights", turns: g.turns}
+	for _, hand := range g.hands {
+		points := 0
+		for _, c := range hand {
+			points += crazyEightsPoints(c)
+		}
+		summary.scores = append(summary.scores, points)
+	}
+	summary.winners = lowestScores(summary.scores)
+	return summary, nil
+}
+
+// Helper Functions
+// ****************
+
+// crazyEightsPoints()
+// Returns the penalty of a card left in hand: 50 for an 8, 10 for a face card, 1 for an Ace, the pip value otherwise.
+func crazyEightsPoints(c card) int {
+	switch {
+	case c.value == "8":
+		return crazyEightsEightPoints
+	case c.rank() > 10:
+		return crazyEightsFacePoints
+	}
+	return c.rank()
+}
@@ -14,40 +14,34 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/maevadevs/Go-Developer-Foundation/02-Cards-Project/cards"
 )
 
 // Type Declaration
 // ****************
 
 // A Deck type is an abstraction of a slice of string with additional functionalities.
+// It is kept for compatibility: New code should use the cards library (cards.Deck).
 type deck []string
 
+// Deck Composition
+// ****************
+
+// Suits: An array of strings, from the cards library
+var deckSuits = cards.Suits()
+
+// Values: An array of strings, from the cards library
+var deckValues = cards.Values()
+
 // Initializer Function (Type Constructor)
 // ***************************************
 
 // Initializes and returns a new deck of cards.
 func newDeck() deck {
-	// A deck is just an abstraction of a slice of strings
-	cards := deck{}
-
-	// Suits: An array of strings
-	suits := [4]string{"Spade", "Diamond", "Heart", "Club"}
-
-	// Values: An array of strings
-	values := [13]string{"A", "2", "3", "4", "5", "6", "7", "8", "9", "10", "J", "Q", "K"}
-
-	// Build the combinations of Suits and Values
-	for _, suit := range suits {
-		for _, value := range values {
-			// Create the new card
-			newCard := fmt.Sprintf("%s of %s", value, suit)
-			// Append the new card to the deck
-			cards = append(cards, newCard)
-		}
-	}
-
-	// Return the new deck
-	return cards
+	// The cards library builds the combinations of Suits and Values
+	// A deck is just an abstraction of a slice of strings: Keep their text form
+	return deck(cards.New().Strings())
 }
 
 // Receiver Functions (Type Methods)
@@ -57,7 +51,7 @@ func newDeck() deck {
 // Receiver Functions for the deck type to print the value representation of a deck.
 func (d deck) print() {
 	dStrs := []string(d)
-	dStr := strings.Join(dStrs, "|")
+	dStr := strings.Join(dStrs, deckSeparator)
 	fmt.Printf("%s", dStr)
 	fmt.Println()
 }
@@ -66,8 +60,8 @@ func (d deck) print() {
 // Receiver Function to deal cards from the deck.
 func (d deck) deal(handSize int) (deck, deck) {
 	// Split the original deck into 2 using the handSize
-	hand := d[:handSize]
-	remDeck := d[handSize:]
+	// A deck is one instance of the generic deck: Share the same dealing logic
+	hand, remDeck := dealCards(d, handSize)
 
 	// Return the "hand" and the "remaining deck"
 	return hand, remDeck
@@ -81,7 +75,7 @@ func (d deck) toString() string {
 
 	// deck -> []string -> string: Condensce by joining with a separator
 	// We can join a []string to string using Join(strs []string, sep string)
-	dStr := strings.Join(dStrs, "|")
+	dStr := strings.Join(dStrs, deckSeparator)
 
 	// Finally return
 	return dStr
@@ -96,25 +90,15 @@ func (d deck) shuffle(times uint) {
 	source := rand.NewSource(time.Now().UnixNano()) // seed
 	randGen := rand.New(source)
 
-	// Suffle whatever times was passed in: At least once
-	if times == 0 {
-		times = 1
-	}
-	for range times {
-		// Go through the list of cards
-		for currentI := range d {
-			// Generate a random index number: [0, len(d)-1]
-			// We could make use of the rand.intn() function for this
-			// By default, the random number generator will always use the exact same seed
-			// Without a new seed, we will always get the exact same sequence
-			// randGen - We make use of the defined Time-Based Random Number Generator
-			randomI := randGen.Intn(len(d) - 1)
-
-			// Swap the current card and the card at the random index number
-			// Same syntax as Python Tuple for swapping
-			d[randomI], d[currentI] = d[currentI], d[randomI]
-		}
-	}
+	d.shuffleWith(randGen, times)
+}
+
+// deck.shuffleWith()
+// Receiver Function that shuffle the deck using the given Random Number Generator.
+// Passing a generator built from a fixed seed makes the shuffle reproducible (e.g. daily deals).
+func (d deck) shuffleWith(randGen *rand.Rand, times uint) {
+	// A deck is one instance of the generic deck: Share the same shuffling logic
+	shuffleCards(d, randGen, times)
 }
 
 // deck.saveToFile()
@@ -136,6 +120,26 @@ func (d deck) saveToFile(filename string) error {
 // Helper Functions
 // ****************
 
+// deckFromCards()
+// Converts a deck of the cards library into the legacy deck type.
+func deckFromCards(d cards.Deck) deck {
+	return deck(d.Strings())
+}
+
+// deck.toCards()
+// Receiver Function that converts the deck into a deck of the cards library. Fails on an unknown card.
+func (d deck) toCards() (cards.Deck, error) {
+	converted := make(cards.Deck, 0, len(d))
+	for i, s := range d {
+		c, err := cards.ParseCard(s)
+		if err != nil {
+			return nil, fmt.Errorf("card %d: %w", i+1, err)
+		}
+		converted = append(converted, c)
+	}
+	return converted, nil
+}
+
 // newDeckFromFile()
 // Function to create a new deck from an existing save file
 func newDeckFromFile(filename string) deck {
@@ -162,7 +166,7 @@ func newDeckFromFile(filename string) deck {
 
 	// We get a psv (pipe-separated values)
 	// We can use strings.Split(s string, sep string) to parse this into []string
-	deckStrs := strings.Split(deckStr, "|")
+	deckStrs := strings.Split(deckStr, deckSeparator)
 
 	// We can use the slice of strings to convert into an actual deck
 	return deck(deckStrs)
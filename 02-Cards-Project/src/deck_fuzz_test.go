@@ -0,0 +1,215 @@
+/**
+ * @file: Fuzz targets, property tests and benchmarks for the Deck type
+ * To run a fuzz target:    go test -fuzz=Fuzz_parseCard -fuzztime=30s ./02-Cards-Project/src
+ * To run the benchmarks:   go test -bench=. -run=^$ ./02-Cards-Project/src
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+// Fuzz Targets
+// ************
+//   - Parsers and loaders should never panic, whatever the input
+//   - Whatever they accept should encode back to the same text
+
+func Fuzz_parseCard(f *testing.F) {
+	f.Add("A of Spade")
+	f.Add("10 of Heart")
+	f.Add("Z of Nothing")
+	f.Add(" of ")
+	f.Fuzz(func(t *testing.T, s string) {
+		c, err := parseCard(s)
+		if err != nil {
+			return
+		}
+		if c.toString() != s {
+			t.Errorf("Expected %q to encode back to itself. Got %q", s, c.toString())
+		}
+		if c.index() < 0 || c.index() >= 52 {
+			t.Errorf("Expected an index in [0, 52) for %q. Got %d", s, c.index())
+		}
+	})
+}
+
+func Fuzz_newGenericDeckFromString(f *testing.F) {
+	f.Add(newDeck().toString())
+	f.Add("A of Spade|A of Spade")
+	f.Add("")
+	f.Add("|")
+	f.Fuzz(func(t *testing.T, s string) {
+		d, err := newGenericDeckFromString(s, cardCodec{})
+		if err != nil {
+			return
+		}
+		encoded, err := d.toString(cardCodec{})
+		if err != nil || encoded != s {
+			t.Errorf("Expected %q to encode back to itself. Got %q (%v)", s, encoded, err)
+		}
+	})
+}
+
+func Fuzz_deckDecoder(f *testing.F) {
+	f.Add(newDeck().toString())
+	f.Add("Red 7|Wild|Blue Draw Two")
+	f.Add("||")
+	f.Add("A of Spade\nK of Club")
+	f.Fuzz(func(t *testing.T, s string) {
+		// Streaming and in-memory decoding should agree, up to the end of the deck
+		streamed, streamErr := readGenericDeck(strings.NewReader(s), cardCodec{})
+		text, _, _ := strings.Cut(s, "\n")
+		parsed, parseErr := newGenericDeckFromString(text, cardCodec{})
+		if (streamErr == nil) != (parseErr == nil) {
+			t.Fatalf("Expected the same outcome for %q. Got %v and %v", s, streamErr, parseErr)
+		}
+		if streamErr == nil && !slices.Equal(streamed, parsed) {
+			t.Errorf("Expected the same deck for %q", s)
+		}
+	})
+}
+
+func Fuzz_readGameSession(f *testing.F) {
+	var buf bytes.Buffer
+	s, _ := newGameSession(1, 2, 5)
+	s.writeTo(&buf)
+	f.Add(buf.Bytes())
+	f.Add([]byte(newDeck().toString()))
+	f.Add([]byte(`{"version": 1, "hands": ["A of Spade"], "scores": [1], "discards": [""]}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		s, err := readGameSession(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		// Whatever is accepted should survive a save and a load
+		var buf bytes.Buffer
+		if err := s.writeTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		again, err := readGameSession(&buf)
+		if err != nil || !reflect.DeepEqual(again, s) {
+			t.Errorf("Expected the session to survive a save and a load (%v)", err)
+		}
+	})
+}
+
+func Fuzz_openDeck(f *testing.F) {
+	key, _ := newSaveKey([]byte("a fuzzing secret of some length"))
+	signed, _ := sealDeck(newDeck(), saveSigned, key)
+	encrypted, _ := sealDeck(newDeck(), saveEncrypted, key)
+	f.Add(signed)
+	f.Add(encrypted)
+	f.Add([]byte(saveEncryptedHeader + "\n"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Only files sealed with the key may open: Any change to a seed must be caught
+		d, err := openDeck(data, key)
+		switch {
+		case err == nil:
+			if !bytes.Equal(data, signed) && !bytes.Equal(data, encrypted) && !slices.Equal(d, newDeck()) {
+				t.Errorf("Expected a forged save file to be refused. Got %d cards", len(d))
+			}
+		case errors.Is(err, errSaveFormat), errors.Is(err, errSaveNotProtected):
+			// Refused before its signature or encryption is checked
+		case !errors.Is(err, errSaveTampered):
+			t.Errorf("Expected a forged save file to fail authentication. Got %v", err)
+		}
+	})
+}
+
+// Property Tests
+// **************
+//   - Shuffling should keep the same multiset of cards, for any seed and any number of times
+//   - Dealing should split the deck: The hand followed by the remaining deck is the original deck
+//   - Saving then loading should give back the same deck
+
+func Test_deckProperties(t *testing.T) {
+	// PROPERTY 1: Shuffle preserves the multiset of cards
+	// ---------------------------------------------------
+	shufflePreserves := func(seed int64, times uint8) bool {
+		d := newDeck()
+		d.shuffleWith(rand.New(rand.NewSource(seed)), uint(times%8))
+		sorted := slices.Clone(d)
+		slices.Sort(sorted)
+		original := newDeck()
+		slices.Sort(original)
+		return slices.Equal(sorted, original)
+	}
+	if err := quick.Check(shufflePreserves, nil); err != nil {
+		t.Errorf("Property 1: Shuffle did not preserve the cards: %v", err)
+	}
+
+	// PROPERTY 2: Deal + remaining equals the original
+	// ------------------------------------------------
+	dealSplits := func(seed int64, size uint8) bool {
+		d := newDeck()
+		d.shuffleWith(rand.New(rand.NewSource(seed)), 1)
+		handSize := int(size) % (len(d) + 1)
+		hand, rest := d.deal(handSize)
+		return len(hand) == handSize && slices.Equal(slices.Concat(hand, rest), d)
+	}
+	if err := quick.Check(dealSplits, nil); err != nil {
+		t.Errorf("Property 2: Deal did not split the deck: %v", err)
+	}
+
+	// PROPERTY 3: Save then load is the identity
+	// ------------------------------------------
+	filename := filepath.Join(t.TempDir(), "property.sav")
+	saveLoads := func(seed int64, size uint8) bool {
+		d := newDeck()
+		d.shuffleWith(rand.New(rand.NewSource(seed)), 1)
+		// Any non-empty part of a shuffled deck
+		d = d[:1+int(size)%len(d)]
+		if err := d.saveToFile(filename); err != nil {
+			return false
+		}
+		var streamed deck
+		var buf bytes.Buffer
+		d.WriteTo(&buf)
+		streamed.ReadFrom(&buf)
+		return slices.Equal(newDeckFromFile(filename), d) && slices.Equal(streamed, d)
+	}
+	if err := quick.Check(saveLoads, nil); err != nil {
+		t.Errorf("Property 3: Save then load changed the deck: %v", err)
+	}
+}
+
+// Benchmarks
+// **********
+
+func Benchmark_shuffle(b *testing.B) {
+	d := newDeck()
+	randGen := rand.New(rand.NewSource(1))
+	for b.Loop() {
+		d.shuffleWith(randGen, 1)
+	}
+}
+
+func Benchmark_deal(b *testing.B) {
+	d := newDeck()
+	for b.Loop() {
+		hand, rest := d.deal(5)
+		_, _ = hand, rest
+	}
+}
+
+func Benchmark_writeTo(b *testing.B) {
+	d := newDeck()
+	var buf bytes.Buffer
+	for b.Loop() {
+		buf.Reset()
+		d.WriteTo(&buf)
+	}
+}
@@ -0,0 +1,128 @@
+/**
+ * @file: Describes what the simple card games (War, Go Fish, Crazy Eights) have in common:
+ * Dealing seeded hands, a game-over summary and a human player over the console.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Type Declaration
+// ****************
+
+// A gameSummary describes how a game ended.
+//   - turns:   Number of turns (or battles) played
+//   - scores:  Score of each seat, as defined by the game
+//   - winners: Seats with the best score
+type gameSummary struct {
+	game    string
+	turns   int
+	scores  []int
+	winners []int
+}
+
+// A consolePlayer is a human playing over a text console, such as stdin and stdout.
+// It implements the player interface of every simple game.
+type consolePlayer struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newConsolePlayer()
+// Initializes a human player reading answers from in and printing prompts to out.
+// Use newConsolePlayer(os.Stdin, os.Stdout) to play from a terminal.
+func newConsolePlayer(in io.Reader, out io.Writer) *consolePlayer {
+	return &consolePlayer{in: bufio.NewReader(in), out: out}
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// gameSummary.toString()
+// Receiver Function to convert the summary into a printable representation.
+func (s gameSummary) toString() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s is over after %d turn(s) ---\n", s.game, s.turns)
+	for seat, score := range s.scores {
+		fmt.Fprintf(&sb, "Seat %d: %d\n", seat, score)
+	}
+	winners := []string{}
+	for _, seat := range s.winners {
+		winners = append(winners, fmt.Sprintf("Seat %d", seat))
+	}
+	fmt.Fprintf(&sb, "Winner(s): %s\n", strings.Join(winners, ", "))
+	return sb.String()
+}
+
+// consolePlayer.readLine()
+// Receiver Function that prints a prompt and reads one trimmed line of input.
+// Returns false once the input is exhausted.
+func (p *consolePlayer) readLine(prompt string) (string, bool) {
+	fmt.Fprint(p.out, prompt)
+	line, err := p.in.ReadString('\n')
+	if err != nil && line == "" {
+		return "", false
+	}
+	return strings.TrimSpace(line), true
+}
+
+// consolePlayer.printHand()
+// Receiver Function that prints the cards of a hand, numbered from 1.
+func (p *consolePlayer) printHand(hand []card) {
+	fmt.Fprintln(p.out, "Your hand:")
+	for i, c := range hand {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, c.toString())
+	}
+}
+
+// Helper Functions
+// ****************
+
+// dealHands()
+// Shuffles a new deck with the seed and deals handSize cards to each of the seats.
+// Returns the hands and the remaining deck.
+func dealHands(seats int, handSize int, randGen *rand.Rand) ([][]card, []card, error) {
+	d := newDeck()
+	d.shuffleWith(randGen, 1)
+
+	hands := make([][]card, seats)
+	for seat := range hands {
+		var hand deck
+		hand, d = d.deal(handSize)
+		cards, err := parseCards(hand)
+		if err != nil {
+			return nil, nil, err
+		}
+		hands[seat] = cards
+	}
+
+	stock, err := parseCards(d)
+	if err != nil {
+		return nil, nil, err
+	}
+	return hands, stock, nil
+}
+
+// parseChoice()
+// Parses a 1-based menu choice into a 0-based index, checking that it is in [1, n].
+func parseChoice(s string, n int) (int, bool) {
+	i, err := strconv.Atoi(s)
+	if err != nil || i < 1 || i > n {
+		return 0, false
+	}
+	return i - 1, true
+}
@@ -0,0 +1,166 @@
+/**
+ * @file: Unit tests for the simple card games: War, Go Fish and Crazy Eights
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Test Helpers
+// ************
+
+// countCards() adds up cards spread across several piles.
+func countCards(piles ...[]card) int {
+	total := 0
+	for _, pile := range piles {
+		total += len(pile)
+	}
+	return total
+}
+
+// Test Cases for warGame
+// **********************
+//   - An all-bot game should end with every card accounted for
+//   - The same seed should give the same game
+//   - A conceding player should lose
+
+func Test_warGame(t *testing.T) {
+	// TEST CASE 1: Cards are never lost nor created
+	// ---------------------------------------------
+	g, err := newWarGame([2]warPlayer{warBot{}, warBot{}}, 3)
+	if err != nil {
+		t.Fatalf("Test Case 1: Unexpected error: %v", err)
+	}
+	summary := g.play()
+	if total := countCards(g.piles[0], g.piles[1]); total != 52 {
+		t.Errorf("Test Case 1: Expected 52 cards at the end of the game. Got %d", total)
+	}
+	if len(summary.winners) == 0 || summary.turns == 0 {
+		t.Errorf("Test Case 1: Expected battles and a winner. Got %s", summary.toString())
+	}
+
+	// TEST CASE 2: Same seed, same game
+	// ---------------------------------
+	g2, _ := newWarGame([2]warPlayer{warBot{}, warBot{}}, 3)
+	if summary.toString() != g2.play().toString() {
+		t.Errorf("Test Case 2: Expected the same summary for the same seed")
+	}
+
+	// TEST CASE 3: Conceding loses the game
+	// -------------------------------------
+	human := newConsolePlayer(strings.NewReader("\n\nq\n"), io.Discard)
+	g, _ = newWarGame([2]warPlayer{human, warBot{}}, 3)
+	summary = g.play()
+	if summary.turns != 2 || len(summary.winners) != 1 || summary.winners[0] != 1 {
+		t.Errorf("Test Case 3: Expected seat 1 to win after 2 battles. Got %s", summary.toString())
+	}
+}
+
+// Test Cases for goFishGame
+// *************************
+//   - An all-bot game should lay down all 13 books
+//   - The same seed should give the same game
+//   - A human asking for a value not in hand should be asked again
+
+func Test_goFishGame(t *testing.T) {
+	// TEST CASE 1: Every book is laid down
+	// ------------------------------------
+	g, err := newGoFishGame([]goFishPlayer{&goFishBot{}, &goFishBot{}, &goFishBot{}}, 5)
+	if err != nil {
+		t.Fatalf("Test Case 1: Unexpected error: %v", err)
+	}
+	summary, err := g.play()
+	if err != nil {
+		t.Fatalf("Test Case 1: Unexpected error: %v", err)
+	}
+	books := 0
+	for _, score := range summary.scores {
+		books += score
+	}
+	if books != 13 || !g.isOver() {
+		t.Errorf("Test Case 1: Expected 13 books. Got %s", summary.toString())
+	}
+
+	// TEST CASE 2: Same seed, same game
+	// ---------------------------------
+	g2, _ := newGoFishGame([]goFishPlayer{&goFishBot{}, &goFishBot{}, &goFishBot{}}, 5)
+	summary2, _ := g2.play()
+	if summary.toString() != summary2.toString() {
+		t.Errorf("Test Case 2: Expected the same summary for the same seed")
+	}
+
+	// TEST CASE 3: A human asking for a value they do not hold is asked again
+	// -----------------------------------------------------------------------
+	g, _ = newGoFishGame([]goFishPlayer{&goFishBot{}, &goFishBot{}}, 5)
+	held := g.hands[0][0].value
+	missing := ""
+	for _, value := range deckValues {
+		if !slices.ContainsFunc(g.hands[0], func(c card) bool { return c.value == value }) {
+			missing = value
+			break
+		}
+	}
+	human := newConsolePlayer(strings.NewReader("1 "+missing+"\n1 "+held+"\n"), io.Discard)
+	if target, value := human.ask(g.view(0)); target != 1 || value != held {
+		t.Errorf("Test Case 3: Expected to ask seat 1 for %s. Got seat %d for %s", held, target, value)
+	}
+
+	// TEST CASE 4: The number of players is checked
+	// ---------------------------------------------
+	if _, err := newGoFishGame([]goFishPlayer{&goFishBot{}}, 5); err == nil {
+		t.Errorf("Test Case 4: Expected an error for a single player")
+	}
+}
+
+// Test Cases for crazyEightsGame
+// ******************************
+//   - An all-bot game should end with a winner and every card accounted for
+//   - The same seed should give the same game
+//   - Only cards matching the suit or value, and 8s, should be legal
+
+func Test_crazyEightsGame(t *testing.T) {
+	// TEST CASE 1: The game ends with every card accounted for
+	// --------------------------------------------------------
+	g, err := newCrazyEightsGame([]crazyEightsPlayer{crazyEightsBot{}, crazyEightsBot{}, crazyEightsBot{}, crazyEightsBot{}}, 11)
+	if err != nil {
+		t.Fatalf("Test Case 1: Unexpected error: %v", err)
+	}
+	summary, err := g.play()
+	if err != nil {
+		t.Fatalf("Test Case 1: Unexpected error: %v", err)
+	}
+	if total := countCards(append(g.hands, g.stock, g.discard)...); total != 52 {
+		t.Errorf("Test Case 1: Expected 52 cards at the end of the game. Got %d", total)
+	}
+	if !g.isOver() || len(summary.winners) == 0 {
+		t.Errorf("Test Case 1: Expected the game to be over with a winner. Got %s", summary.toString())
+	}
+
+	// TEST CASE 2: Same seed, same game
+	// ---------------------------------
+	g2, _ := newCrazyEightsGame([]crazyEightsPlayer{crazyEightsBot{}, crazyEightsBot{}, crazyEightsBot{}, crazyEightsBot{}}, 11)
+	summary2, _ := g2.play()
+	if summary.toString() != summary2.toString() {
+		t.Errorf("Test Case 2: Expected the same summary for the same seed")
+	}
+
+	// TEST CASE 3: Only matching cards and 8s are legal
+	// -------------------------------------------------
+	g, _ = newCrazyEightsGame([]crazyEightsPlayer{crazyEightsBot{}, crazyEightsBot{}}, 11)
+	g.discard = mustParseCards(t, "5 of Heart")
+	g.suit = "Heart"
+	g.hands[0] = mustParseCards(t, "5 of Club", "K of Heart", "8 of Spade", "2 of Diamond")
+	legal := g.legalPlays(0)
+	if len(legal) != 3 {
+		t.Errorf("Test Case 3: Expected 3 legal cards. Got %v", legal)
+	}
+}
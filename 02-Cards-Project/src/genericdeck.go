@@ -0,0 +1,190 @@
+/**
+ * @file: Describes a generic deck container that works with any type of card:
+ * French-suited playing cards, Tarot, Uno or custom trading cards.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+
+	"github.com/maevadevs/Go-Developer-Foundation/02-Cards-Project/cards"
+)
+
+// Constants
+// *********
+
+// Separator between cards in the text form of a deck, as used by deck.toString().
+const deckSeparator = cards.Separator
+
+// Errors
+// ******
+
+var errDeckTooSmall = cards.ErrDeckTooSmall
+
+// Interfaces
+// **********
+
+// A deckCodec converts cards of type T to and from text, to save and load decks.
+// An encoded card must not contain the deck separator "|".
+type deckCodec[T any] interface {
+	encode(c T) string
+	decode(s string) (T, error)
+}
+
+// Type Declaration
+// ****************
+
+// A genericDeck is an ordered pile of cards of any type. The top of the deck is the first card.
+type genericDeck[T any] []T
+
+// A stringCodec saves cards that already are strings, such as the entries of a deck.
+type stringCodec struct{}
+
+// A cardCodec saves playing cards as "<value> of <suit>".
+type cardCodec struct{}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newFrenchDeck()
+// Initializes the 52-card French-suited deck of newDeck() as a generic deck of cards.
+func newFrenchDeck() genericDeck[card] {
+	parsed, err := parseCards(newDeck())
+	if err != nil {
+		// newDeck() only builds valid cards
+		panic(err)
+	}
+	return genericDeck[card](parsed)
+}
+
+// newGenericDeckFromString()
+// Initializes a deck from its text form, decoding each card with the codec.
+func newGenericDeckFromString[T any](s string, codec deckCodec[T]) (genericDeck[T], error) {
+	d := genericDeck[T]{}
+	if s == "" {
+		return d, nil
+	}
+	for i, str := range strings.Split(s, deckSeparator) {
+		c, err := codec.decode(str)
+		if err != nil {
+			return nil, fmt.Errorf("card %d: %w", i+1, err)
+		}
+		d = append(d, c)
+	}
+	return d, nil
+}
+
+// newGenericDeckFromFile()
+// Initializes a deck from a file written by genericDeck.saveToFile().
+func newGenericDeckFromFile[T any](filename string, codec deckCodec[T]) (genericDeck[T], error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readGenericDeck(bufio.NewReader(f), codec)
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// genericDeck.shuffle()
+// Receiver Function that shuffles the deck in place with the given Random Number Generator.
+// A deck of 0 or 1 card is left as it is.
+func (d genericDeck[T]) shuffle(randGen *rand.Rand, times uint) {
+	if len(d) < 2 {
+		return
+	}
+	shuffleCards(d, randGen, times)
+}
+
+// genericDeck.deal()
+// Receiver Function that splits the deck into a hand of handSize cards and the remaining deck.
+func (d genericDeck[T]) deal(handSize int) (genericDeck[T], genericDeck[T], error) {
+	if handSize < 0 || handSize > len(d) {
+		return nil, d, fmt.Errorf("%w: cannot deal %d from %d", errDeckTooSmall, handSize, len(d))
+	}
+	hand, rest := dealCards(d, handSize)
+	return hand, rest, nil
+}
+
+// genericDeck.draw()
+// Receiver Function that takes the top card of the deck. Returns the card and the remaining deck.
+func (d genericDeck[T]) draw() (T, genericDeck[T], error) {
+	if len(d) == 0 {
+		var zero T
+		return zero, d, fmt.Errorf("%w: cannot draw from an empty deck", errDeckTooSmall)
+	}
+	return d[0], d[1:], nil
+}
+
+// genericDeck.cut()
+// Receiver Function that cuts the deck: The top n cards go to the bottom. Returns the new deck.
+func (d genericDeck[T]) cut(n int) (genericDeck[T], error) {
+	if n < 0 || n > len(d) {
+		return d, fmt.Errorf("%w: cannot cut at %d in %d", errDeckTooSmall, n, len(d))
+	}
+	cutDeck := make(genericDeck[T], 0, len(d))
+	cutDeck = append(cutDeck, d[n:]...)
+	return append(cutDeck, d[:n]...), nil
+}
+
+// genericDeck.toString()
+// Receiver Function to convert the deck into its text form, encoding each card with the codec.
+func (d genericDeck[T]) toString(codec deckCodec[T]) (string, error) {
+	strs := make([]string, 0, len(d))
+	for i, c := range d {
+		s := codec.encode(c)
+		if strings.Contains(s, deckSeparator) {
+			return "", fmt.Errorf("card %d: encoded card %q contains the separator %q", i+1, s, deckSeparator)
+		}
+		strs = append(strs, s)
+	}
+	return strings.Join(strs, deckSeparator), nil
+}
+
+// genericDeck.saveToFile()
+// Receiver Function to save the deck to a file, encoding each card with the codec.
+func (d genericDeck[T]) saveToFile(filename string, codec deckCodec[T]) error {
+	s, err := d.toString(codec)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, []byte(s), 0o666)
+}
+
+func (stringCodec) encode(s string) string          { return s }
+func (stringCodec) decode(s string) (string, error) { return s, nil }
+
+func (cardCodec) encode(c card) string          { return c.toString() }
+func (cardCodec) decode(s string) (card, error) { return parseCard(s) }
+
+// Helper Functions
+// ****************
+
+// shuffleCards()
+// Shuffles any slice of cards in place. This is the algorithm behind deck.shuffle(), from the cards library.
+func shuffleCards[T any](c []T, randGen *rand.Rand, times uint) {
+	cards.Shuffle(c, randGen, times)
+}
+
+// dealCards()
+// Splits any slice of cards into a hand of handSize cards and the remaining cards.
+// This is the algorithm behind deck.deal(), from the cards library.
+func dealCards[S ~[]T, T any](c S, handSize int) (S, S) {
+	hand, rest, err := cards.Deal(c, handSize)
+	if err != nil {
+		// Callers check the size of the deck first
+		panic(err)
+	}
+	return hand, rest
+}
@@ -0,0 +1,143 @@
+/**
+ * @file: Unit tests for the generic deck container
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Test Helpers
+// ************
+
+// A tradingCard is a custom card type, to make sure that the deck is not tied to playing cards.
+type tradingCard struct {
+	name  string
+	power int
+}
+
+// tradingCardCodec saves trading cards as "<name>:<power>".
+type tradingCardCodec struct{}
+
+func (tradingCardCodec) encode(c tradingCard) string {
+	return fmt.Sprintf("%s:%d", c.name, c.power)
+}
+
+func (tradingCardCodec) decode(s string) (tradingCard, error) {
+	name, power, found := strings.Cut(s, ":")
+	p, err := strconv.Atoi(power)
+	if !found || err != nil {
+		return tradingCard{}, fmt.Errorf("invalid trading card %q", s)
+	}
+	return tradingCard{name: name, power: p}, nil
+}
+
+// Test Cases for genericDeck with playing cards
+// *********************************************
+//   - The French deck should hold the 52 cards of newDeck()
+//   - A seeded shuffle should keep every card and be reproducible
+//   - Decks of 0 or 1 card should shuffle as they are
+//   - deal(), draw() and cut() should split the deck as expected
+
+func Test_genericDeckOfCards(t *testing.T) {
+	// TEST CASE 1: The French deck is an instance of the generic deck
+	// ---------------------------------------------------------------
+	d := newFrenchDeck()
+	if len(d) != 52 || d[0].toString() != "A of Spade" || d[51].toString() != "K of Club" {
+		t.Errorf("Test Case 1: Expected 52 cards from A of Spade to K of Club. Got %d cards", len(d))
+	}
+
+	// TEST CASE 2: Seeded shuffles keep every card and are reproducible
+	// -----------------------------------------------------------------
+	d1, d2 := newFrenchDeck(), newFrenchDeck()
+	d1.shuffle(rand.New(rand.NewSource(9)), 1)
+	d2.shuffle(rand.New(rand.NewSource(9)), 1)
+	if !slices.Equal(d1, d2) {
+		t.Errorf("Test Case 2: Expected the same order for the same seed")
+	}
+	sorted := slices.Clone(d1)
+	slices.SortFunc(sorted, func(a, b card) int { return a.index() - b.index() })
+	if !slices.Equal(sorted, d) {
+		t.Errorf("Test Case 2: Expected the shuffled deck to hold the same 52 cards")
+	}
+	for _, small := range []genericDeck[card]{{}, d[:1]} {
+		shuffled := slices.Clone(small)
+		shuffled.shuffle(rand.New(rand.NewSource(9)), 3)
+		if !slices.Equal(shuffled, small) {
+			t.Errorf("Test Case 2: Expected a deck of %d cards to shuffle as is. Got %v", len(small), shuffled)
+		}
+	}
+
+	// TEST CASE 3: deal(), draw() and cut()
+	// -------------------------------------
+	hand, rest, err := d.deal(5)
+	if err != nil || len(hand) != 5 || len(rest) != 47 {
+		t.Errorf("Test Case 3: Expected a hand of 5 and 47 remaining. Got %d and %d (%v)", len(hand), len(rest), err)
+	}
+	if _, _, err := d.deal(53); !errors.Is(err, errDeckTooSmall) {
+		t.Errorf("Test Case 3: Expected an error dealing 53 cards. Got %v", err)
+	}
+	top, rest, err := d.draw()
+	if err != nil || top.toString() != "A of Spade" || len(rest) != 51 {
+		t.Errorf("Test Case 3: Expected to draw the A of Spade. Got %s (%v)", top.toString(), err)
+	}
+	if _, _, err := (genericDeck[card]{}).draw(); !errors.Is(err, errDeckTooSmall) {
+		t.Errorf("Test Case 3: Expected an error drawing from an empty deck. Got %v", err)
+	}
+	cutDeck, err := d.cut(13)
+	if err != nil || cutDeck[0].toString() != "A of Diamond" || cutDeck[51].toString() != "K of Spade" {
+		t.Errorf("Test Case 3: Expected the Spades to move to the bottom. Got %v", err)
+	}
+}
+
+// Test Cases for genericDeck with a custom card type
+// **************************************************
+//   - Saving and loading through a codec should give back the same deck
+//   - Invalid saves and separators in encoded cards should be rejected
+
+func Test_genericDeckOfTradingCards(t *testing.T) {
+	d := genericDeck[tradingCard]{{"Dragon", 9}, {"Goblin", 2}, {"Knight", 5}}
+	filename := filepath.Join(t.TempDir(), "trading.sav")
+
+	// TEST CASE 1: Save and load
+	// --------------------------
+	if err := d.saveToFile(filename, tradingCardCodec{}); err != nil {
+		t.Fatalf("Test Case 1: Unexpected error: %v", err)
+	}
+	loaded, err := newGenericDeckFromFile(filename, tradingCardCodec{})
+	if err != nil || !slices.Equal(loaded, d) {
+		t.Errorf("Test Case 1: Expected the same deck back. Got %v (%v)", loaded, err)
+	}
+
+	// TEST CASE 2: Invalid files and cards
+	// ------------------------------------
+	os.WriteFile(filename, []byte("Dragon:9|Goblin"), 0o666)
+	if _, err := newGenericDeckFromFile(filename, tradingCardCodec{}); err == nil {
+		t.Errorf("Test Case 2: Expected an error loading an invalid card")
+	}
+	bad := genericDeck[tradingCard]{{"Pipe|Master", 1}}
+	if _, err := bad.toString(tradingCardCodec{}); err == nil {
+		t.Errorf("Test Case 2: Expected an error encoding a card with the separator")
+	}
+
+	// TEST CASE 3: A deck of strings reads the saves of deck.saveToFile()
+	// -------------------------------------------------------------------
+	newDeck().saveToFile(filename)
+	strs, err := newGenericDeckFromFile(filename, stringCodec{})
+	if err != nil || !slices.Equal(strs, genericDeck[string](newDeck())) {
+		t.Errorf("Test Case 3: Expected to load the deck saved by deck.saveToFile() (%v)", err)
+	}
+}
@@ -0,0 +1,277 @@
+/**
+ * @file: Describes the game of Go Fish for 2 to 6 players.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+)
+
+// Constants
+// *********
+
+const (
+	// Cards of the same value needed for a book
+	goFishBookSize = 4
+	// Safety net: No game of Go Fish needs this many turns
+	goFishMaxTurns = 10000
+)
+
+// Interfaces
+// **********
+
+// goFishPlayer is a seat at a game of Go Fish.
+type goFishPlayer interface {
+	// Seat to ask and card value to ask for: The value must be in the player's hand
+	ask(view goFishView) (int, string)
+}
+
+// Type Declaration
+// ****************
+
+// A goFishView is what a player can see on their turn.
+type goFishView struct {
+	seat      int
+	hand      []card
+	handSizes []int
+	books     [][]string
+	stock     int
+}
+
+// A goFishGame holds the hands, the stock and the books laid down by each seat.
+type goFishGame struct {
+	players []goFishPlayer
+	hands   [][]card
+	stock   []card
+	books   [][]string
+	turn    int
+	turns   int
+}
+
+// A goFishBot asks for the values it holds the most of first, rotating through values and
+// opponents on each ask so that it never gets stuck asking the same question.
+type goFishBot struct {
+	asks int
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newGoFishGame()
+// Initializes a game of Go Fish: 7 cards each for 2 or 3 players, 5 cards each for more.
+func newGoFishGame(players []goFishPlayer, seed int64) (*goFishGame, error) {
+	if len(players) < 2 || len(players) > 6 {
+		return nil, fmt.Errorf("go fish needs 2 to 6 players, got %d", len(players))
+	}
+
+	handSize := 7
+	if len(players) > 3 {
+		handSize = 5
+	}
+	hands, stock, err := dealHands(len(players), handSize, rand.New(rand.NewSource(seed)))
+	if err != nil {
+		return nil, err
+	}
+
+	g := &goFishGame{
+		players: players,
+		hands:   hands,
+		stock:   stock,
+		books:   make([][]string, len(players)),
+	}
+
+	// A lucky deal may already hold a book
+	for seat := range players {
+		g.layBooks(seat)
+	}
+	return g, nil
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// goFishBot.ask()
+// Receiver Function that picks the next (value, opponent) pair to ask for.
+// Values are tried from the most to the least common in hand, opponents from seat 0 up.
+func (b *goFishBot) ask(view goFishView) (int, string) {
+	defer func() { b.asks++ }()
+
+	// Values held, most common first
+	counts := map[string]int{}
+	values := []string{}
+	for _, c := range view.hand {
+		if counts[c.value] == 0 {
+			values = append(values, c.value)
+		}
+		counts[c.value]++
+	}
+	slices.SortStableFunc(values, func(a, b string) int { return counts[b] - counts[a] })
+
+	// Opponents that still hold cards (any opponent if none do)
+	opponents := []int{}
+	for seat, size := range view.handSizes {
+		if seat != view.seat && size > 0 {
+			opponents = append(opponents, seat)
+		}
+	}
+	if len(opponents) == 0 {
+		opponents = append(opponents, (view.seat+1)%len(view.handSizes))
+	}
+
+	value := values[b.asks%len(values)]
+	target := opponents[(b.asks/len(values))%len(opponents)]
+	return target, value
+}
+
+// consolePlayer.ask()
+// Receiver Function that asks the human who to ask and for what, as "<seat> <value>" (e.g. "1 Q").
+func (p *consolePlayer) ask(view goFishView) (int, string) {
+	p.printHand(view.hand)
+	for seat, size := range view.handSizes {
+		if seat != view.seat {
+			fmt.Fprintf(p.out, "Seat %d has %d card(s) and %d book(s)\n", seat, size, len(view.books[seat]))
+		}
+	}
+
+	for {
+		line, ok := p.readLine("Ask a seat for a value (e.g. \"1 Q\"): ")
+		if !ok {
+			// No more input: Let a bot decide
+			return (&goFishBot{}).ask(view)
+		}
+
+		var target int
+		var value string
+		if _, err := fmt.Sscanf(line, "%d %s", &target, &value); err == nil {
+			value = strings.ToUpper(value)
+			if target != view.seat && target >= 0 && target < len(view.handSizes) && slices.ContainsFunc(view.hand, func(c card) bool { return c.value == value }) {
+				return target, value
+			}
+		}
+		fmt.Fprintln(p.out, "Invalid request: Ask another seat for a value you hold")
+	}
+}
+
+// goFishGame.view()
+// Receiver Function that builds what a seat can see.
+func (g *goFishGame) view(seat int) goFishView {
+	v := goFishView{seat: seat, hand: slices.Clone(g.hands[seat]), stock: len(g.stock)}
+	for s := range g.hands {
+		v.handSizes = append(v.handSizes, len(g.hands[s]))
+		v.books = append(v.books, slices.Clone(g.books[s]))
+	}
+	return v
+}
+
+// goFishGame.isOver()
+// Receiver Function that tells if all 13 books were laid down.
+func (g *goFishGame) isOver() bool {
+	total := 0
+	for _, books := range g.books {
+		total += len(books)
+	}
+	return total == len(deckValues)
+}
+
+// goFishGame.draw()
+// Receiver Function that moves the top card of the stock to a hand.
+// Returns false when the stock is empty.
+func (g *goFishGame) draw(seat int) (card, bool) {
+	if len(g.stock) == 0 {
+		return card{}, false
+	}
+	c := g.stock[0]
+	g.stock = g.stock[1:]
+	g.hands[seat] = append(g.hands[seat], c)
+	return c, true
+}
+
+// goFishGame.layBooks()
+// Receiver Function that lays down every complete book of a hand.
+func (g *goFishGame) layBooks(seat int) {
+	counts := map[string]int{}
+	for _, c := range g.hands[seat] {
+		counts[c.value]++
+	}
+	for _, value := range deckValues {
+		if counts[value] == goFishBookSize {
+			g.books[seat] = append(g.books[seat], value)
+			g.hands[seat] = slices.DeleteFunc(g.hands[seat], func(c card) bool { return c.value == value })
+		}
+	}
+}
+
+// goFishGame.takeTurn()
+// Receiver Function that plays the turn of the current seat.
+//   - A player with an empty hand draws a card, or passes once the stock is empty
+//   - A successful ask, or fishing the value that was asked for, earns another turn
+func (g *goFishGame) takeTurn() error {
+	seat := g.turn
+	g.turns++
+
+	// An empty hand draws a card first
+	if len(g.hands[seat]) == 0 {
+		if _, ok := g.draw(seat); !ok {
+			g.turn = (seat + 1) % len(g.players)
+			return nil
+		}
+	}
+
+	target, value := g.players[seat].ask(g.view(seat))
+	if target == seat || target < 0 || target >= len(g.players) {
+		return fmt.Errorf("seat %d: cannot ask seat %d", seat, target)
+	}
+	if !slices.ContainsFunc(g.hands[seat], func(c card) bool { return c.value == value }) {
+		return fmt.Errorf("seat %d: cannot ask for %q without holding one", seat, value)
+	}
+
+	// The target hands over every card of that value
+	given := []card{}
+	g.hands[target] = slices.DeleteFunc(g.hands[target], func(c card) bool {
+		if c.value == value {
+			given = append(given, c)
+			return true
+		}
+		return false
+	})
+	g.hands[seat] = append(g.hands[seat], given...)
+
+	again := len(given) > 0
+	if !again {
+		// Go Fish!
+		if c, ok := g.draw(seat); ok && c.value == value {
+			again = true
+		}
+	}
+	g.layBooks(seat)
+
+	if !again || len(g.hands[seat]) == 0 {
+		g.turn = (seat + 1) % len(g.players)
+	}
+	return nil
+}
+
+// goFishGame.play()
+// Receiver Function that plays until every book is laid down. The most books wins.
+func (g *goFishGame) play() (gameSummary, error) {
+	for !g.isOver() && g.turns < goFishMaxTurns {
+		if err := g.takeTurn(); err != nil {
+			return gameSummary{}, err
+		}
+	}
+
+	summary := gameSummary{game: "Go Fish", turns: g.turns}
+	for _, books := range g.books {
+		summary.scores = append(summary.scores, len(books))
+	}
+	summary.winners = highestScores(summary.scores)
+	return summary, nil
+}
@@ -0,0 +1,140 @@
+/**
+ * @file: Describes the rules of Hearts for the trick-taking framework.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import "slices"
+
+// Constants
+// *********
+
+const (
+	// The game ends when a player reaches this score
+	heartsGameOver = 100
+	// Points in a round: One per Heart plus 13 for the Queen of Spade
+	heartsPointsInRound = 26
+)
+
+// Type Declaration
+// ****************
+
+// heartsRules are the rules of Hearts for 4 players (without passing cards).
+//   - The 2 of Club leads the first trick, where no points may be played unless there is no choice
+//   - Hearts cannot be led until a Heart has been played, unless there is nothing else left
+//   - Each Heart is worth 1 point and the Queen of Spade 13: Fewer points is better
+//   - Taking all 26 points ("shooting the moon") gives 26 points to every other player instead
+type heartsRules struct{}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+func (heartsRules) name() string  { return "Hearts" }
+func (heartsRules) seats() int    { return 4 }
+func (heartsRules) cards() deck   { return newDeck() }
+func (heartsRules) trump() string { return "" }
+func (heartsRules) bidding() bool { return false }
+
+// heartsRules.firstLeader()
+// The player holding the 2 of Club leads first.
+func (heartsRules) firstLeader(round *trickRound) int {
+	for seat, hand := range round.hands {
+		if slices.Contains(hand, card{value: "2", suit: "Club"}) {
+			return seat
+		}
+	}
+	return 0
+}
+
+// heartsRules.legalPlays()
+// Cards the seat may play in the current trick.
+func (heartsRules) legalPlays(round *trickRound, seat int) []card {
+	hand := round.hands[seat]
+	twoOfClub := card{value: "2", suit: "Club"}
+
+	// Leading
+	if len(round.trick) == 0 {
+		if round.completed == 0 && slices.Contains(hand, twoOfClub) {
+			return []card{twoOfClub}
+		}
+		if !round.suitPlayed("Heart") {
+			noHearts := slices.DeleteFunc(slices.Clone(hand), func(c card) bool { return c.suit == "Heart" })
+			if len(noHearts) > 0 {
+				return noHearts
+			}
+		}
+		return slices.Clone(hand)
+	}
+
+	// Following: Follow suit if possible
+	legal := followSuit(hand, round.trick[0].card.suit)
+
+	// No points on the first trick unless there is no choice
+	if round.completed == 0 && legal[0].suit != round.trick[0].card.suit {
+		noPoints := slices.DeleteFunc(slices.Clone(legal), func(c card) bool { return heartsPoints(c) > 0 })
+		if len(noPoints) > 0 {
+			return noPoints
+		}
+	}
+	return legal
+}
+
+// heartsRules.strength()
+// No trump: The highest card of the lead suit wins.
+func (heartsRules) strength(c card, leadSuit string) int {
+	return standardStrength(c, leadSuit, "")
+}
+
+// heartsRules.scoreRound()
+// Points taken by each seat, accounting for shooting the moon.
+func (heartsRules) scoreRound(round *trickRound, _ []int) []int {
+	points := make([]int, len(round.cardsWon))
+	for seat, cards := range round.cardsWon {
+		for _, c := range cards {
+			points[seat] += heartsPoints(c)
+		}
+	}
+
+	// Shooting the moon
+	for seat, p := range points {
+		if p == heartsPointsInRound {
+			for other := range points {
+				points[other] = heartsPointsInRound
+			}
+			points[seat] = 0
+			break
+		}
+	}
+	return points
+}
+
+// heartsRules.isGameOver()
+// The game ends once a player reaches 100 points.
+func (heartsRules) isGameOver(scores []int) bool {
+	return slices.Max(scores) >= heartsGameOver
+}
+
+// heartsRules.winners()
+// The lowest score wins.
+func (heartsRules) winners(scores []int) []int {
+	return lowestScores(scores)
+}
+
+// Helper Functions
+// ****************
+
+// heartsPoints()
+// Returns the penalty points of a card in Hearts.
+func heartsPoints(c card) int {
+	switch {
+	case c.suit == "Heart":
+		return 1
+	case c.suit == "Spade" && c.value == "Q":
+		return 13
+	}
+	return 0
+}
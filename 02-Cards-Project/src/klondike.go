@@ -0,0 +1,538 @@
+/**
+ * @file: Describes a game of Klondike solitaire played with a deck of cards.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Constants
+// *********
+
+const (
+	// Number of columns in the tableau
+	klondikeColumns = 7
+	// Number of foundation piles: One per suit
+	klondikeFoundations = len(deckSuits)
+)
+
+// Kinds of moves that can be made in Klondike.
+type klondikeMoveKind int
+
+const (
+	// Turn cards from the stock to the waste, or recycle the waste when the stock is empty
+	klondikeDraw klondikeMoveKind = iota
+	// Move the top card of the waste onto a tableau column
+	klondikeWasteToTableau
+	// Move the top card of the waste onto its foundation
+	klondikeWasteToFoundation
+	// Move the top card of a tableau column onto its foundation
+	klondikeTableauToFoundation
+	// Move one or more face-up cards from a tableau column onto another column
+	klondikeTableauToTableau
+	// Move the top card of a foundation back onto a tableau column
+	klondikeFoundationToTableau
+)
+
+// Errors
+// ******
+
+var (
+	errKlondikeIllegalMove = errors.New("illegal move")
+	errKlondikeNothingUndo = errors.New("nothing to undo")
+)
+
+// Type Declaration
+// ****************
+
+// A klondikeMove describes a single action on the table.
+//   - from:  Source column (tableau moves) or foundation (foundation-to-tableau)
+//   - to:    Destination column (tableau moves). Foundation moves use the suit of the card instead.
+//   - count: Number of cards moved (tableau-to-tableau only)
+type klondikeMove struct {
+	kind  klondikeMoveKind
+	from  int
+	to    int
+	count int
+}
+
+// A klondikeColumn is a tableau column: Face-down cards covered by a run of face-up cards.
+// The last card of each slice is the top of the pile.
+type klondikeColumn struct {
+	faceDown []card
+	faceUp   []card
+}
+
+// A klondikeTable is the position of every card at a given time.
+//   - foundations are indexed in the order of deckSuits
+//   - stock and waste have their top card last
+type klondikeTable struct {
+	tableau     [klondikeColumns]klondikeColumn
+	foundations [klondikeFoundations][]card
+	stock       []card
+	waste       []card
+}
+
+// A klondike game is a table, its draw rule (1 or 3 cards) and an undo stack.
+type klondike struct {
+	table     klondikeTable
+	drawCount int
+	undoStack []klondikeTable
+	moves     []klondikeMove
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newKlondike()
+// Initializes a new game of Klondike from a deck, dealt in the given order.
+//   - The tableau is dealt row by row: Column n gets n cards, only the last one face-up
+//   - The remaining cards become the stock, the first remaining card being drawn first
+//   - drawCount must be 1 (draw-1) or 3 (draw-3)
+func newKlondike(d deck, drawCount int) (*klondike, error) {
+	if drawCount != 1 && drawCount != 3 {
+		return nil, fmt.Errorf("invalid draw count %d: expected 1 or 3", drawCount)
+	}
+
+	cards, err := parseCards(d)
+	if err != nil {
+		return nil, err
+	}
+	if len(cards) != len(deckSuits)*len(deckValues) {
+		return nil, fmt.Errorf("invalid deck: expected %d cards, got %d", len(deckSuits)*len(deckValues), len(cards))
+	}
+
+	// Make sure that the deck does not contain duplicates
+	var seen [52]bool
+	for _, c := range cards {
+		if seen[c.index()] {
+			return nil, fmt.Errorf("invalid deck: duplicate card %q", c.toString())
+		}
+		seen[c.index()] = true
+	}
+
+	k := &klondike{drawCount: drawCount}
+
+	// Deal the tableau row by row
+	next := 0
+	for row := range klondikeColumns {
+		for col := row; col < klondikeColumns; col++ {
+			column := &k.table.tableau[col]
+			if col == row {
+				column.faceUp = append(column.faceUp, cards[next])
+			} else {
+				column.faceDown = append(column.faceDown, cards[next])
+			}
+			next++
+		}
+	}
+
+	// The rest goes to the stock: Reverse it so that the next card in the deck is on top
+	for i := len(cards) - 1; i >= next; i-- {
+		k.table.stock = append(k.table.stock, cards[i])
+	}
+
+	return k, nil
+}
+
+// newSeededKlondike()
+// Initializes a new game of Klondike from a fresh deck shuffled with the given seed.
+// The same seed always gives the same deal.
+func newSeededKlondike(seed int64, drawCount int) (*klondike, error) {
+	d := newDeck()
+	d.shuffleWith(rand.New(rand.NewSource(seed)), 1)
+	return newKlondike(d, drawCount)
+}
+
+// newDailyKlondike()
+// Initializes the deal of the day: Every player gets the same deal on the same calendar date.
+func newDailyKlondike(date time.Time, drawCount int) (*klondike, error) {
+	year, month, day := date.Date()
+	seed := int64(year*10000 + int(month)*100 + day)
+	return newSeededKlondike(seed, drawCount)
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// klondike.legalMoves()
+// Receiver Function that lists every legal move in the current position.
+func (k *klondike) legalMoves() []klondikeMove {
+	return k.table.legalMoves()
+}
+
+// klondike.validate()
+// Receiver Function that checks if a move is legal in the current position.
+// Returns an error describing why it is not.
+func (k *klondike) validate(m klondikeMove) error {
+	return k.table.validate(m)
+}
+
+// klondike.play()
+// Receiver Function that validates and applies a move, remembering the previous position for undo().
+func (k *klondike) play(m klondikeMove) error {
+	if err := k.table.validate(m); err != nil {
+		return err
+	}
+
+	k.undoStack = append(k.undoStack, k.table.clone())
+	k.moves = append(k.moves, m)
+	k.table.apply(m, k.drawCount)
+	return nil
+}
+
+// klondike.undo()
+// Receiver Function that takes back the last move played.
+func (k *klondike) undo() error {
+	if len(k.undoStack) == 0 {
+		return errKlondikeNothingUndo
+	}
+
+	last := len(k.undoStack) - 1
+	k.table = k.undoStack[last]
+	k.undoStack = k.undoStack[:last]
+	k.moves = k.moves[:last]
+	return nil
+}
+
+// klondike.isWon()
+// Receiver Function that tells if every card made it to the foundations.
+func (k *klondike) isWon() bool {
+	return k.table.isWon()
+}
+
+// klondike.toString()
+// Receiver Function to convert the table into a printable representation.
+func (k *klondike) toString() string {
+	return k.table.toString()
+}
+
+// klondikeTable.clone()
+// Receiver Function that makes a deep copy of the table.
+func (t *klondikeTable) clone() klondikeTable {
+	var c klondikeTable
+	for i, column := range t.tableau {
+		c.tableau[i].faceDown = append([]card(nil), column.faceDown...)
+		c.tableau[i].faceUp = append([]card(nil), column.faceUp...)
+	}
+	for i, foundation := range t.foundations {
+		c.foundations[i] = append([]card(nil), foundation...)
+	}
+	c.stock = append([]card(nil), t.stock...)
+	c.waste = append([]card(nil), t.waste...)
+	return c
+}
+
+// klondikeTable.isWon()
+// Receiver Function that tells if every foundation is complete.
+func (t *klondikeTable) isWon() bool {
+	for _, foundation := range t.foundations {
+		if len(foundation) != len(deckValues) {
+			return false
+		}
+	}
+	return true
+}
+
+// klondikeTable.canFound()
+// Receiver Function that tells if a card can go on its foundation.
+func (t *klondikeTable) canFound(c card) bool {
+	return len(t.foundations[c.suitIndex()]) == c.rank()-1
+}
+
+// klondikeTable.canStack()
+// Receiver Function that tells if a card can go on top of a tableau column.
+//   - Only a King can go on an empty column
+//   - Otherwise, the card must be one rank lower and of the opposite color
+func (t *klondikeTable) canStack(c card, col int) bool {
+	column := t.tableau[col]
+	if len(column.faceUp) == 0 {
+		return len(column.faceDown) == 0 && c.rank() == len(deckValues)
+	}
+	top := column.faceUp[len(column.faceUp)-1]
+	return top.rank() == c.rank()+1 && top.isRed() != c.isRed()
+}
+
+// klondikeTable.validate()
+// Receiver Function that checks if a move is legal on this table.
+func (t *klondikeTable) validate(m klondikeMove) error {
+	validColumn := func(i int) bool { return i >= 0 && i < klondikeColumns }
+
+	switch m.kind {
+	case klondikeDraw:
+		if len(t.stock) == 0 && len(t.waste) == 0 {
+			return fmt.Errorf("%w: stock and waste are both empty", errKlondikeIllegalMove)
+		}
+
+	case klondikeWasteToTableau:
+		if len(t.waste) == 0 {
+			return fmt.Errorf("%w: waste is empty", errKlondikeIllegalMove)
+		}
+		if !validColumn(m.to) {
+			return fmt.Errorf("%w: no column %d", errKlondikeIllegalMove, m.to+1)
+		}
+		top := t.waste[len(t.waste)-1]
+		if !t.canStack(top, m.to) {
+			return fmt.Errorf("%w: %s cannot go on column %d", errKlondikeIllegalMove, top.toString(), m.to+1)
+		}
+
+	case klondikeWasteToFoundation:
+		if len(t.waste) == 0 {
+			return fmt.Errorf("%w: waste is empty", errKlondikeIllegalMove)
+		}
+		top := t.waste[len(t.waste)-1]
+		if !t.canFound(top) {
+			return fmt.Errorf("%w: %s cannot go on its foundation", errKlondikeIllegalMove, top.toString())
+		}
+
+	case klondikeTableauToFoundation:
+		if !validColumn(m.from) {
+			return fmt.Errorf("%w: no column %d", errKlondikeIllegalMove, m.from+1)
+		}
+		faceUp := t.tableau[m.from].faceUp
+		if len(faceUp) == 0 {
+			return fmt.Errorf("%w: column %d is empty", errKlondikeIllegalMove, m.from+1)
+		}
+		top := faceUp[len(faceUp)-1]
+		if !t.canFound(top) {
+			return fmt.Errorf("%w: %s cannot go on its foundation", errKlondikeIllegalMove, top.toString())
+		}
+
+	case klondikeTableauToTableau:
+		if !validColumn(m.from) || !validColumn(m.to) {
+			return fmt.Errorf("%w: no such column", errKlondikeIllegalMove)
+		}
+		if m.from == m.to {
+			return fmt.Errorf("%w: source and destination are the same column", errKlondikeIllegalMove)
+		}
+		faceUp := t.tableau[m.from].faceUp
+		if m.count < 1 || m.count > len(faceUp) {
+			return fmt.Errorf("%w: column %d has %d face-up cards, cannot move %d", errKlondikeIllegalMove, m.from+1, len(faceUp), m.count)
+		}
+		base := faceUp[len(faceUp)-m.count]
+		if !t.canStack(base, m.to) {
+			return fmt.Errorf("%w: %s cannot go on column %d", errKlondikeIllegalMove, base.toString(), m.to+1)
+		}
+
+	case klondikeFoundationToTableau:
+		if m.from < 0 || m.from >= klondikeFoundations {
+			return fmt.Errorf("%w: no foundation %d", errKlondikeIllegalMove, m.from)
+		}
+		if !validColumn(m.to) {
+			return fmt.Errorf("%w: no column %d", errKlondikeIllegalMove, m.to+1)
+		}
+		foundation := t.foundations[m.from]
+		if len(foundation) == 0 {
+			return fmt.Errorf("%w: %s foundation is empty", errKlondikeIllegalMove, deckSuits[m.from])
+		}
+		top := foundation[len(foundation)-1]
+		if !t.canStack(top, m.to) {
+			return fmt.Errorf("%w: %s cannot go on column %d", errKlondikeIllegalMove, top.toString(), m.to+1)
+		}
+
+	default:
+		return fmt.Errorf("%w: unknown move kind %d", errKlondikeIllegalMove, m.kind)
+	}
+
+	return nil
+}
+
+// klondikeTable.apply()
+// Receiver Function that applies a move that was already validated.
+// A face-down card left on top of a column is turned face-up automatically.
+func (t *klondikeTable) apply(m klondikeMove, drawCount int) {
+	switch m.kind {
+	case klondikeDraw:
+		if len(t.stock) == 0 {
+			// Recycle: Turn the waste over to become the stock again
+			for i := len(t.waste) - 1; i >= 0; i-- {
+				t.stock = append(t.stock, t.waste[i])
+			}
+			t.waste = t.waste[:0]
+			return
+		}
+		for i := 0; i < drawCount && len(t.stock) > 0; i++ {
+			top := t.stock[len(t.stock)-1]
+			t.stock = t.stock[:len(t.stock)-1]
+			t.waste = append(t.waste, top)
+		}
+
+	case klondikeWasteToTableau:
+		top := t.popWaste()
+		t.tableau[m.to].faceUp = append(t.tableau[m.to].faceUp, top)
+
+	case klondikeWasteToFoundation:
+		top := t.popWaste()
+		t.foundations[top.suitIndex()] = append(t.foundations[top.suitIndex()], top)
+
+	case klondikeTableauToFoundation:
+		column := &t.tableau[m.from]
+		top := column.faceUp[len(column.faceUp)-1]
+		column.faceUp = column.faceUp[:len(column.faceUp)-1]
+		t.foundations[top.suitIndex()] = append(t.foundations[top.suitIndex()], top)
+		column.flip()
+
+	case klondikeTableauToTableau:
+		src := &t.tableau[m.from]
+		run := src.faceUp[len(src.faceUp)-m.count:]
+		t.tableau[m.to].faceUp = append(t.tableau[m.to].faceUp, run...)
+		src.faceUp = src.faceUp[:len(src.faceUp)-m.count]
+		src.flip()
+
+	case klondikeFoundationToTableau:
+		foundation := t.foundations[m.from]
+		top := foundation[len(foundation)-1]
+		t.foundations[m.from] = foundation[:len(foundation)-1]
+		t.tableau[m.to].faceUp = append(t.tableau[m.to].faceUp, top)
+	}
+}
+
+// klondikeTable.popWaste()
+// Receiver Function that removes and returns the top card of the waste.
+func (t *klondikeTable) popWaste() card {
+	top := t.waste[len(t.waste)-1]
+	t.waste = t.waste[:len(t.waste)-1]
+	return top
+}
+
+// klondikeColumn.flip()
+// Receiver Function that turns the top face-down card face-up once the face-up run is gone.
+func (column *klondikeColumn) flip() {
+	if len(column.faceUp) == 0 && len(column.faceDown) > 0 {
+		last := len(column.faceDown) - 1
+		column.faceUp = append(column.faceUp, column.faceDown[last])
+		column.faceDown = column.faceDown[:last]
+	}
+}
+
+// klondikeTable.legalMoves()
+// Receiver Function that lists every legal move on this table.
+// Moves to the foundations come first, drawing from the stock comes last.
+func (t *klondikeTable) legalMoves() []klondikeMove {
+	moves := []klondikeMove{}
+
+	// Waste and tableau to foundations
+	if len(t.waste) > 0 && t.canFound(t.waste[len(t.waste)-1]) {
+		moves = append(moves, klondikeMove{kind: klondikeWasteToFoundation})
+	}
+	for from, column := range t.tableau {
+		if len(column.faceUp) > 0 && t.canFound(column.faceUp[len(column.faceUp)-1]) {
+			moves = append(moves, klondikeMove{kind: klondikeTableauToFoundation, from: from})
+		}
+	}
+
+	// Tableau to tableau: Any face-up run that fits on another column
+	for from, column := range t.tableau {
+		for count := len(column.faceUp); count >= 1; count-- {
+			base := column.faceUp[len(column.faceUp)-count]
+			for to := range t.tableau {
+				if to != from && t.canStack(base, to) {
+					moves = append(moves, klondikeMove{kind: klondikeTableauToTableau, from: from, to: to, count: count})
+				}
+			}
+		}
+	}
+
+	// Waste to tableau
+	if len(t.waste) > 0 {
+		top := t.waste[len(t.waste)-1]
+		for to := range t.tableau {
+			if t.canStack(top, to) {
+				moves = append(moves, klondikeMove{kind: klondikeWasteToTableau, to: to})
+			}
+		}
+	}
+
+	// Foundations back to tableau
+	for from, foundation := range t.foundations {
+		if len(foundation) == 0 {
+			continue
+		}
+		top := foundation[len(foundation)-1]
+		for to := range t.tableau {
+			if t.canStack(top, to) {
+				moves = append(moves, klondikeMove{kind: klondikeFoundationToTableau, from: from, to: to})
+			}
+		}
+	}
+
+	// Draw from the stock (or recycle the waste)
+	if len(t.stock) > 0 || len(t.waste) > 0 {
+		moves = append(moves, klondikeMove{kind: klondikeDraw})
+	}
+
+	return moves
+}
+
+// klondikeTable.toString()
+// Receiver Function to convert the table into a printable representation.
+// Face-down cards are shown as "##".
+func (t *klondikeTable) toString() string {
+	var sb strings.Builder
+
+	// Stock and waste: Only the top 3 waste cards are visible
+	fmt.Fprintf(&sb, "Stock: %d card(s) | Waste:", len(t.stock))
+	start := max(len(t.waste)-3, 0)
+	if len(t.waste) == 0 {
+		sb.WriteString(" -")
+	}
+	for _, c := range t.waste[start:] {
+		fmt.Fprintf(&sb, " [%s]", c.toString())
+	}
+	sb.WriteString("\n")
+
+	// Foundations: Only the top card
+	sb.WriteString("Foundations:")
+	for i, foundation := range t.foundations {
+		if len(foundation) == 0 {
+			fmt.Fprintf(&sb, " [- of %s]", deckSuits[i])
+			continue
+		}
+		fmt.Fprintf(&sb, " [%s]", foundation[len(foundation)-1].toString())
+	}
+	sb.WriteString("\n")
+
+	// Tableau: One line per column
+	for i, column := range t.tableau {
+		fmt.Fprintf(&sb, "T%d:", i+1)
+		for range column.faceDown {
+			sb.WriteString(" ##")
+		}
+		for _, c := range column.faceUp {
+			fmt.Fprintf(&sb, " [%s]", c.toString())
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// klondikeMove.toString()
+// Receiver Function to convert a move into a readable string. Columns are numbered from 1.
+func (m klondikeMove) toString() string {
+	switch m.kind {
+	case klondikeDraw:
+		return "draw"
+	case klondikeWasteToTableau:
+		return fmt.Sprintf("waste -> T%d", m.to+1)
+	case klondikeWasteToFoundation:
+		return "waste -> foundation"
+	case klondikeTableauToFoundation:
+		return fmt.Sprintf("T%d -> foundation", m.from+1)
+	case klondikeTableauToTableau:
+		return fmt.Sprintf("T%d -> T%d (%d card(s))", m.from+1, m.to+1, m.count)
+	case klondikeFoundationToTableau:
+		return fmt.Sprintf("%s foundation -> T%d", deckSuits[m.from], m.to+1)
+	}
+	return fmt.Sprintf("unknown move %d", m.kind)
+}
@@ -0,0 +1,225 @@
+/**
+ * @file: Describes a solver that decides if a Klondike deal can be won.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"slices"
+	"strings"
+)
+
+// Constants
+// *********
+
+// Outcomes of a solver run.
+type klondikeSolveStatus int
+
+const (
+	// A winning sequence of moves was found
+	klondikeSolvable klondikeSolveStatus = iota
+	// Every reachable position was explored without finding a win
+	klondikeUnsolvable
+	// The node budget ran out before the search could conclude
+	klondikeBudgetExceeded
+)
+
+// Type Declaration
+// ****************
+
+// A klondikeSolution is the result of solveKlondike().
+//   - moves: The winning sequence of moves when status is klondikeSolvable
+//   - nodes: The number of positions explored
+type klondikeSolution struct {
+	status klondikeSolveStatus
+	moves  []klondikeMove
+	nodes  int
+}
+
+// A klondikeSolver holds the state of a single depth-first search.
+type klondikeSolver struct {
+	drawCount int
+	budget    int
+	nodes     int
+	visited   map[string]bool
+	path      []klondikeMove
+}
+
+// Helper Functions
+// ****************
+
+// solveKlondike()
+// Decides if the current position of a game can be won, exploring at most nodeBudget positions.
+// The game itself is left untouched.
+func solveKlondike(k *klondike, nodeBudget int) klondikeSolution {
+	s := &klondikeSolver{
+		drawCount: k.drawCount,
+		budget:    nodeBudget,
+		visited:   map[string]bool{},
+	}
+
+	table := k.table.clone()
+	won, exhausted := s.search(&table)
+
+	solution := klondikeSolution{nodes: s.nodes}
+	switch {
+	case won:
+		solution.status = klondikeSolvable
+		solution.moves = s.path
+	case exhausted:
+		solution.status = klondikeBudgetExceeded
+	default:
+		solution.status = klondikeUnsolvable
+	}
+	return solution
+}
+
+// klondikeSolver.search()
+// Receiver Function that explores the positions reachable from the table, depth first.
+// Returns whether a win was found, and whether the budget ran out.
+func (s *klondikeSolver) search(t *klondikeTable) (won bool, exhausted bool) {
+	if t.isWon() {
+		return true, false
+	}
+
+	// Skip positions that were already explored
+	key := t.key()
+	if s.visited[key] {
+		return false, false
+	}
+	s.visited[key] = true
+
+	if s.nodes >= s.budget {
+		return false, true
+	}
+	s.nodes++
+
+	// A safe move to the foundations never hurts: Play it without branching
+	candidates := t.legalMoves()
+	if safe, ok := t.safeFoundationMove(candidates); ok {
+		candidates = []klondikeMove{safe}
+	} else {
+		slices.SortStableFunc(candidates, func(a, b klondikeMove) int {
+			return t.movePriority(b) - t.movePriority(a)
+		})
+	}
+
+	for _, m := range candidates {
+		next := t.clone()
+		next.apply(m, s.drawCount)
+
+		s.path = append(s.path, m)
+		won, exhausted := s.search(&next)
+		if won || exhausted {
+			return won, exhausted
+		}
+		s.path = s.path[:len(s.path)-1]
+	}
+
+	return false, false
+}
+
+// klondikeTable.safeFoundationMove()
+// Receiver Function that picks a move to the foundations that can never block a win.
+// A card is safe to found when it is an Ace or a 2, or when both foundations of the opposite
+// color already hold the card below it and the other foundation of its color is not far behind.
+func (t *klondikeTable) safeFoundationMove(moves []klondikeMove) (klondikeMove, bool) {
+	for _, m := range moves {
+		var c card
+		switch m.kind {
+		case klondikeWasteToFoundation:
+			c = t.waste[len(t.waste)-1]
+		case klondikeTableauToFoundation:
+			faceUp := t.tableau[m.from].faceUp
+			c = faceUp[len(faceUp)-1]
+		default:
+			continue
+		}
+
+		if c.rank() <= 2 {
+			return m, true
+		}
+
+		safe := true
+		for i, foundation := range t.foundations {
+			other := card{suit: deckSuits[i]}
+			switch {
+			case i == c.suitIndex():
+			case other.isRed() != c.isRed() && len(foundation) < c.rank()-1:
+				safe = false
+			case other.isRed() == c.isRed() && len(foundation) < c.rank()-2:
+				safe = false
+			}
+		}
+		if safe {
+			return m, true
+		}
+	}
+	return klondikeMove{}, false
+}
+
+// klondikeTable.movePriority()
+// Receiver Function that scores a move so that the most promising ones are tried first.
+func (t *klondikeTable) movePriority(m klondikeMove) int {
+	switch m.kind {
+	case klondikeWasteToFoundation, klondikeTableauToFoundation:
+		return 100
+	case klondikeTableauToTableau:
+		column := t.tableau[m.from]
+		// Moving the whole run uncovers a face-down card (or frees a column)
+		if m.count == len(column.faceUp) {
+			// A King already at the bottom of its column gains nothing by moving
+			if len(column.faceDown) == 0 && column.faceUp[0].rank() == len(deckValues) {
+				return 0
+			}
+			return 80 + len(column.faceDown)
+		}
+		return 10
+	case klondikeWasteToTableau:
+		return 50
+	case klondikeDraw:
+		return 20
+	case klondikeFoundationToTableau:
+		return 5
+	}
+	return 0
+}
+
+// klondikeTable.key()
+// Receiver Function that builds a compact key identifying the position.
+// Columns are sorted since their order does not change the outcome of the game.
+func (t *klondikeTable) key() string {
+	encode := func(sb *strings.Builder, cards []card) {
+		for _, c := range cards {
+			sb.WriteByte(byte(c.index()))
+		}
+	}
+
+	columns := make([]string, klondikeColumns)
+	for i, column := range t.tableau {
+		var sb strings.Builder
+		encode(&sb, column.faceDown)
+		sb.WriteByte(0xFE)
+		encode(&sb, column.faceUp)
+		columns[i] = sb.String()
+	}
+	slices.Sort(columns)
+
+	var sb strings.Builder
+	for _, column := range columns {
+		sb.WriteString(column)
+		sb.WriteByte(0xFF)
+	}
+	for _, foundation := range t.foundations {
+		sb.WriteByte(byte(len(foundation)))
+	}
+	sb.WriteByte(0xFF)
+	encode(&sb, t.stock)
+	sb.WriteByte(0xFF)
+	encode(&sb, t.waste)
+	return sb.String()
+}
@@ -0,0 +1,202 @@
+/**
+ * @file: Unit tests for the Klondike game and its solver
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"errors"
+	"testing"
+)
+
+// Test Helpers
+// ************
+
+// mustParseCards() parses card strings for test fixtures, failing the test on error.
+func mustParseCards(t *testing.T, strs ...string) []card {
+	t.Helper()
+	cards, err := parseCards(deck(strs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cards
+}
+
+// fullFoundation() returns the cards of a suit from Ace up to (and including) the given rank.
+func fullFoundation(t *testing.T, suit string, upTo int) []card {
+	t.Helper()
+	cards := []card{}
+	for _, value := range deckValues[:upTo] {
+		cards = append(cards, mustParseCards(t, value+" of "+suit)...)
+	}
+	return cards
+}
+
+// Test Cases for newKlondike()
+// ****************************
+//   - Column n should hold n cards with only the top one face-up
+//   - The remaining 24 cards should be in the stock, the waste should be empty
+//   - Invalid decks and draw counts should be rejected
+
+func Test_newKlondike(t *testing.T) {
+	// TEST CASE 1: The tableau and stock are dealt correctly
+	// ------------------------------------------------------
+	k, err := newKlondike(newDeck(), 1)
+	if err != nil {
+		t.Fatalf("Test Case 1: Unexpected error: %v", err)
+	}
+	for i, column := range k.table.tableau {
+		if len(column.faceDown) != i || len(column.faceUp) != 1 {
+			t.Errorf("Test Case 1: Expected column %d to have %d face-down and 1 face-up cards. Got %d and %d", i+1, i, len(column.faceDown), len(column.faceUp))
+		}
+	}
+	if len(k.table.stock) != 24 || len(k.table.waste) != 0 {
+		t.Errorf("Test Case 1: Expected 24 cards in stock and an empty waste. Got %d and %d", len(k.table.stock), len(k.table.waste))
+	}
+
+	// TEST CASE 2: Invalid decks and draw counts are rejected
+	// -------------------------------------------------------
+	if _, err := newKlondike(newDeck(), 2); err == nil {
+		t.Errorf("Test Case 2: Expected an error for a draw count of 2")
+	}
+	hand, _ := newDeck().deal(5)
+	if _, err := newKlondike(hand, 1); err == nil {
+		t.Errorf("Test Case 2: Expected an error for a 5-card deck")
+	}
+	duplicated := newDeck()
+	duplicated[1] = duplicated[0]
+	if _, err := newKlondike(duplicated, 1); err == nil {
+		t.Errorf("Test Case 2: Expected an error for a deck with duplicates")
+	}
+
+	// TEST CASE 3: The same seed gives the same deal
+	// ----------------------------------------------
+	k1, _ := newSeededKlondike(42, 3)
+	k2, _ := newSeededKlondike(42, 3)
+	if k1.toString() != k2.toString() {
+		t.Errorf("Test Case 3: Expected the same deal for the same seed")
+	}
+}
+
+// Test Cases for klondike.play() and klondike.undo()
+// **************************************************
+//   - Every generated move should be valid and playable
+//   - Illegal moves should be rejected without changing the table
+//   - Undo should restore the previous position exactly
+
+func Test_klondikePlayAndUndo(t *testing.T) {
+	k, _ := newSeededKlondike(7, 3)
+	initial := k.toString()
+
+	// TEST CASE 1: Illegal moves are rejected
+	// ---------------------------------------
+	err := k.play(klondikeMove{kind: klondikeTableauToTableau, from: 0, to: 0, count: 1})
+	if !errors.Is(err, errKlondikeIllegalMove) {
+		t.Errorf("Test Case 1: Expected an illegal move error. Got %v", err)
+	}
+	err = k.play(klondikeMove{kind: klondikeWasteToFoundation})
+	if !errors.Is(err, errKlondikeIllegalMove) {
+		t.Errorf("Test Case 1: Expected an illegal move error on an empty waste. Got %v", err)
+	}
+	if k.toString() != initial {
+		t.Errorf("Test Case 1: Expected an illegal move to leave the table untouched")
+	}
+
+	// TEST CASE 2: Generated moves are legal, and undo walks back to the start
+	// ------------------------------------------------------------------------
+	for range 30 {
+		moves := k.legalMoves()
+		if len(moves) == 0 {
+			break
+		}
+		if err := k.play(moves[0]); err != nil {
+			t.Fatalf("Test Case 2: Generated move %s was rejected: %v", moves[0].toString(), err)
+		}
+	}
+	for len(k.moves) > 0 {
+		if err := k.undo(); err != nil {
+			t.Fatalf("Test Case 2: Unexpected undo error: %v", err)
+		}
+	}
+	if k.toString() != initial {
+		t.Errorf("Test Case 2: Expected undo to restore the initial deal")
+	}
+	if !errors.Is(k.undo(), errKlondikeNothingUndo) {
+		t.Errorf("Test Case 2: Expected an error when there is nothing to undo")
+	}
+}
+
+// Test Cases for klondike.isWon() and solveKlondike()
+// ***************************************************
+//   - A nearly finished game should be solved and won by replaying the solution
+//   - A blocked game should be reported as unsolvable
+//   - A tiny budget should be reported as exceeded
+
+func Test_solveKlondike(t *testing.T) {
+	// TEST CASE 1: A nearly finished game is solved
+	// ---------------------------------------------
+	k := &klondike{drawCount: 1}
+	k.table.foundations[0] = fullFoundation(t, "Spade", 11)
+	k.table.foundations[1] = fullFoundation(t, "Diamond", 13)
+	k.table.foundations[2] = fullFoundation(t, "Heart", 12)
+	k.table.foundations[3] = fullFoundation(t, "Club", 13)
+	k.table.tableau[0].faceDown = mustParseCards(t, "K of Spade")
+	k.table.tableau[0].faceUp = mustParseCards(t, "Q of Spade")
+	k.table.stock = mustParseCards(t, "K of Heart")
+
+	if k.isWon() {
+		t.Errorf("Test Case 1: Expected the game not to be won yet")
+	}
+	solution := solveKlondike(k, 1000)
+	if solution.status != klondikeSolvable {
+		t.Fatalf("Test Case 1: Expected the game to be solvable. Got status %d", solution.status)
+	}
+	for _, m := range solution.moves {
+		if err := k.play(m); err != nil {
+			t.Fatalf("Test Case 1: Solution move %s was rejected: %v", m.toString(), err)
+		}
+	}
+	if !k.isWon() {
+		t.Errorf("Test Case 1: Expected the solution to win the game")
+	}
+
+	// TEST CASE 2: A blocked game is unsolvable
+	// -----------------------------------------
+	// Only black cards are left and every column is occupied: The red Kings can never come back down,
+	// and both black Aces are buried under their own 2
+	k = &klondike{drawCount: 1}
+	k.table.foundations[1] = fullFoundation(t, "Diamond", 13)
+	k.table.foundations[2] = fullFoundation(t, "Heart", 13)
+	k.table.tableau[0].faceDown = mustParseCards(t, "A of Spade")
+	k.table.tableau[0].faceUp = mustParseCards(t, "2 of Spade")
+	k.table.tableau[1].faceDown = mustParseCards(t, "A of Club")
+	k.table.tableau[1].faceUp = mustParseCards(t, "2 of Club")
+	rest := []card{}
+	for _, suit := range []string{"Spade", "Club"} {
+		rest = append(rest, fullFoundation(t, suit, 13)[2:]...)
+	}
+	for i, c := range rest {
+		column := &k.table.tableau[2+i%5]
+		column.faceDown = append(column.faceDown, c)
+	}
+	for i := 2; i < klondikeColumns; i++ {
+		k.table.tableau[i].flip()
+	}
+
+	solution = solveKlondike(k, 1000)
+	if solution.status != klondikeUnsolvable {
+		t.Errorf("Test Case 2: Expected the game to be unsolvable. Got status %d", solution.status)
+	}
+
+	// TEST CASE 3: The node budget is respected
+	// -----------------------------------------
+	k, _ = newSeededKlondike(2024, 3)
+	solution = solveKlondike(k, 1)
+	if solution.status != klondikeBudgetExceeded || solution.nodes > 1 {
+		t.Errorf("Test Case 3: Expected the budget of 1 node to be exceeded. Got status %d after %d nodes", solution.status, solution.nodes)
+	}
+}
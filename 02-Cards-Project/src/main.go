@@ -6,39 +6,92 @@ package main
 // *******
 import (
 	"fmt"
+	"math/rand"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/maevadevs/Go-Developer-Foundation/02-Cards-Project/cards"
 )
 
 // Project Structure
 // *****************
 // 02-Cards-Project (Module)
-// |- main.go      - Executable
+// |- cards/       - Library package: The public Deck and Card API (New, Shuffle, Deal, Save, Load)
+// |  |- card.go       - Describes what a Card is
+// |  |- deck.go       - Describes what a Deck is and how it works
+// |  |- cards_test.go - Automated tests and examples for the library
+// |  |- odds.go       - Probability and odds calculator for partial decks: Exact, or Monte Carlo
+// |  |- odds_test.go  - Automated tests for odds.go
+// |  |- shoe.go       - Decks safe to share between goroutines: A synchronized Shoe and a Dealer goroutine
+// |  |- shoe_test.go  - Automated tests for shoe.go, meant for the race detector
+// |  |- replay.go     - Replay files: The seed, initial deck and every action of a game, checked against the seed
+// |  |- replay_test.go - Automated tests for replay.go
+// |  |- stats.go      - Shuffle strategies (legacy, Fisher-Yates, riffle) and randomness statistics
+// |  |- stats_test.go - Automated tests for stats.go
+// |- cmd/replay/main.go - Replay viewer: Steps forward and backward through a replay file, printing the table
+// |- cmd/replay/main_test.go - Automated tests for the replay viewer, on the replay files of cmd/replay/testdata
+// |- cmd/shufflestats/main.go - Shuffle quality report: Position bias, adjacency, rising sequences and chi-square
+// |- cmd/shufflestats/main_test.go - Automated tests for the report, against the reports of cmd/shufflestats/testdata
+// |- main.go      - Executable: A thin command on top of the cards library
 // |- deck.go      - Describes what a Deck type is and how it works
 // |- deck_test.go - Automated tests for deck.go
+// |- card.go      - Describes what a single Card is, parsed from a deck entry
+// |- genericdeck.go - Generic deck container for any card type, saved and loaded through a codec
+// |- genericdeck_test.go - Automated tests for genericdeck.go
+// |- tarot.go     - The 78-card Tarot deck, with upright and reversed cards
+// |- tarot_test.go - Automated tests for tarot.go
+// |- uno.go       - The 108-card Uno deck
+// |- uno_test.go  - Automated tests for uno.go
+// |- klondike.go  - Klondike solitaire: Tableau, foundations, stock/waste, moves and undo
+// |- klondike_solver.go - Decides if a Klondike deal can be won within a node budget
+// |- klondike_test.go   - Automated tests for klondike.go and klondike_solver.go
+// |- tricks.go    - Trick-taking framework: Seats, turns, legal plays, trick winner and scoring
+// |- tricks_bot.go - Simple bot players for trick-taking games
+// |- hearts.go    - Rules of Hearts for the trick-taking framework
+// |- spades.go    - Rules of Spades for the trick-taking framework
+// |- tricks_test.go - Automated tests for tricks.go, hearts.go and spades.go
+// |- games.go     - What the simple games share: Seeded deals, summaries and the console player
+// |- war.go       - The game of War
+// |- gofish.go    - The game of Go Fish
+// |- crazyeights.go - The game of Crazy Eights
+// |- games_test.go  - Automated tests for war.go, gofish.go and crazyeights.go
+// |- table_protocol.go - Line-based protocol between a table server and its clients
+// |- table_server.go   - Table server: Holds the deck, deals hidden hands, manages turns and seats
+// |- table_client.go   - Client library to sit at a table server
+// |- table_test.go     - Automated tests for the table server and client over localhost
+// |- securesave.go      - Signed (HMAC-SHA256) and encrypted (AES-256-GCM) deck save files
+// |- securesave_test.go - Automated tests for securesave.go
+// |- stream.go          - Stream decks to any io.Writer and from any io.Reader, one card at a time
+// |- stream_test.go     - Automated tests for stream.go
+// |- session.go         - Game session: Deck, hands, discards, turn, scores and seed, saved as a unit
+// |- session_test.go    - Automated tests for session.go
+// |- deck_fuzz_test.go  - Fuzz targets, property tests and benchmarks for the deck, its parsers and loaders
 
 // Functions
 // *********
 
 // This is the main entry of the application.
 func main() {
-	// Variables: deck type
-	// playingDeck is essentially a slice of strings
-	playingDeck := newDeck()
+	// Variables: cards.Deck type from the library
+	// playingDeck is a slice of cards
+	playingDeck := cards.New()
 
-	// Calling Type Receiver Function: Deal 5 cards
-	hand, playingDeck := playingDeck.deal(5)
+	// Deal 5 cards
+	hand, playingDeck, err := playingDeck.Deal(5)
+	if err != nil {
+		panic(err)
+	}
 
-	// Calling Type Receiver Function: Print to screen
-	fmt.Print("Current Hand: ")
-	hand.print()
+	// Print to screen
+	fmt.Println("Current Hand:", hand)
 	fmt.Println("---")
 
 	// Convert deck to string an print
 	fmt.Print("Remaining Playing Deck: ")
-	fmt.Println(playingDeck.toString())
+	fmt.Println(playingDeck)
 	fmt.Println("---")
 
 	// Save the playingDeck to file
@@ -87,24 +140,68 @@ func main() {
 	}
 
 	// 6. Save the file in the savPath
-	if err := playingDeck.saveToFile(fmt.Sprintf("%s/datasave_current_deck.sav", savPath)); err != nil {
+	// DECK_SAVE_MODE=signed|encrypted protects the file with the key from DECK_SAVE_KEY or DECK_SAVE_KEY_FILE
+	saveMode, err := parseSaveMode(os.Getenv("DECK_SAVE_MODE"))
+	if err != nil {
 		panic(err)
 	}
+	savFile := fmt.Sprintf("%s/datasave_current_deck.sav", savPath)
+	if saveMode == savePlain {
+		if err := playingDeck.Save(savFile); err != nil {
+			panic(err)
+		}
+
+		// Testing reading from the saved file
+		fmt.Println("--- Reading playingDeck from saved file --- ")
+		if playingDeck, err = cards.Load(savFile); err != nil {
+			panic(err)
+		}
+	} else {
+		key, err := loadSaveKey()
+		if err != nil {
+			panic(err)
+		}
+		if err := saveCardsToSecureFile(playingDeck, savFile, saveMode, key); err != nil {
+			panic(err)
+		}
+
+		// Testing reading from the protected saved file
+		fmt.Println("--- Reading playingDeck from protected saved file --- ")
+		if playingDeck, err = loadCardsFromSecureFile(savFile, key); err != nil {
+			panic(err)
+		}
+	}
+	fmt.Println(playingDeck)
 
-	// Testing reading from the saved file
-	fmt.Println("--- Reading playingDeck from saved file --- ")
-	playingDeck = newDeckFromFile(fmt.Sprintf("%s/datasave_current_deck.sav", savPath))
-	fmt.Println(playingDeck.toString())
+	// 7. Save the whole session: The hand is kept too, so the game can resume
+	session, err := newGameSessionFromCards(0, playingDeck, hand)
+	if err != nil {
+		panic(err)
+	}
+	if err := session.saveToFile(fmt.Sprintf("%s/datasave_current_session.json", savPath)); err != nil {
+		panic(err)
+	}
+	fmt.Println("--- Resuming the session from saved file --- ")
+	session, err = loadGameSession(fmt.Sprintf("%s/datasave_current_session.json", savPath))
+	if err != nil {
+		panic(err)
+	}
+	resumed, err := session.hand(0)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("Resumed Hand:", resumed)
 
 	// Testing Shuffling
-	playingDeck = newDeck()
+	playingDeck = cards.New()
 	fmt.Println("---")
 	fmt.Println("Before Shuffling The Deck:")
-	fmt.Println(playingDeck.toString())
+	fmt.Println(playingDeck)
 	fmt.Println("---")
 	fmt.Println("After Shuffling The Deck:")
-	playingDeck.shuffle(5)
-	fmt.Println(playingDeck.toString())
+	// Time-Based Random Number Generator
+	playingDeck.Shuffle(rand.New(rand.NewSource(time.Now().UnixNano())), 5)
+	fmt.Println(playingDeck)
 }
 
 // FOR WINDOWS:
@@ -114,7 +211,7 @@ func main() {
 //  Compile + Run:          go build -o 02-Cards-Project\bin\Program.exe 02-Cards-Project\src\*.go && .\02-Cards-Project\bin\Program.exe
 
 // FOR LINUX:
-//  To run:                 go run 02-Cards-Project/src/main.go deck.go
+//  To run:                 go run 02-Cards-Project/src/*.go
 //  To compile:             go build -o 02-Cards-Project/bin/Program 02-Cards-Project/src/*.go
 //  To run after compile:   ./02-Cards-Project/bin/Program
 //  Compile + Run:          go build -o 02-Cards-Project/bin/Program 02-Cards-Project/src/*.go && ./02-Cards-Project/bin/Program
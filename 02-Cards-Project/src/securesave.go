@@ -0,0 +1,265 @@
+/**
+ * @file: Describes signed and encrypted deck save files.
+ *
+ * A protected save file starts with a header line naming its format, followed by:
+ *   - Signed:    The deck in plain text and an HMAC-SHA256 tag. Anyone can read it, nobody can edit it.
+ *   - Encrypted: The deck sealed with AES-256-GCM. Nobody can read nor edit it.
+ *
+ * The secret comes from the DECK_SAVE_KEY environment variable, or from the file named by DECK_SAVE_KEY_FILE.
+ * Separate keys for signing and encryption are derived from it with HKDF.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/maevadevs/Go-Developer-Foundation/02-Cards-Project/cards"
+)
+
+// Constants
+// *********
+
+// Protection applied to a save file.
+type saveMode int
+
+const (
+	// No protection: The format of deck.saveToFile()
+	savePlain saveMode = iota
+	// Plain text with an HMAC-SHA256 tag
+	saveSigned
+	// AES-256-GCM authenticated encryption
+	saveEncrypted
+)
+
+const (
+	// Environment variable holding the secret
+	saveKeyEnv = "DECK_SAVE_KEY"
+	// Environment variable holding the path of a file containing the secret
+	saveKeyFileEnv = "DECK_SAVE_KEY_FILE"
+	// Shortest secret accepted
+	saveKeyMinLength = 16
+	// Header lines of protected save files
+	saveSignedHeader    = "DECKSAV1 HMAC-SHA256"
+	saveEncryptedHeader = "DECKSAV1 AES-256-GCM"
+)
+
+// Errors
+// ******
+
+var (
+	errSaveKeyMissing   = fmt.Errorf("no save key: set %s or %s", saveKeyEnv, saveKeyFileEnv)
+	errSaveKeyTooShort  = fmt.Errorf("save key is too short: at least %d bytes are needed", saveKeyMinLength)
+	errSaveTampered     = errors.New("save file was tampered with, or the key is wrong")
+	errSaveNotProtected = errors.New("save file is neither signed nor encrypted")
+	errSaveFormat       = errors.New("save file is malformed")
+)
+
+// Type Declaration
+// ****************
+
+// A saveKey holds the keys derived from the secret.
+type saveKey struct {
+	signing    []byte
+	encryption []byte
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newSaveKey()
+// Derives the signing and encryption keys from a secret.
+func newSaveKey(secret []byte) (saveKey, error) {
+	if len(secret) < saveKeyMinLength {
+		return saveKey{}, errSaveKeyTooShort
+	}
+
+	signing, err := hkdf.Key(sha256.New, secret, nil, "deck save signing", 32)
+	if err != nil {
+		return saveKey{}, err
+	}
+	encryption, err := hkdf.Key(sha256.New, secret, nil, "deck save encryption", 32)
+	if err != nil {
+		return saveKey{}, err
+	}
+	return saveKey{signing: signing, encryption: encryption}, nil
+}
+
+// loadSaveKey()
+// Reads the secret from DECK_SAVE_KEY, or from the file named by DECK_SAVE_KEY_FILE.
+func loadSaveKey() (saveKey, error) {
+	if secret := os.Getenv(saveKeyEnv); secret != "" {
+		return newSaveKey([]byte(secret))
+	}
+
+	if filename := os.Getenv(saveKeyFileEnv); filename != "" {
+		secret, err := os.ReadFile(filename)
+		if err != nil {
+			return saveKey{}, fmt.Errorf("reading save key file: %w", err)
+		}
+		return newSaveKey(bytes.TrimSpace(secret))
+	}
+
+	return saveKey{}, errSaveKeyMissing
+}
+
+// parseSaveMode()
+// Parses the name of a save mode: "plain", "signed" or "encrypted".
+func parseSaveMode(s string) (saveMode, error) {
+	switch strings.ToLower(s) {
+	case "", "plain":
+		return savePlain, nil
+	case "signed":
+		return saveSigned, nil
+	case "encrypted":
+		return saveEncrypted, nil
+	}
+	return savePlain, fmt.Errorf("unknown save mode %q: expected plain, signed or encrypted", s)
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// deck.saveToSecureFile()
+// Receiver Function to save the deck to a file, signed or encrypted with the key.
+func (d deck) saveToSecureFile(filename string, mode saveMode, key saveKey) error {
+	data, err := sealDeck(d, mode, key)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0o600)
+}
+
+// Helper Functions
+// ****************
+
+// newDeckFromSecureFile()
+// Function to load a deck from a signed or encrypted save file.
+// Plain files are refused: A signature could simply have been stripped.
+func newDeckFromSecureFile(filename string, key saveKey) (deck, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return openDeck(data, key)
+}
+
+// saveCardsToSecureFile()
+// Function to save a deck of the cards library to a file, signed or encrypted with the key.
+func saveCardsToSecureFile(d cards.Deck, filename string, mode saveMode, key saveKey) error {
+	return deckFromCards(d).saveToSecureFile(filename, mode, key)
+}
+
+// loadCardsFromSecureFile()
+// Function to load a deck of the cards library from a signed or encrypted save file.
+func loadCardsFromSecureFile(filename string, key saveKey) (cards.Deck, error) {
+	d, err := newDeckFromSecureFile(filename, key)
+	if err != nil {
+		return nil, err
+	}
+	return d.toCards()
+}
+
+// sealDeck()
+// Converts a deck into the content of a protected save file.
+func sealDeck(d deck, mode saveMode, key saveKey) ([]byte, error) {
+	dStr := d.toString()
+
+	switch mode {
+	case savePlain:
+		return []byte(dStr), nil
+
+	case saveSigned:
+		tag := signDeck(saveSignedHeader, dStr, key)
+		return []byte(saveSignedHeader + "\n" + dStr + "\n" + hex.EncodeToString(tag)), nil
+
+	case saveEncrypted:
+		gcm, err := newSaveCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		nonce := make([]byte, gcm.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, err
+		}
+		// The header is authenticated too, so it cannot be swapped
+		sealed := gcm.Seal(nonce, nonce, []byte(dStr), []byte(saveEncryptedHeader))
+		return []byte(saveEncryptedHeader + "\n" + base64.StdEncoding.EncodeToString(sealed)), nil
+	}
+
+	return nil, fmt.Errorf("unknown save mode %d", mode)
+}
+
+// openDeck()
+// Checks and decodes the content of a protected save file.
+func openDeck(data []byte, key saveKey) (deck, error) {
+	header, body, _ := strings.Cut(string(data), "\n")
+
+	switch header {
+	case saveSignedHeader:
+		dStr, tagHex, found := strings.Cut(body, "\n")
+		tag, err := hex.DecodeString(strings.TrimSpace(tagHex))
+		if !found || err != nil {
+			return nil, fmt.Errorf("%w: missing or invalid signature", errSaveFormat)
+		}
+		if !hmac.Equal(tag, signDeck(header, dStr, key)) {
+			return nil, errSaveTampered
+		}
+		return deck(strings.Split(dStr, deckSeparator)), nil
+
+	case saveEncryptedHeader:
+		sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(body))
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid encoding", errSaveFormat)
+		}
+		gcm, err := newSaveCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		if len(sealed) < gcm.NonceSize() {
+			return nil, fmt.Errorf("%w: truncated", errSaveFormat)
+		}
+		nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+		plaintext, err := gcm.Open(nil, nonce, ciphertext, []byte(header))
+		if err != nil {
+			return nil, errSaveTampered
+		}
+		return deck(strings.Split(string(plaintext), deckSeparator)), nil
+	}
+
+	return nil, errSaveNotProtected
+}
+
+// signDeck()
+// Computes the HMAC-SHA256 tag of a signed save file, covering its header and its deck.
+func signDeck(header string, dStr string, key saveKey) []byte {
+	mac := hmac.New(sha256.New, key.signing)
+	mac.Write([]byte(header + "\n" + dStr))
+	return mac.Sum(nil)
+}
+
+// newSaveCipher()
+// Builds the AES-256-GCM cipher for the encryption key.
+func newSaveCipher(key saveKey) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key.encryption)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,131 @@
+/**
+ * @file: Unit tests for signed and encrypted deck save files
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/maevadevs/Go-Developer-Foundation/02-Cards-Project/cards"
+)
+
+// Test Cases for saveToSecureFile() and newDeckFromSecureFile()
+// *************************************************************
+//   - Signed and encrypted files should load back the same deck
+//   - Encrypted files should not reveal the cards
+//   - Tampered files, wrong keys and plain files should be refused
+//   - Decks of the cards library should go through the same files
+
+func Test_saveToSecureFile(t *testing.T) {
+	key, err := newSaveKey([]byte("correct horse battery staple"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrongKey, _ := newSaveKey([]byte("incorrect horse battery staple"))
+	d := newDeck()
+	dir := t.TempDir()
+
+	for _, mode := range []saveMode{saveSigned, saveEncrypted} {
+		filename := filepath.Join(dir, "secure.sav")
+
+		// TEST CASE 1: Round trip
+		// -----------------------
+		if err := d.saveToSecureFile(filename, mode, key); err != nil {
+			t.Fatalf("Test Case 1: Unexpected error in mode %d: %v", mode, err)
+		}
+		loaded, err := newDeckFromSecureFile(filename, key)
+		if err != nil || !slices.Equal(loaded, d) {
+			t.Errorf("Test Case 1: Expected the same deck back in mode %d (%v)", mode, err)
+		}
+
+		// TEST CASE 2: Only encrypted files hide the cards
+		// ------------------------------------------------
+		data, _ := os.ReadFile(filename)
+		if hidden := !strings.Contains(string(data), "A of Spade"); hidden != (mode == saveEncrypted) {
+			t.Errorf("Test Case 2: Unexpected visibility of the cards in mode %d", mode)
+		}
+
+		// TEST CASE 3: Tampering and wrong keys are detected
+		// --------------------------------------------------
+		if _, err := newDeckFromSecureFile(filename, wrongKey); !errors.Is(err, errSaveTampered) {
+			t.Errorf("Test Case 3: Expected a tampering error with the wrong key in mode %d. Got %v", mode, err)
+		}
+		tampered := strings.Replace(string(data), "A of Spade", "K of Spade", 1)
+		if mode == saveEncrypted {
+			// Flip a character of the ciphertext
+			b := []byte(tampered)
+			i := len(b) - 5
+			b[i] ^= 'A' ^ 'B'
+			tampered = string(b)
+		}
+		os.WriteFile(filename, []byte(tampered), 0o600)
+		if _, err := newDeckFromSecureFile(filename, key); !errors.Is(err, errSaveTampered) && !errors.Is(err, errSaveFormat) {
+			t.Errorf("Test Case 3: Expected a tampered file to be refused in mode %d. Got %v", mode, err)
+		}
+	}
+
+	// TEST CASE 4: Plain files are refused
+	// ------------------------------------
+	filename := filepath.Join(dir, "plain.sav")
+	d.saveToFile(filename)
+	if _, err := newDeckFromSecureFile(filename, key); !errors.Is(err, errSaveNotProtected) {
+		t.Errorf("Test Case 4: Expected a plain file to be refused. Got %v", err)
+	}
+
+	// TEST CASE 5: Decks of the cards library
+	// ---------------------------------------
+	hand, _, _ := cards.New().Deal(5)
+	filename = filepath.Join(dir, "cards.sav")
+	if err := saveCardsToSecureFile(hand, filename, saveEncrypted, key); err != nil {
+		t.Fatalf("Test Case 5: Unexpected error: %v", err)
+	}
+	if loaded, err := loadCardsFromSecureFile(filename, key); err != nil || !slices.Equal(loaded, hand) {
+		t.Errorf("Test Case 5: Expected the same hand back. Got %v (%v)", loaded, err)
+	}
+	if _, err := loadCardsFromSecureFile(filename, wrongKey); !errors.Is(err, errSaveTampered) {
+		t.Errorf("Test Case 5: Expected a tampering error with the wrong key. Got %v", err)
+	}
+}
+
+// Test Cases for loadSaveKey()
+// ****************************
+//   - The key should come from the environment variable first, then from the key file
+//   - Missing and short keys should be refused
+
+func Test_loadSaveKey(t *testing.T) {
+	// TEST CASE 1: Missing key
+	// ------------------------
+	t.Setenv(saveKeyEnv, "")
+	t.Setenv(saveKeyFileEnv, "")
+	if _, err := loadSaveKey(); !errors.Is(err, errSaveKeyMissing) {
+		t.Errorf("Test Case 1: Expected a missing key error. Got %v", err)
+	}
+
+	// TEST CASE 2: Key file
+	// ---------------------
+	keyFile := filepath.Join(t.TempDir(), "deck.key")
+	os.WriteFile(keyFile, []byte("a secret from a key file\n"), 0o600)
+	t.Setenv(saveKeyFileEnv, keyFile)
+	fromFile, err := loadSaveKey()
+	expected, _ := newSaveKey([]byte("a secret from a key file"))
+	if err != nil || !slices.Equal(fromFile.encryption, expected.encryption) {
+		t.Errorf("Test Case 2: Expected the key from the key file (%v)", err)
+	}
+
+	// TEST CASE 3: The environment variable wins, and must be long enough
+	// -------------------------------------------------------------------
+	t.Setenv(saveKeyEnv, "short")
+	if _, err := loadSaveKey(); !errors.Is(err, errSaveKeyTooShort) {
+		t.Errorf("Test Case 3: Expected a short key error. Got %v", err)
+	}
+}
@@ -0,0 +1,309 @@
+/**
+ * @file: Describes a game session: The full table state, saved and restored as a unit.
+ *
+ * A session holds the deck, every player's hand, the discard piles, the turn pointer, the scores
+ * and the RNG seed. It is saved as JSON with a format version. Older formats are migrated on load:
+ *   - Version 0: A plain deck save from deck.saveToFile(). It becomes a session without players.
+ *   - Version 1: The current JSON format.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/maevadevs/Go-Developer-Foundation/02-Cards-Project/cards"
+)
+
+// Constants
+// *********
+
+// Format version written by gameSession.saveToFile().
+const sessionVersion = 1
+
+// Errors
+// ******
+
+var (
+	errSessionTooNew  = errors.New("session was saved by a newer version")
+	errSessionInvalid = errors.New("session is invalid")
+)
+
+// Type Declaration
+// ****************
+
+// A gameSession is the full state of a table: Enough to resume a game exactly where it stopped.
+type gameSession struct {
+	seed     int64
+	deck     deck
+	hands    []deck
+	discards []deck
+	turn     int
+	scores   []int
+}
+
+// sessionFile is the JSON form of a session. Decks are kept in their text form, "A of Spade|2 of Spade|...".
+type sessionFile struct {
+	Version  int      `json:"version"`
+	Seed     int64    `json:"seed"`
+	Deck     string   `json:"deck"`
+	Hands    []string `json:"hands"`
+	Discards []string `json:"discards"`
+	Turn     int      `json:"turn"`
+	Scores   []int    `json:"scores"`
+}
+
+// A sessionMigration upgrades a session file from one version to the next.
+type sessionMigration func(data []byte) (sessionFile, error)
+
+// Migrations, indexed by the version they upgrade from.
+var sessionMigrations = map[int]sessionMigration{
+	0: migrateSessionFromDeck,
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newGameSession()
+// Initializes a session: A new deck shuffled with the seed, and a hand of handSize cards for each player.
+func newGameSession(seed int64, players int, handSize int) (*gameSession, error) {
+	if players < 1 || handSize < 0 || players*handSize > len(deckValues)*len(deckSuits) {
+		return nil, fmt.Errorf("%w: cannot deal %d hands of %d cards", errSessionInvalid, players, handSize)
+	}
+
+	s := &gameSession{seed: seed, deck: newDeck(), scores: make([]int, players)}
+	s.deck.shuffleWith(rand.New(rand.NewSource(seed)), 1)
+	for range players {
+		var hand deck
+		hand, s.deck = s.deck.deal(handSize)
+		s.hands = append(s.hands, hand)
+		s.discards = append(s.discards, deck{})
+	}
+	return s, nil
+}
+
+// newGameSessionFromCards()
+// Initializes a session from decks of the cards library: The remaining deck, and the hand of each player.
+// Discard piles start empty and scores at 0. Fails if a card is in two places.
+func newGameSessionFromCards(seed int64, remaining cards.Deck, hands ...cards.Deck) (*gameSession, error) {
+	s := &gameSession{seed: seed, deck: deckFromCards(remaining), scores: make([]int, len(hands))}
+	for _, hand := range hands {
+		s.hands = append(s.hands, deckFromCards(hand))
+		s.discards = append(s.discards, deck{})
+	}
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// loadGameSession()
+// Restores a session from a file written by gameSession.saveToFile(), or from an older format.
+func loadGameSession(filename string) (*gameSession, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readGameSession(f)
+}
+
+// readGameSession()
+// Restores a session from r, migrating older formats to the current version.
+func readGameSession(r io.Reader) (*gameSession, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Version 0 is not JSON: Any other version is read from the "version" field
+	version := 0
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var header struct {
+			Version int `json:"version"`
+		}
+		if err := json.Unmarshal(data, &header); err != nil {
+			return nil, fmt.Errorf("%w: %v", errSessionInvalid, err)
+		}
+		if header.Version < 1 {
+			return nil, fmt.Errorf("%w: version %d", errSessionInvalid, header.Version)
+		}
+		version = header.Version
+	}
+	if version > sessionVersion {
+		return nil, fmt.Errorf("%w: version %d, expected at most %d", errSessionTooNew, version, sessionVersion)
+	}
+
+	var sf sessionFile
+	if version == sessionVersion {
+		if err := json.Unmarshal(data, &sf); err != nil {
+			return nil, fmt.Errorf("%w: %v", errSessionInvalid, err)
+		}
+	} else {
+		// Upgrade one version at a time up to the current one
+		for ; version < sessionVersion; version++ {
+			migrate, found := sessionMigrations[version]
+			if !found {
+				return nil, fmt.Errorf("%w: no migration from version %d", errSessionInvalid, version)
+			}
+			if sf, err = migrate(data); err != nil {
+				return nil, fmt.Errorf("migrating from version %d: %w", version, err)
+			}
+			if data, err = json.Marshal(sf); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	s := &gameSession{
+		seed:     sf.Seed,
+		deck:     deckFromText(sf.Deck),
+		turn:     sf.Turn,
+		scores:   sf.Scores,
+		hands:    make([]deck, len(sf.Hands)),
+		discards: make([]deck, len(sf.Discards)),
+	}
+	for i, h := range sf.Hands {
+		s.hands[i] = deckFromText(h)
+	}
+	for i, p := range sf.Discards {
+		s.discards[i] = deckFromText(p)
+	}
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// gameSession.validate()
+// Receiver Function that checks that the session is consistent: One score and one discard pile per
+// player, a turn pointer on a player, and no card in two places.
+func (s *gameSession) validate() error {
+	players := len(s.hands)
+	if len(s.scores) != players || len(s.discards) != players {
+		return fmt.Errorf("%w: %d hands, %d scores and %d discard piles", errSessionInvalid, players, len(s.scores), len(s.discards))
+	}
+	if s.turn < 0 || (players > 0 && s.turn >= players) || (players == 0 && s.turn != 0) {
+		return fmt.Errorf("%w: turn %d with %d players", errSessionInvalid, s.turn, players)
+	}
+
+	seen := map[string]bool{}
+	piles := append(append([]deck{s.deck}, s.hands...), s.discards...)
+	for _, pile := range piles {
+		for _, c := range pile {
+			if seen[c] {
+				return fmt.Errorf("%w: %s appears twice", errSessionInvalid, c)
+			}
+			seen[c] = true
+		}
+	}
+	return nil
+}
+
+// gameSession.hand()
+// Receiver Function that returns the hand of a player as a deck of the cards library.
+func (s *gameSession) hand(player int) (cards.Deck, error) {
+	if player < 0 || player >= len(s.hands) {
+		return nil, fmt.Errorf("%w: no player %d among %d", errSessionInvalid, player, len(s.hands))
+	}
+	return s.hands[player].toCards()
+}
+
+// gameSession.advanceTurn()
+// Receiver Function that passes the turn to the next player.
+func (s *gameSession) advanceTurn() {
+	if len(s.hands) > 0 {
+		s.turn = (s.turn + 1) % len(s.hands)
+	}
+}
+
+// gameSession.writeTo()
+// Receiver Function that writes the session to w in the current format.
+func (s *gameSession) writeTo(w io.Writer) error {
+	sf := sessionFile{
+		Version:  sessionVersion,
+		Seed:     s.seed,
+		Deck:     s.deck.toString(),
+		Turn:     s.turn,
+		Scores:   s.scores,
+		Hands:    make([]string, len(s.hands)),
+		Discards: make([]string, len(s.discards)),
+	}
+	for i, h := range s.hands {
+		sf.Hands[i] = h.toString()
+	}
+	for i, p := range s.discards {
+		sf.Discards[i] = p.toString()
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sf)
+}
+
+// gameSession.saveToFile()
+// Receiver Function that saves the session as a unit: It is written to a temporary file first,
+// then renamed, so that a crash never leaves half a session behind.
+func (s *gameSession) saveToFile(filename string) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := s.writeTo(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
+
+// Helper Functions
+// ****************
+
+// deckFromText()
+// Converts the text form of a deck back into a deck. An empty text is an empty deck.
+func deckFromText(s string) deck {
+	if s == "" {
+		return deck{}
+	}
+	return deck(strings.Split(s, deckSeparator))
+}
+
+// migrateSessionFromDeck()
+// Migrates version 0, a plain deck save, into a session holding that deck and no players.
+func migrateSessionFromDeck(data []byte) (sessionFile, error) {
+	if !utf8.Valid(data) {
+		return sessionFile{}, fmt.Errorf("%w: the deck is not text", errSessionInvalid)
+	}
+	return sessionFile{
+		Version:  1,
+		Deck:     strings.TrimSpace(string(data)),
+		Hands:    []string{},
+		Discards: []string{},
+		Scores:   []int{},
+	}, nil
+}
@@ -0,0 +1,124 @@
+/**
+ * @file: Unit tests for game session persistence
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/maevadevs/Go-Developer-Foundation/02-Cards-Project/cards"
+)
+
+// Test Cases for newGameSession(), newGameSessionFromCards() and gameSession.saveToFile()
+// ****************************************************************************************
+//   - A new session should deal a hand to each player from a seeded deck
+//   - A saved session should load back exactly, hands, discards, turn and scores included
+//   - A session should be built from decks of the cards library, and give its hands back
+
+func Test_gameSession(t *testing.T) {
+	// TEST CASE 1: New session
+	// ------------------------
+	s, err := newGameSession(42, 3, 5)
+	if err != nil || len(s.hands) != 3 || len(s.hands[2]) != 5 || len(s.deck) != 37 {
+		t.Fatalf("Test Case 1: Expected 3 hands of 5 and 37 cards left (%v)", err)
+	}
+	again, _ := newGameSession(42, 3, 5)
+	if !reflect.DeepEqual(s, again) {
+		t.Errorf("Test Case 1: Expected the same session for the same seed")
+	}
+	if _, err := newGameSession(1, 11, 5); !errors.Is(err, errSessionInvalid) {
+		t.Errorf("Test Case 1: Expected an error dealing 55 cards. Got %v", err)
+	}
+
+	// TEST CASE 2: Save and resume
+	// ----------------------------
+	s.discards[0] = append(s.discards[0], s.hands[0][0])
+	s.hands[0] = s.hands[0][1:]
+	s.scores[1] = 7
+	s.advanceTurn()
+	filename := filepath.Join(t.TempDir(), "session.json")
+	if err := s.saveToFile(filename); err != nil {
+		t.Fatalf("Test Case 2: Unexpected error: %v", err)
+	}
+	loaded, err := loadGameSession(filename)
+	if err != nil || !reflect.DeepEqual(loaded, s) {
+		t.Errorf("Test Case 2: Expected the same session back. Got %+v (%v)", loaded, err)
+	}
+
+	// TEST CASE 3: Inconsistent sessions are not saved
+	// ------------------------------------------------
+	s.hands[1] = append(s.hands[1], s.deck[0])
+	if err := s.saveToFile(filename); !errors.Is(err, errSessionInvalid) {
+		t.Errorf("Test Case 3: Expected an error for a card in two places. Got %v", err)
+	}
+
+	// TEST CASE 4: Session from decks of the cards library
+	// ----------------------------------------------------
+	hand, remaining, _ := cards.New().Deal(5)
+	fromCards, err := newGameSessionFromCards(7, remaining, hand)
+	if err != nil || len(fromCards.hands) != 1 || len(fromCards.deck) != 47 || fromCards.seed != 7 {
+		t.Fatalf("Test Case 4: Expected 1 hand and 47 cards left (%v)", err)
+	}
+	if back, err := fromCards.hand(0); err != nil || !slices.Equal(back, hand) {
+		t.Errorf("Test Case 4: Expected the same hand back. Got %v (%v)", back, err)
+	}
+	if _, err := fromCards.hand(1); !errors.Is(err, errSessionInvalid) {
+		t.Errorf("Test Case 4: Expected an error for a missing player. Got %v", err)
+	}
+	if _, err := newGameSessionFromCards(7, cards.New(), hand); !errors.Is(err, errSessionInvalid) {
+		t.Errorf("Test Case 4: Expected an error for a hand still in the deck. Got %v", err)
+	}
+}
+
+// Test Cases for readGameSession() migrations
+// *******************************************
+//   - A plain deck save (version 0) should become a session without players
+//   - Sessions from a newer version should be refused
+//   - JSON without a version, and version 0 saves that are not text, should be refused
+
+func Test_readGameSession(t *testing.T) {
+	// TEST CASE 1: Version 0
+	// ----------------------
+	filename := filepath.Join(t.TempDir(), "legacy.sav")
+	newDeck().saveToFile(filename)
+	s, err := loadGameSession(filename)
+	if err != nil || !slices.Equal(s.deck, newDeck()) || len(s.hands) != 0 {
+		t.Errorf("Test Case 1: Expected the legacy deck in a session without players (%v)", err)
+	}
+
+	// TEST CASE 2: Newer version
+	// --------------------------
+	os.WriteFile(filename, []byte(`{"version": 99}`), 0o666)
+	if _, err := loadGameSession(filename); !errors.Is(err, errSessionTooNew) {
+		t.Errorf("Test Case 2: Expected a newer version error. Got %v", err)
+	}
+
+	// TEST CASE 3: Malformed JSON
+	// ---------------------------
+	if _, err := readGameSession(strings.NewReader(`{"version": 1, "turn": `)); !errors.Is(err, errSessionInvalid) {
+		t.Errorf("Test Case 3: Expected an invalid session error. Got %v", err)
+	}
+
+	// TEST CASE 4: Missing version, and binary data
+	// ---------------------------------------------
+	for _, data := range []string{`{"hands": ["A of Spade"]}`, `{"version": 0}`, `{"version": -1}`} {
+		if _, err := readGameSession(strings.NewReader(data)); !errors.Is(err, errSessionInvalid) {
+			t.Errorf("Test Case 4: Expected an invalid session error for %s. Got %v", data, err)
+		}
+	}
+	// Found by Fuzz_readGameSession: testdata/fuzz/Fuzz_readGameSession
+	if _, err := readGameSession(strings.NewReader("\x96")); !errors.Is(err, errSessionInvalid) {
+		t.Errorf("Test Case 4: Expected an invalid session error for bytes that are not text. Got %v", err)
+	}
+}
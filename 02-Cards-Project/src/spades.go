@@ -0,0 +1,139 @@
+/**
+ * @file: Describes the rules of Spades for the trick-taking framework.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import "slices"
+
+// Constants
+// *********
+
+const (
+	// The game ends when a team reaches this score...
+	spadesGameOver = 500
+	// ...or falls down to this one
+	spadesGameLost = -200
+	// Bonus (or penalty) of a nil bid
+	spadesNilBonus = 100
+	// Every 10 overtricks ("bags") cost 100 points
+	spadesBagLimit   = 10
+	spadesBagPenalty = 100
+)
+
+// Type Declaration
+// ****************
+
+// spadesRules are the rules of Spades for 4 players in 2 teams (seats 0 & 2 against 1 & 3).
+//   - Spades are always trump and cannot be led until broken, unless there is nothing else left
+//   - Each team scores 10 points per trick bid when it makes its contract, or loses them otherwise
+//   - Overtricks are worth 1 point but accumulate as bags: Every 10 bags cost 100 points
+//   - A bid of 0 (nil) is scored on its own: +100 if the player takes no trick, -100 otherwise
+//
+// Both players of a team carry the team's score.
+type spadesRules struct {
+	bags [2]int
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+func (*spadesRules) name() string  { return "Spades" }
+func (*spadesRules) seats() int    { return 4 }
+func (*spadesRules) cards() deck   { return newDeck() }
+func (*spadesRules) trump() string { return "Spade" }
+func (*spadesRules) bidding() bool { return true }
+
+// spadesRules.firstLeader()
+// The player after the dealer leads first.
+func (*spadesRules) firstLeader(round *trickRound) int {
+	return (round.dealer + 1) % len(round.hands)
+}
+
+// spadesRules.legalPlays()
+// Cards the seat may play in the current trick.
+func (*spadesRules) legalPlays(round *trickRound, seat int) []card {
+	hand := round.hands[seat]
+
+	// Leading: No Spade until broken
+	if len(round.trick) == 0 {
+		if !round.suitPlayed("Spade") {
+			noSpades := slices.DeleteFunc(slices.Clone(hand), func(c card) bool { return c.suit == "Spade" })
+			if len(noSpades) > 0 {
+				return noSpades
+			}
+		}
+		return slices.Clone(hand)
+	}
+
+	// Following: Follow suit if possible
+	return followSuit(hand, round.trick[0].card.suit)
+}
+
+// spadesRules.strength()
+// Spades are trump.
+func (*spadesRules) strength(c card, leadSuit string) int {
+	return standardStrength(c, leadSuit, "Spade")
+}
+
+// spadesRules.scoreRound()
+// Points scored by each team, given to both of its seats.
+func (r *spadesRules) scoreRound(round *trickRound, _ []int) []int {
+	points := make([]int, len(round.hands))
+
+	for team := range r.bags {
+		bid, tricks, teamPoints := 0, 0, 0
+
+		for _, seat := range []int{team, team + 2} {
+			tricks += round.tricksWon[seat]
+			if round.bids[seat] > 0 {
+				bid += round.bids[seat]
+				continue
+			}
+			// Nil bid
+			if round.tricksWon[seat] == 0 {
+				teamPoints += spadesNilBonus
+			} else {
+				teamPoints -= spadesNilBonus
+			}
+		}
+
+		// Contract
+		if bid > 0 {
+			if tricks >= bid {
+				overtricks := tricks - bid
+				teamPoints += 10*bid + overtricks
+				r.bags[team] += overtricks
+			} else {
+				teamPoints -= 10 * bid
+			}
+		}
+
+		// Bag penalty
+		for r.bags[team] >= spadesBagLimit {
+			r.bags[team] -= spadesBagLimit
+			teamPoints -= spadesBagPenalty
+		}
+
+		points[team] = teamPoints
+		points[team+2] = teamPoints
+	}
+
+	return points
+}
+
+// spadesRules.isGameOver()
+// The game ends once a team reaches 500 points or falls to -200.
+func (*spadesRules) isGameOver(scores []int) bool {
+	return slices.Max(scores) >= spadesGameOver || slices.Min(scores) <= spadesGameLost
+}
+
+// spadesRules.winners()
+// The highest score wins: Both players of the team.
+func (*spadesRules) winners(scores []int) []int {
+	return highestScores(scores)
+}
@@ -0,0 +1,236 @@
+/**
+ * @file: Describes how decks are streamed to any io.Writer and from any io.Reader:
+ * Files, network connections, gzip streams, in-memory buffers or HTTP bodies.
+ *
+ * The stream format is the text form of deck.toString(): Cards separated by "|", and ended by a newline.
+ * Cards are written and read one at a time, so a large shoe or a long history never has to be held
+ * in memory twice. The decoder reads up to the newline and never past it: A deck can share a stream,
+ * such as a network connection, with other messages.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Constants
+// *********
+
+// End of a deck in a stream. A stream may also end without it, as a file written by deck.saveToFile().
+const deckTerminator = '\n'
+
+// Type Declaration
+// ****************
+
+// A deckEncoder writes cards of type T to a stream, one at a time.
+type deckEncoder[T any] struct {
+	w     *bufio.Writer
+	codec deckCodec[T]
+	count int
+}
+
+// A deckDecoder reads cards of type T from a stream, one at a time.
+type deckDecoder[T any] struct {
+	r     io.ByteReader
+	codec deckCodec[T]
+	count int
+	done  bool
+}
+
+// countingWriter counts the bytes written through it, to report them from deck.WriteTo().
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// countingReader counts the bytes read through it, to report them from deck.ReadFrom().
+type countingReader struct {
+	r io.ByteReader
+	n int64
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newDeckEncoder()
+// Initializes an encoder that writes cards to w through the codec.
+func newDeckEncoder[T any](w io.Writer, codec deckCodec[T]) *deckEncoder[T] {
+	return &deckEncoder[T]{w: bufio.NewWriter(w), codec: codec}
+}
+
+// newDeckDecoder()
+// Initializes a decoder that reads cards from r through the codec.
+// A reader that is not an io.ByteReader is buffered, so it may be read past the deck:
+// Pass a bufio.Reader to read what follows the deck from it.
+func newDeckDecoder[T any](r io.Reader, codec deckCodec[T]) *deckDecoder[T] {
+	return &deckDecoder[T]{r: asByteReader(r), codec: codec}
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// deckEncoder.encode()
+// Receiver Function that writes one card to the stream.
+func (e *deckEncoder[T]) encode(c T) error {
+	s := e.codec.encode(c)
+	if strings.Contains(s, deckSeparator) || strings.ContainsRune(s, deckTerminator) {
+		return fmt.Errorf("card %d: encoded card %q contains the separator %q or a newline", e.count+1, s, deckSeparator)
+	}
+	if e.count > 0 {
+		if _, err := e.w.WriteString(deckSeparator); err != nil {
+			return err
+		}
+	}
+	if _, err := e.w.WriteString(s); err != nil {
+		return err
+	}
+	e.count++
+	return nil
+}
+
+// deckEncoder.close()
+// Receiver Function that ends the deck, and writes any buffered cards to the stream.
+// Call it once all cards are encoded. The stream itself stays open.
+func (e *deckEncoder[T]) close() error {
+	if err := e.w.WriteByte(deckTerminator); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
+
+// deckDecoder.next()
+// Receiver Function that reads the next card from the stream. Returns io.EOF after the last card:
+// At the end of the deck, or of the stream.
+func (dec *deckDecoder[T]) next() (T, error) {
+	var zero T
+	if dec.done {
+		return zero, io.EOF
+	}
+
+	var sb strings.Builder
+	for {
+		b, err := dec.r.ReadByte()
+		if errors.Is(err, io.EOF) || (err == nil && b == deckTerminator) {
+			// Last card: Not followed by a separator
+			dec.done = true
+			if sb.Len() == 0 && dec.count == 0 {
+				// Empty deck
+				return zero, io.EOF
+			}
+			break
+		}
+		if err != nil {
+			return zero, err
+		}
+		if b == deckSeparator[0] {
+			break
+		}
+		sb.WriteByte(b)
+	}
+
+	c, err := dec.codec.decode(sb.String())
+	if err != nil {
+		return zero, fmt.Errorf("card %d: %w", dec.count+1, err)
+	}
+	dec.count++
+	return c, nil
+}
+
+// genericDeck.writeTo()
+// Receiver Function that streams the deck to w, encoding each card with the codec.
+func (d genericDeck[T]) writeTo(w io.Writer, codec deckCodec[T]) error {
+	enc := newDeckEncoder(w, codec)
+	for _, c := range d {
+		if err := enc.encode(c); err != nil {
+			return err
+		}
+	}
+	return enc.close()
+}
+
+// deck.WriteTo()
+// Receiver Function that streams the deck to w. Implements io.WriterTo, so io.Copy() can use it.
+func (d deck) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	err := genericDeck[string](d).writeTo(cw, stringCodec{})
+	return cw.n, err
+}
+
+// deck.ReadFrom()
+// Receiver Function that replaces the deck with the one streamed from r. Implements io.ReaderFrom.
+// Only the deck is read from an io.ByteReader, up to its newline: What follows is left in r.
+// Any other reader is buffered, and may be read past the deck.
+func (d *deck) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: asByteReader(r)}
+	read, err := readGenericDeck(cr, stringCodec{})
+	if err != nil {
+		return cr.n, err
+	}
+	*d = deck(read)
+	return cr.n, nil
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+func (cr *countingReader) ReadByte() (byte, error) {
+	b, err := cr.r.ReadByte()
+	if err == nil {
+		cr.n++
+	}
+	return b, err
+}
+
+// countingReader.Read() reads a single byte: The decoder goes through ReadByte().
+func (cr *countingReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	b, err := cr.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	p[0] = b
+	return 1, nil
+}
+
+// Helper Functions
+// ****************
+
+// asByteReader()
+// Returns r if it reads byte by byte already, otherwise r buffered.
+func asByteReader(r io.Reader) io.ByteReader {
+	if br, ok := r.(io.ByteReader); ok {
+		return br
+	}
+	return bufio.NewReader(r)
+}
+
+// readGenericDeck()
+// Reads a deck from r, up to its newline or the end of r, decoding each card with the codec.
+func readGenericDeck[T any](r io.Reader, codec deckCodec[T]) (genericDeck[T], error) {
+	d := genericDeck[T]{}
+	dec := newDeckDecoder(r, codec)
+	for {
+		c, err := dec.next()
+		if errors.Is(err, io.EOF) {
+			return d, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		d = append(d, c)
+	}
+}
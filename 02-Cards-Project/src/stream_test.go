@@ -0,0 +1,166 @@
+/**
+ * @file: Unit tests for streaming decks over io.Reader and io.Writer
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Test Cases for deck.WriteTo() and deck.ReadFrom()
+// *************************************************
+//   - The stream should be the same text as deck.toString(), ended by a newline
+//   - A deck should go through a buffer and a gzip stream unchanged
+//   - An empty stream should give an empty deck
+//   - Decks should share a buffered stream with other messages: Nothing past a deck should be read
+//   - A reader that does not read byte by byte should be buffered, not read one byte per call
+
+func Test_deckWriteToReadFrom(t *testing.T) {
+	d := newDeck()
+
+	// TEST CASE 1: Same text as toString()
+	// ------------------------------------
+	var buf bytes.Buffer
+	n, err := d.WriteTo(&buf)
+	if err != nil || buf.String() != d.toString()+"\n" || n != int64(buf.Len()) {
+		t.Errorf("Test Case 1: Expected the text of toString() with a newline, and its length. Got %d bytes (%v)", n, err)
+	}
+
+	// TEST CASE 2: Through a buffer
+	// -----------------------------
+	var loaded deck
+	if _, err := loaded.ReadFrom(&buf); err != nil || !slices.Equal(loaded, d) {
+		t.Errorf("Test Case 2: Expected the same deck back (%v)", err)
+	}
+
+	// TEST CASE 3: Through a gzip stream
+	// ----------------------------------
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	d.WriteTo(zw)
+	zw.Close()
+	zr, err := gzip.NewReader(&gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded = nil
+	if _, err := loaded.ReadFrom(zr); err != nil || !slices.Equal(loaded, d) {
+		t.Errorf("Test Case 3: Expected the same deck back from gzip (%v)", err)
+	}
+
+	// TEST CASE 4: Empty stream
+	// -------------------------
+	if _, err := loaded.ReadFrom(strings.NewReader("")); err != nil || len(loaded) != 0 {
+		t.Errorf("Test Case 4: Expected an empty deck. Got %d cards (%v)", len(loaded), err)
+	}
+
+	// TEST CASE 5: A stream shared with other messages
+	// ------------------------------------------------
+	var shared bytes.Buffer
+	hand := d[:5]
+	d.WriteTo(&shared)
+	hand.WriteTo(&shared)
+	deck{}.WriteTo(&shared)
+	shared.WriteString("HELLO\n")
+	// Hide the io.ByteReader of the buffer, as a network connection would, and buffer it once for all messages
+	conn := bufio.NewReader(struct{ io.Reader }{&shared})
+	var first, second, empty deck
+	n1, err1 := first.ReadFrom(conn)
+	_, err2 := second.ReadFrom(conn)
+	_, err3 := empty.ReadFrom(conn)
+	if err1 != nil || err2 != nil || err3 != nil || !slices.Equal(first, d) || !slices.Equal(second, hand) || len(empty) != 0 {
+		t.Errorf("Test Case 5: Expected the deck, the hand and an empty deck in turn. Got %d, %d and %d cards (%v, %v, %v)",
+			len(first), len(second), len(empty), err1, err2, err3)
+	}
+	if rest, _ := io.ReadAll(conn); string(rest) != "HELLO\n" || n1 != int64(len(d.toString())+1) {
+		t.Errorf("Test Case 5: Expected the message after the decks left unread. Got %q, and %d bytes read", rest, n1)
+	}
+
+	// TEST CASE 6: A reader without ReadByte()
+	// ----------------------------------------
+	var stream bytes.Buffer
+	d.WriteTo(&stream)
+	size := stream.Len()
+	reads := &readCounter{r: &stream}
+	loaded = nil
+	if n, err := loaded.ReadFrom(reads); err != nil || !slices.Equal(loaded, d) || n != int64(size) {
+		t.Errorf("Test Case 6: Expected the deck back in %d bytes. Got %d cards in %d bytes (%v)", size, len(loaded), n, err)
+	}
+	if reads.calls >= size/2 {
+		t.Errorf("Test Case 6: Expected the %d bytes read through a buffer. Got %d calls to Read()", size, reads.calls)
+	}
+}
+
+// readCounter counts the calls to Read() on r, and hides any io.ByteReader of r.
+type readCounter struct {
+	r     io.Reader
+	calls int
+}
+
+func (rc *readCounter) Read(p []byte) (int, error) {
+	rc.calls++
+	return rc.r.Read(p)
+}
+
+// Test Cases for deckEncoder and deckDecoder
+// ******************************************
+//   - A multi-deck shoe should stream card by card through a pipe
+//   - Invalid cards should be reported with their position
+
+func Test_deckEncoderDecoder(t *testing.T) {
+	// TEST CASE 1: Stream an 8-deck shoe through a pipe, card by card
+	// ---------------------------------------------------------------
+	shoe := genericDeck[card]{}
+	for range 8 {
+		shoe = append(shoe, newFrenchDeck()...)
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(shoe.writeTo(pw, cardCodec{}))
+	}()
+	dec := newDeckDecoder(pr, cardCodec{})
+	count := 0
+	for {
+		c, err := dec.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Test Case 1: Unexpected error: %v", err)
+		}
+		if c != shoe[count] {
+			t.Fatalf("Test Case 1: Expected %s at position %d. Got %s", shoe[count].toString(), count, c.toString())
+		}
+		count++
+	}
+	if count != 8*52 {
+		t.Errorf("Test Case 1: Expected %d cards. Got %d", 8*52, count)
+	}
+
+	// TEST CASE 2: Invalid cards
+	// --------------------------
+	_, err := readGenericDeck(strings.NewReader("A of Spade|Z of Spade"), cardCodec{})
+	if err == nil || !strings.Contains(err.Error(), "card 2") {
+		t.Errorf("Test Case 2: Expected an error on card 2. Got %v", err)
+	}
+	var buf bytes.Buffer
+	enc := newDeckEncoder(&buf, stringCodec{})
+	if err := enc.encode("Pipe|Card"); err == nil {
+		t.Errorf("Test Case 2: Expected an error encoding a card with the separator")
+	}
+	if err := enc.encode("Two\nLines"); err == nil {
+		t.Errorf("Test Case 2: Expected an error encoding a card with a newline")
+	}
+}
@@ -0,0 +1,216 @@
+/**
+ * @file: Describes a client library to sit at a table server.
+ * The client keeps track of its seat, resume token, own hand and the public state of the table.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Constants
+// *********
+
+// Messages kept for the reader of the messages channel
+const tableClientBuffer = 256
+
+// Errors
+// ******
+
+var errTableClosed = errors.New("table connection closed")
+
+// Type Declaration
+// ****************
+
+// A tableClient is a connection to a table server.
+// Every message received is also delivered on the messages channel, in order. The channel is bounded:
+// When nobody reads it, the oldest messages are dropped, and the local state keeps up with the server.
+type tableClient struct {
+	conn     net.Conn
+	messages chan tableMessage
+	writeMu  sync.Mutex
+
+	mu    sync.Mutex
+	seat  int
+	token string
+	hand  deck
+	state tableView
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// dialTable()
+// Connects to a table server. Call join() or resume() next to get a seat.
+func dialTable(addr string, timeout time.Duration) (*tableClient, error) {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &tableClient{conn: conn, messages: make(chan tableMessage, tableClientBuffer), seat: -1}
+	go c.readLoop()
+	return c, nil
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// tableClient.readLoop()
+// Receiver Function that reads the messages of the server, keeps the local state in sync
+// and forwards every message on the messages channel. The channel is closed on disconnection.
+func (c *tableClient) readLoop() {
+	defer close(c.messages)
+
+	scanner := bufio.NewScanner(c.conn)
+	for scanner.Scan() {
+		m := parseTableMessage(scanner.Text())
+
+		c.mu.Lock()
+		switch m.command {
+		case tableWelcome:
+			seat, token, _ := strings.Cut(m.payload, " ")
+			c.seat, _ = strconv.Atoi(seat)
+			c.token = token
+		case tableHand:
+			c.hand = deck{}
+			if m.payload != "" {
+				c.hand = deck(strings.Split(m.payload, "|"))
+			}
+		case tableState:
+			var state tableView
+			if err := json.Unmarshal([]byte(m.payload), &state); err == nil {
+				c.state = state
+			}
+		}
+		c.mu.Unlock()
+
+		c.deliver(m)
+	}
+}
+
+// tableClient.deliver()
+// Receiver Function that puts a message on the messages channel without waiting.
+// When the channel is full, the oldest message makes room for it.
+func (c *tableClient) deliver(m tableMessage) {
+	for {
+		select {
+		case c.messages <- m:
+			return
+		default:
+		}
+		select {
+		case <-c.messages:
+		default:
+		}
+	}
+}
+
+// tableClient.send()
+// Receiver Function that sends a command to the server.
+func (c *tableClient) send(command string, payload string) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := fmt.Fprintln(c.conn, tableMessage{command, payload}.toString())
+	return err
+}
+
+// Actions available to a player.
+func (c *tableClient) join(name string) error    { return c.send(tableJoin, name) }
+func (c *tableClient) resume(token string) error { return c.send(tableResume, token) }
+func (c *tableClient) start() error              { return c.send(tableStart, "") }
+func (c *tableClient) draw() error               { return c.send(tableDraw, "") }
+func (c *tableClient) play(card string) error    { return c.send(tablePlay, card) }
+func (c *tableClient) pass() error               { return c.send(tablePass, "") }
+func (c *tableClient) leave() error              { return c.send(tableLeave, "") }
+
+// tableClient.reconnect()
+// Receiver Function that connects again to the server of a dropped client, and resumes its seat with its token.
+// Returns the new client once its seat and hand are back. The dropped client is closed.
+func (c *tableClient) reconnect(timeout time.Duration) (*tableClient, error) {
+	_, token := c.currentSeat()
+	if token == "" {
+		return nil, errors.New("no seat to resume")
+	}
+	c.close()
+	next, err := dialTable(c.conn.RemoteAddr().String(), timeout)
+	if err != nil {
+		return nil, err
+	}
+	if err := next.resume(token); err != nil {
+		next.close()
+		return nil, err
+	}
+	// The server sends the seat, then the hand
+	if _, err := next.waitFor(tableHand, timeout); err != nil {
+		next.close()
+		return nil, err
+	}
+	return next, nil
+}
+
+// tableClient.close()
+// Receiver Function that drops the connection without leaving: The seat can be resumed.
+func (c *tableClient) close() error {
+	return c.conn.Close()
+}
+
+// tableClient.waitFor()
+// Receiver Function that waits for the next message with the given command, skipping any other.
+// An ERROR message is returned as an error.
+func (c *tableClient) waitFor(command string, timeout time.Duration) (tableMessage, error) {
+	deadline := time.After(timeout)
+	for {
+		select {
+		case m, ok := <-c.messages:
+			if !ok {
+				return tableMessage{}, errTableClosed
+			}
+			if m.command == command {
+				return m, nil
+			}
+			if m.command == tableError {
+				return m, fmt.Errorf("table error: %s", m.payload)
+			}
+		case <-deadline:
+			return tableMessage{}, fmt.Errorf("timed out waiting for %s", command)
+		}
+	}
+}
+
+// tableClient.currentSeat()
+// Receiver Function that returns the seat of the client (-1 before being welcomed) and its resume token.
+func (c *tableClient) currentSeat() (int, string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.seat, c.token
+}
+
+// tableClient.currentHand()
+// Receiver Function that returns a copy of the client's own hand.
+func (c *tableClient) currentHand() deck {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append(deck{}, c.hand...)
+}
+
+// tableClient.currentState()
+// Receiver Function that returns the last public state received.
+func (c *tableClient) currentState() tableView {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
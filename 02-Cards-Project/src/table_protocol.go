@@ -0,0 +1,113 @@
+/**
+ * @file: Describes the line-based protocol spoken between a table server and its clients.
+ *
+ * Every message is a single line: "<COMMAND> <payload>". The payload may contain spaces (cards do).
+ *
+ * Client -> Server:
+ *   JOIN <name>        Sit at the table
+ *   RESUME <token>     Take back a seat after a disconnection
+ *   START              Deal the cards (at least 2 players)
+ *   DRAW               Draw a card from the stock
+ *   PLAY <card>        Play a card from the hand onto the discard pile
+ *   PASS               End the turn without playing
+ *   LEAVE              Leave the table for good
+ *
+ * Server -> Client:
+ *   WELCOME <seat> <token>  Seat granted (keep the token to resume)
+ *   HAND <cards>            The player's own hand, pipe-separated: Only ever sent to its owner
+ *   STATE <json>            Public state of the table (see tableView)
+ *   JOINED/LEFT/DROPPED/RESUMED <seat>
+ *   DREW/PASSED/TIMEOUT <seat>
+ *   PLAYED <seat> <card>
+ *   TURN <seat>
+ *   OVER <seat>             The seat emptied its hand and won
+ *   ERROR <message>
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"fmt"
+	"strings"
+)
+
+// Constants
+// *********
+
+// Commands of the table protocol.
+const (
+	tableJoin    = "JOIN"
+	tableResume  = "RESUME"
+	tableStart   = "START"
+	tableDraw    = "DRAW"
+	tablePlay    = "PLAY"
+	tablePass    = "PASS"
+	tableLeave   = "LEAVE"
+	tableWelcome = "WELCOME"
+	tableHand    = "HAND"
+	tableState   = "STATE"
+	tableJoined  = "JOINED"
+	tableLeft    = "LEFT"
+	tableDropped = "DROPPED"
+	tableResumed = "RESUMED"
+	tableDrew    = "DREW"
+	tablePlayed  = "PLAYED"
+	tablePassed  = "PASSED"
+	tableTimeout = "TIMEOUT"
+	tableTurn    = "TURN"
+	tableOver    = "OVER"
+	tableError   = "ERROR"
+)
+
+// Type Declaration
+// ****************
+
+// A tableMessage is a single line of the protocol.
+type tableMessage struct {
+	command string
+	payload string
+}
+
+// A tableViewPlayer is the public information about a seat.
+type tableViewPlayer struct {
+	Seat      int    `json:"seat"`
+	Name      string `json:"name"`
+	Cards     int    `json:"cards"`
+	Connected bool   `json:"connected"`
+}
+
+// A tableView is the public state of the table, sent to every client. It never contains cards
+// that are hidden: Only the top of the discard pile is visible.
+type tableView struct {
+	Started bool              `json:"started"`
+	Turn    int               `json:"turn"`
+	Top     string            `json:"top"`
+	Stock   int               `json:"stock"`
+	Players []tableViewPlayer `json:"players"`
+}
+
+// Helper Functions
+// ****************
+
+// parseTableMessage()
+// Parses a line of the protocol into a message. The command is case-insensitive.
+func parseTableMessage(line string) tableMessage {
+	command, payload, _ := strings.Cut(strings.TrimSpace(line), " ")
+	return tableMessage{command: strings.ToUpper(command), payload: strings.TrimSpace(payload)}
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// tableMessage.toString()
+// Receiver Function to convert a message into its line representation (without the newline).
+func (m tableMessage) toString() string {
+	if m.payload == "" {
+		return m.command
+	}
+	return fmt.Sprintf("%s %s", m.command, m.payload)
+}
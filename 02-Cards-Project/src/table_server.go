@@ -0,0 +1,632 @@
+/**
+ * @file: Describes a table server: The deck lives on the server and each client only sees its own hand.
+ * Players take turns to draw, play or pass. The first player to empty their hand wins.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	mathrand "math/rand"
+	"net"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Constants
+// *********
+
+const (
+	// Default number of seats at a table
+	tableMaxSeats = 6
+	// Default number of cards dealt to each player
+	tableHandSize = 5
+	// Default time a player has to act before their turn is passed
+	tableTurnTimeout = 30 * time.Second
+	// Default time a disconnected player has to resume before losing their seat
+	tableResumeGrace = 60 * time.Second
+	// Time allowed to write a message to a client
+	tableWriteTimeout = 5 * time.Second
+	// Messages waiting to be written to a client: A client that lets them pile up is dropped
+	tableSendQueue = 64
+)
+
+// Type Declaration
+// ****************
+
+// A tableConn is a client connection. Messages are queued, and written by the writeLoop() of the connection,
+// so that a slow client never holds up the table.
+type tableConn struct {
+	conn net.Conn
+	out  chan string
+	done chan struct{}
+}
+
+// A tableSeat is a player at the table.
+//   - token: Secret given on JOIN, used to RESUME after a disconnection
+//   - conn:  nil while the player is disconnected
+//   - left:  The player left the table: The seat is kept to keep seat numbers stable
+type tableSeat struct {
+	name      string
+	token     string
+	hand      deck
+	conn      *tableConn
+	left      bool
+	dropTimer *time.Timer
+}
+
+// A tableServer holds the deck, the hands and the turn of a single table.
+// Configuration fields can be changed between newTableServer() and serve().
+// The wait group tracks the goroutines of every connection, and the timers armed with afterFunc().
+type tableServer struct {
+	listener    net.Listener
+	maxSeats    int
+	handSize    int
+	turnTimeout time.Duration
+	resumeGrace time.Duration
+	randGen     *mathrand.Rand
+
+	mu        sync.Mutex
+	seats     []*tableSeat
+	stock     deck
+	discard   deck
+	started   bool
+	turn      int
+	drew      bool
+	turnID    int
+	turnTimer *time.Timer
+	conns     map[*tableConn]bool
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newTableConn()
+// Initializes a client connection with an empty send queue. Call writeLoop() to write the queue.
+func newTableConn(conn net.Conn) *tableConn {
+	return &tableConn{conn: conn, out: make(chan string, tableSendQueue), done: make(chan struct{})}
+}
+
+// newTableServer()
+// Initializes a table server listening on addr (e.g. "127.0.0.1:0" for any free port).
+// The seed makes the shuffles reproducible.
+func newTableServer(addr string, seed int64) (*tableServer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tableServer{
+		listener:    listener,
+		maxSeats:    tableMaxSeats,
+		handSize:    tableHandSize,
+		turnTimeout: tableTurnTimeout,
+		resumeGrace: tableResumeGrace,
+		randGen:     mathrand.New(mathrand.NewSource(seed)),
+		conns:       map[*tableConn]bool{},
+	}, nil
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// tableServer.addr()
+// Receiver Function that returns the address the server listens on.
+func (s *tableServer) addr() string {
+	return s.listener.Addr().String()
+}
+
+// tableServer.serve()
+// Receiver Function that accepts clients until close() is called.
+func (s *tableServer) serve() error {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return err
+		}
+
+		tc := newTableConn(conn)
+		s.mu.Lock()
+		s.conns[tc] = true
+		s.mu.Unlock()
+
+		s.wg.Add(2)
+		go func() {
+			defer s.wg.Done()
+			tc.writeLoop()
+		}()
+		go func() {
+			defer s.wg.Done()
+			s.handleConn(tc)
+		}()
+	}
+}
+
+// tableServer.close()
+// Receiver Function that stops the server and disconnects every client.
+func (s *tableServer) close() error {
+	err := s.listener.Close()
+
+	s.mu.Lock()
+	// No timer is armed from now on: Those already running are waited for
+	s.closed = true
+	s.stopTimer(s.turnTimer)
+	for _, seat := range s.seats {
+		s.stopTimer(seat.dropTimer)
+	}
+	for tc := range s.conns {
+		tc.conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+// tableServer.handleConn()
+// Receiver Function that reads the messages of a client until it disconnects or leaves.
+// The writeLoop() of the connection then closes it.
+func (s *tableServer) handleConn(tc *tableConn) {
+	defer func() {
+		close(tc.done)
+		s.mu.Lock()
+		delete(s.conns, tc)
+		s.mu.Unlock()
+	}()
+
+	seat := -1
+	scanner := bufio.NewScanner(tc.conn)
+	for scanner.Scan() {
+		m := parseTableMessage(scanner.Text())
+		if m.command == "" {
+			continue
+		}
+
+		s.mu.Lock()
+		var done bool
+		seat, done = s.handle(tc, seat, m)
+		s.mu.Unlock()
+
+		if done {
+			return
+		}
+	}
+
+	// The connection dropped without a LEAVE: Keep the seat for a while
+	s.mu.Lock()
+	s.disconnect(tc, seat)
+	s.mu.Unlock()
+}
+
+// tableServer.handle()
+// Receiver Function that processes a message from a client sitting at the given seat (-1 if none).
+// Returns the seat of the client after the message, and whether the connection should be closed.
+// Must be called with the lock held.
+func (s *tableServer) handle(tc *tableConn, seat int, m tableMessage) (int, bool) {
+	switch m.command {
+	case tableJoin:
+		return s.join(tc, seat, m.payload), false
+	case tableResume:
+		return s.resume(tc, seat, m.payload), false
+	}
+
+	if seat < 0 {
+		tc.send(tableMessage{tableError, "join the table first"})
+		return seat, false
+	}
+
+	switch m.command {
+	case tableStart:
+		s.start(tc)
+	case tableDraw:
+		s.draw(tc, seat)
+	case tablePlay:
+		s.play(tc, seat, m.payload)
+	case tablePass:
+		if s.checkTurn(tc, seat) {
+			s.broadcast(tableMessage{tablePassed, fmt.Sprint(seat)})
+			s.nextTurn()
+		}
+	case tableLeave:
+		s.leave(seat)
+		return -1, true
+	default:
+		tc.send(tableMessage{tableError, fmt.Sprintf("unknown command %q", m.command)})
+	}
+	return seat, false
+}
+
+// tableServer.join()
+// Receiver Function that gives a new seat to a client.
+func (s *tableServer) join(tc *tableConn, seat int, name string) int {
+	switch {
+	case seat >= 0:
+		tc.send(tableMessage{tableError, "already seated"})
+		return seat
+	case name == "":
+		tc.send(tableMessage{tableError, "a name is required"})
+		return seat
+	case s.started:
+		tc.send(tableMessage{tableError, "the game has already started"})
+		return seat
+	case len(s.activeSeats()) >= s.maxSeats:
+		tc.send(tableMessage{tableError, "the table is full"})
+		return seat
+	}
+
+	token, err := newTableToken()
+	if err != nil {
+		tc.send(tableMessage{tableError, err.Error()})
+		return seat
+	}
+
+	s.seats = append(s.seats, &tableSeat{name: name, token: token, conn: tc})
+	seat = len(s.seats) - 1
+	tc.send(tableMessage{tableWelcome, fmt.Sprintf("%d %s", seat, token)})
+	s.broadcast(tableMessage{tableJoined, fmt.Sprint(seat)})
+	s.broadcastState()
+	return seat
+}
+
+// tableServer.resume()
+// Receiver Function that gives a seat back to a client presenting its token, and resyncs its state.
+func (s *tableServer) resume(tc *tableConn, seat int, token string) int {
+	if seat >= 0 {
+		tc.send(tableMessage{tableError, "already seated"})
+		return seat
+	}
+
+	i := slices.IndexFunc(s.seats, func(st *tableSeat) bool { return st.token == token && !st.left })
+	if token == "" || i < 0 {
+		tc.send(tableMessage{tableError, "unknown or expired token"})
+		return seat
+	}
+
+	st := s.seats[i]
+	s.stopTimer(st.dropTimer)
+	st.dropTimer = nil
+	// A new connection takes over from a stale one
+	if st.conn != nil && st.conn != tc {
+		st.conn.conn.Close()
+	}
+	st.conn = tc
+
+	tc.send(tableMessage{tableWelcome, fmt.Sprintf("%d %s", i, st.token)})
+	tc.send(tableMessage{tableHand, st.hand.toString()})
+	s.broadcast(tableMessage{tableResumed, fmt.Sprint(i)})
+	s.broadcastState()
+	if s.started {
+		tc.send(tableMessage{tableTurn, fmt.Sprint(s.turn)})
+	}
+	return i
+}
+
+// tableServer.start()
+// Receiver Function that shuffles a new deck, deals every hand and starts the first turn.
+func (s *tableServer) start(tc *tableConn) {
+	active := s.activeSeats()
+	switch {
+	case s.started:
+		tc.send(tableMessage{tableError, "the game has already started"})
+		return
+	case len(active) < 2:
+		tc.send(tableMessage{tableError, "at least 2 players are needed"})
+		return
+	}
+
+	s.stock = newDeck()
+	s.stock.shuffleWith(s.randGen, 1)
+	for _, i := range active {
+		s.seats[i].hand, s.stock = s.stock.deal(s.handSize)
+		s.seats[i].hand = slices.Clone(s.seats[i].hand)
+		s.sendTo(i, tableMessage{tableHand, s.seats[i].hand.toString()})
+	}
+	s.discard, s.stock = s.stock.deal(1)
+	s.discard = slices.Clone(s.discard)
+
+	s.started = true
+	s.turn = active[0]
+	s.startTurn()
+}
+
+// tableServer.draw()
+// Receiver Function that moves the top card of the stock to the hand of the player (once per turn).
+// Only the player learns which card it was.
+func (s *tableServer) draw(tc *tableConn, seat int) {
+	if !s.checkTurn(tc, seat) {
+		return
+	}
+	if s.drew {
+		tc.send(tableMessage{tableError, "already drew this turn"})
+		return
+	}
+	if len(s.stock) == 0 {
+		tc.send(tableMessage{tableError, "the stock is empty"})
+		return
+	}
+
+	var drawn deck
+	drawn, s.stock = s.stock.deal(1)
+	s.seats[seat].hand = append(s.seats[seat].hand, drawn...)
+	s.drew = true
+
+	s.sendTo(seat, tableMessage{tableHand, s.seats[seat].hand.toString()})
+	s.broadcast(tableMessage{tableDrew, fmt.Sprint(seat)})
+	s.broadcastState()
+}
+
+// tableServer.play()
+// Receiver Function that moves a card from the hand of the player to the discard pile.
+func (s *tableServer) play(tc *tableConn, seat int, c string) {
+	if !s.checkTurn(tc, seat) {
+		return
+	}
+	hand := s.seats[seat].hand
+	i := slices.Index(hand, c)
+	if i < 0 {
+		tc.send(tableMessage{tableError, fmt.Sprintf("%q is not in your hand", c)})
+		return
+	}
+
+	s.seats[seat].hand = slices.Delete(hand, i, i+1)
+	s.discard = append(s.discard, c)
+	s.sendTo(seat, tableMessage{tableHand, s.seats[seat].hand.toString()})
+	s.broadcast(tableMessage{tablePlayed, fmt.Sprintf("%d %s", seat, c)})
+
+	if len(s.seats[seat].hand) == 0 {
+		s.finish(seat)
+		return
+	}
+	s.nextTurn()
+}
+
+// tableServer.leave()
+// Receiver Function that removes a player for good. Their cards go back under the stock.
+func (s *tableServer) leave(seat int) {
+	st := s.seats[seat]
+	if st.left {
+		return
+	}
+	st.left = true
+	s.stopTimer(st.dropTimer)
+	s.stock = append(s.stock, st.hand...)
+	st.hand = nil
+	st.conn = nil
+
+	s.broadcast(tableMessage{tableLeft, fmt.Sprint(seat)})
+
+	if s.started {
+		active := s.activeSeats()
+		switch {
+		case len(active) == 1:
+			s.finish(active[0])
+			return
+		case s.turn == seat:
+			s.nextTurn()
+			return
+		}
+	}
+	s.broadcastState()
+}
+
+// tableServer.disconnect()
+// Receiver Function that keeps the seat of a dropped client for the resume grace period.
+func (s *tableServer) disconnect(tc *tableConn, seat int) {
+	if seat < 0 || s.seats[seat].conn != tc {
+		return
+	}
+
+	st := s.seats[seat]
+	st.conn = nil
+	s.broadcast(tableMessage{tableDropped, fmt.Sprint(seat)})
+	s.broadcastState()
+
+	s.stopTimer(st.dropTimer)
+	st.dropTimer = s.afterFunc(s.resumeGrace, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if st.conn == nil && !st.left {
+			s.leave(seat)
+		}
+	})
+}
+
+// tableServer.checkTurn()
+// Receiver Function that makes sure a game is running and that it is the turn of the seat.
+func (s *tableServer) checkTurn(tc *tableConn, seat int) bool {
+	switch {
+	case !s.started:
+		tc.send(tableMessage{tableError, "the game has not started"})
+		return false
+	case s.turn != seat:
+		tc.send(tableMessage{tableError, fmt.Sprintf("it is the turn of seat %d", s.turn)})
+		return false
+	}
+	return true
+}
+
+// tableServer.nextTurn()
+// Receiver Function that hands the turn to the next player still at the table.
+func (s *tableServer) nextTurn() {
+	for i := 1; i <= len(s.seats); i++ {
+		next := (s.turn + i) % len(s.seats)
+		if !s.seats[next].left {
+			s.turn = next
+			break
+		}
+	}
+	s.startTurn()
+}
+
+// tableServer.startTurn()
+// Receiver Function that announces the turn and arms its timeout.
+// A player who does not act in time has their turn passed.
+func (s *tableServer) startTurn() {
+	s.drew = false
+	s.broadcastState()
+	s.broadcast(tableMessage{tableTurn, fmt.Sprint(s.turn)})
+
+	s.stopTimer(s.turnTimer)
+	s.turnID++
+	id := s.turnID
+	s.turnTimer = s.afterFunc(s.turnTimeout, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		// Ignore a timer that fired while the turn was already over
+		if !s.started || s.turnID != id {
+			return
+		}
+		s.broadcast(tableMessage{tableTimeout, fmt.Sprint(s.turn)})
+		s.nextTurn()
+	})
+}
+
+// tableServer.finish()
+// Receiver Function that ends the game with a winner.
+func (s *tableServer) finish(winner int) {
+	s.started = false
+	s.stopTimer(s.turnTimer)
+	s.broadcast(tableMessage{tableOver, fmt.Sprint(winner)})
+	s.broadcastState()
+}
+
+// tableServer.afterFunc()
+// Receiver Function that calls f in its own goroutine after d, counted in the wait group until it returns
+// or is stopped with stopTimer(). Returns nil once the server is closed. Must be called with the lock held.
+func (s *tableServer) afterFunc(d time.Duration, f func()) *time.Timer {
+	if s.closed {
+		return nil
+	}
+	s.wg.Add(1)
+	return time.AfterFunc(d, func() {
+		defer s.wg.Done()
+		f()
+	})
+}
+
+// tableServer.stopTimer()
+// Receiver Function that stops a timer of afterFunc() (nil for none). A callback already running is left to finish.
+func (s *tableServer) stopTimer(t *time.Timer) {
+	if t != nil && t.Stop() {
+		s.wg.Done()
+	}
+}
+
+// tableServer.activeSeats()
+// Receiver Function that lists the seats of the players still at the table.
+func (s *tableServer) activeSeats() []int {
+	active := []int{}
+	for i, st := range s.seats {
+		if !st.left {
+			active = append(active, i)
+		}
+	}
+	return active
+}
+
+// tableServer.state()
+// Receiver Function that builds the public state of the table.
+func (s *tableServer) state() tableView {
+	state := tableView{Started: s.started, Turn: s.turn, Stock: len(s.stock), Players: []tableViewPlayer{}}
+	if len(s.discard) > 0 {
+		state.Top = s.discard[len(s.discard)-1]
+	}
+	for _, i := range s.activeSeats() {
+		st := s.seats[i]
+		state.Players = append(state.Players, tableViewPlayer{Seat: i, Name: st.name, Cards: len(st.hand), Connected: st.conn != nil})
+	}
+	return state
+}
+
+// tableServer.broadcastState()
+// Receiver Function that sends the public state of the table to every connected player.
+func (s *tableServer) broadcastState() {
+	payload, err := json.Marshal(s.state())
+	if err != nil {
+		return
+	}
+	s.broadcast(tableMessage{tableState, string(payload)})
+}
+
+// tableServer.broadcast()
+// Receiver Function that sends a message to every connected player.
+func (s *tableServer) broadcast(m tableMessage) {
+	for i := range s.seats {
+		s.sendTo(i, m)
+	}
+}
+
+// tableServer.sendTo()
+// Receiver Function that sends a message to a single seat, if connected.
+func (s *tableServer) sendTo(seat int, m tableMessage) {
+	if st := s.seats[seat]; !st.left && st.conn != nil {
+		st.conn.send(m)
+	}
+}
+
+// tableConn.send()
+// Receiver Function that queues a message without waiting.
+// A client too slow to read is dropped when its queue is full: Its seat can be resumed.
+func (tc *tableConn) send(m tableMessage) {
+	select {
+	case tc.out <- m.toString():
+	default:
+		tc.conn.Close()
+	}
+}
+
+// tableConn.writeLoop()
+// Receiver Function that writes the queued messages, one line each, until the connection is done.
+// What is still queued is then flushed, and the connection closed.
+// A client too slow to read is dropped by the write deadline.
+func (tc *tableConn) writeLoop() {
+	defer tc.conn.Close()
+	write := func(line string) bool {
+		tc.conn.SetWriteDeadline(time.Now().Add(tableWriteTimeout))
+		_, err := fmt.Fprintln(tc.conn, line)
+		return err == nil
+	}
+	for {
+		select {
+		case line := <-tc.out:
+			if !write(line) {
+				return
+			}
+		case <-tc.done:
+			for {
+				select {
+				case line := <-tc.out:
+					if !write(line) {
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// Helper Functions
+// ****************
+
+// newTableToken()
+// Returns a random secret used to resume a seat.
+func newTableToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,294 @@
+/**
+ * @file: Unit tests for the table server and client, over localhost
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test Helpers
+// ************
+
+const tableTestWait = 2 * time.Second
+
+// startTestTable() starts a table server on a free localhost port, stopped at the end of the test.
+func startTestTable(t *testing.T) *tableServer {
+	t.Helper()
+	s, err := newTableServer("127.0.0.1:0", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.serve()
+	t.Cleanup(func() { s.close() })
+	return s
+}
+
+// joinTestTable() connects a client and waits for its seat.
+func joinTestTable(t *testing.T, s *tableServer, name string) *tableClient {
+	t.Helper()
+	c, err := dialTable(s.addr(), tableTestWait)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.close() })
+	c.join(name)
+	if _, err := c.waitFor(tableWelcome, tableTestWait); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// Test Cases for the table server
+// *******************************
+//   - Each player receives their own hand, and never sees the hands of others
+//   - Players must wait for their turn
+//   - A player who does not act in time has their turn passed
+//   - A dropped player can resume their seat and get their hand back
+//   - A player leaving ends the game when only one player is left
+
+func Test_tableServer(t *testing.T) {
+	s := startTestTable(t)
+	alice := joinTestTable(t, s, "alice")
+	bob := joinTestTable(t, s, "bob")
+
+	// TEST CASE 1: Hidden information
+	// -------------------------------
+	alice.start()
+	if _, err := alice.waitFor(tableTurn, tableTestWait); err != nil {
+		t.Fatalf("Test Case 1: %v", err)
+	}
+	if _, err := bob.waitFor(tableTurn, tableTestWait); err != nil {
+		t.Fatalf("Test Case 1: %v", err)
+	}
+	aliceHand, bobHand := alice.currentHand(), bob.currentHand()
+	if len(aliceHand) != tableHandSize || len(bobHand) != tableHandSize {
+		t.Fatalf("Test Case 1: Expected hands of %d cards. Got %d and %d", tableHandSize, len(aliceHand), len(bobHand))
+	}
+	for _, c := range aliceHand {
+		if slices.Contains(bobHand, c) {
+			t.Errorf("Test Case 1: Expected distinct hands. Both hold %s", c)
+		}
+	}
+	state := bob.currentState()
+	if !state.Started || state.Turn != 0 || len(state.Players) != 2 || state.Players[0].Cards != tableHandSize {
+		t.Errorf("Test Case 1: Unexpected public state %+v", state)
+	}
+
+	// TEST CASE 2: Turn order
+	// -----------------------
+	bob.play(bobHand[0])
+	if _, err := bob.waitFor(tablePlayed, tableTestWait); err == nil || !strings.Contains(err.Error(), "turn") {
+		t.Errorf("Test Case 2: Expected an error playing out of turn. Got %v", err)
+	}
+	alice.draw()
+	if _, err := alice.waitFor(tableDrew, tableTestWait); err != nil {
+		t.Fatalf("Test Case 2: %v", err)
+	}
+	// Bob learns that Alice drew, but not which card
+	m, err := bob.waitFor(tableDrew, tableTestWait)
+	if err != nil || m.payload != "0" {
+		t.Errorf("Test Case 2: Expected bob to see \"DREW 0\". Got %q (%v)", m.toString(), err)
+	}
+	drawn := alice.currentHand()
+	alice.play(drawn[len(drawn)-1])
+	if m, err := bob.waitFor(tablePlayed, tableTestWait); err != nil || !strings.HasPrefix(m.payload, "0 ") {
+		t.Errorf("Test Case 2: Expected bob to see alice's play. Got %q (%v)", m.toString(), err)
+	}
+	if m, err := bob.waitFor(tableTurn, tableTestWait); err != nil || m.payload != "1" {
+		t.Errorf("Test Case 2: Expected the turn of seat 1. Got %q (%v)", m.toString(), err)
+	}
+
+	// TEST CASE 3: Turn timeout
+	// -------------------------
+	s.mu.Lock()
+	s.turnTimeout = 50 * time.Millisecond
+	s.mu.Unlock()
+	bob.pass()
+	if m, err := alice.waitFor(tableTimeout, tableTestWait); err != nil || m.payload != "0" {
+		t.Errorf("Test Case 3: Expected alice's turn to time out. Got %q (%v)", m.toString(), err)
+	}
+	s.mu.Lock()
+	s.turnTimeout = tableTurnTimeout
+	s.mu.Unlock()
+
+	// TEST CASE 4: Reconnection with state resync
+	// -------------------------------------------
+	_, token := bob.currentSeat()
+	bob.close()
+	if _, err := alice.waitFor(tableDropped, tableTestWait); err != nil {
+		t.Fatalf("Test Case 4: %v", err)
+	}
+	bob2, err := dialTable(s.addr(), tableTestWait)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bob2.close()
+	bob2.resume(token)
+	if _, err := bob2.waitFor(tableState, tableTestWait); err != nil {
+		t.Fatalf("Test Case 4: %v", err)
+	}
+	if seat, _ := bob2.currentSeat(); seat != 1 || !slices.Equal(bob2.currentHand(), bobHand) {
+		t.Errorf("Test Case 4: Expected seat 1 with the same hand. Got seat %d with %v", seat, bob2.currentHand())
+	}
+	if err := bob2.resume("not-a-token"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bob2.waitFor(tableWelcome, tableTestWait); err == nil {
+		t.Errorf("Test Case 4: Expected an error resuming twice")
+	}
+
+	// TEST CASE 5: Leaving ends the game
+	// ----------------------------------
+	alice.leave()
+	if m, err := bob2.waitFor(tableOver, tableTestWait); err != nil || m.payload != "1" {
+		t.Errorf("Test Case 5: Expected bob to win when alice leaves. Got %q (%v)", m.toString(), err)
+	}
+}
+
+// Test Cases for joining the table
+// ********************************
+//   - Commands other than JOIN or RESUME need a seat
+//   - The game cannot start with a single player
+
+func Test_tableServerJoin(t *testing.T) {
+	s := startTestTable(t)
+
+	// TEST CASE 1: A seat is required
+	// -------------------------------
+	c, err := dialTable(s.addr(), tableTestWait)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.close()
+	c.start()
+	if _, err := c.waitFor(tableTurn, tableTestWait); err == nil || !strings.Contains(err.Error(), "join") {
+		t.Errorf("Test Case 1: Expected an error before joining. Got %v", err)
+	}
+
+	// TEST CASE 2: Two players are needed
+	// -----------------------------------
+	c.join("carol")
+	if _, err := c.waitFor(tableWelcome, tableTestWait); err != nil {
+		t.Fatal(err)
+	}
+	c.start()
+	if _, err := c.waitFor(tableTurn, tableTestWait); err == nil || !strings.Contains(err.Error(), "2 players") {
+		t.Errorf("Test Case 2: Expected an error starting alone. Got %v", err)
+	}
+}
+
+// Test Cases for slow readers
+// ***************************
+//   - The server never waits for a client that does not read: The client is dropped once its queue is full
+//   - The client keeps its state in sync even when nobody reads its messages channel
+
+func Test_tableSlowReaders(t *testing.T) {
+	// TEST CASE 1: A slow client is dropped
+	// -------------------------------------
+	serverEnd, clientEnd := net.Pipe()
+	tc := newTableConn(serverEnd)
+	go tc.writeLoop()
+	start := time.Now()
+	for i := 0; i < tableSendQueue+10; i++ {
+		tc.send(tableMessage{tableTurn, fmt.Sprint(i)})
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Test Case 1: Expected send() not to wait for the client. Took %v", elapsed)
+	}
+	clientEnd.SetReadDeadline(time.Now().Add(tableTestWait))
+	if _, err := io.Copy(io.Discard, clientEnd); err != nil {
+		t.Errorf("Test Case 1: Expected the slow client to be disconnected. Got %v", err)
+	}
+
+	// TEST CASE 2: The state of an unread client is up to date
+	// --------------------------------------------------------
+	serverEnd, clientEnd = net.Pipe()
+	c := &tableClient{conn: clientEnd, messages: make(chan tableMessage, tableClientBuffer), seat: -1}
+	go c.readLoop()
+	defer c.close()
+	for i := 0; i <= tableClientBuffer+10; i++ {
+		fmt.Fprintln(serverEnd, tableMessage{tableState, fmt.Sprintf(`{"turn":%d}`, i)}.toString())
+	}
+	serverEnd.Close()
+	var received []tableMessage
+	for m := range c.messages {
+		received = append(received, m)
+	}
+	if turn := c.currentState().Turn; turn != tableClientBuffer+10 {
+		t.Errorf("Test Case 2: Expected the last state, turn %d. Got turn %d", tableClientBuffer+10, turn)
+	}
+	last := fmt.Sprintf(`{"turn":%d}`, tableClientBuffer+10)
+	if received[0].payload == `{"turn":0}` || received[len(received)-1].payload != last {
+		t.Errorf("Test Case 2: Expected the oldest messages dropped, and the latest kept. Got %d, from %q to %q",
+			len(received), received[0].payload, received[len(received)-1].payload)
+	}
+}
+
+// Test Cases for reconnecting and closing
+// ***************************************
+//   - A dropped client reconnects to its seat, with its hand back
+//   - A client without a seat has nothing to reconnect to
+//   - close() waits for a timer callback already running
+
+func Test_tableReconnect(t *testing.T) {
+	s := startTestTable(t)
+	alice := joinTestTable(t, s, "alice")
+	joinTestTable(t, s, "bob")
+	alice.start()
+	if _, err := alice.waitFor(tableTurn, tableTestWait); err != nil {
+		t.Fatal(err)
+	}
+
+	// TEST CASE 1: Seat and hand back
+	// -------------------------------
+	hand := alice.currentHand()
+	again, err := alice.reconnect(tableTestWait)
+	if err != nil {
+		t.Fatalf("Test Case 1: %v", err)
+	}
+	defer again.close()
+	if seat, _ := again.currentSeat(); seat != 0 || !slices.Equal(again.currentHand(), hand) {
+		t.Errorf("Test Case 1: Expected seat 0 with the same hand. Got seat %d with %v", seat, again.currentHand())
+	}
+
+	// TEST CASE 2: No seat
+	// --------------------
+	c, err := dialTable(s.addr(), tableTestWait)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.close()
+	if _, err := c.reconnect(tableTestWait); err == nil {
+		t.Errorf("Test Case 2: Expected an error without a seat. Got none")
+	}
+
+	// TEST CASE 3: Timers are waited for
+	// ----------------------------------
+	started, finished := make(chan struct{}), make(chan struct{})
+	s.mu.Lock()
+	s.afterFunc(0, func() {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		close(finished)
+	})
+	s.mu.Unlock()
+	<-started
+	s.close()
+	select {
+	case <-finished:
+	default:
+		t.Errorf("Test Case 3: Expected close() to wait for the timer callback")
+	}
+}
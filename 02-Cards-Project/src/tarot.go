@@ -0,0 +1,131 @@
+/**
+ * @file: Describes the 78-card Tarot deck on top of the generic deck.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+)
+
+// Deck Composition
+// ****************
+
+// Major arcana, numbered from 0 (The Fool) to 21 (The World).
+var tarotMajorArcana = [22]string{
+	"The Fool", "The Magician", "The High Priestess", "The Empress", "The Emperor",
+	"The Hierophant", "The Lovers", "The Chariot", "Strength", "The Hermit",
+	"Wheel of Fortune", "Justice", "The Hanged Man", "Death", "Temperance",
+	"The Devil", "The Tower", "The Star", "The Moon", "The Sun",
+	"Judgement", "The World",
+}
+
+// Suits of the minor arcana.
+var tarotSuits = [4]string{"Wands", "Cups", "Swords", "Pentacles"}
+
+// Ranks of the minor arcana, numbered from 1 (Ace) to 14 (King).
+var tarotRanks = [14]string{"Ace", "Two", "Three", "Four", "Five", "Six", "Seven", "Eight", "Nine", "Ten", "Page", "Knight", "Queen", "King"}
+
+// Suffix of a reversed card in its text form.
+const tarotReversed = " (Reversed)"
+
+// Type Declaration
+// ****************
+
+// A tarotCard is either a major arcana (no suit, number 0 to 21) or a minor arcana
+// (a suit, number 1 to 14). Cards can be upright or reversed.
+type tarotCard struct {
+	suit     string
+	number   int
+	reversed bool
+}
+
+// A tarotCodec saves Tarot cards by name, such as "The Fool" or "Queen of Cups (Reversed)".
+type tarotCodec struct{}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newTarotDeck()
+// Initializes a 78-card Tarot deck, all upright: The 22 major arcana, then the 56 minor arcana by suit.
+func newTarotDeck() genericDeck[tarotCard] {
+	d := genericDeck[tarotCard]{}
+	for number := range tarotMajorArcana {
+		d = append(d, tarotCard{number: number})
+	}
+	for _, suit := range tarotSuits {
+		for i := range tarotRanks {
+			d = append(d, tarotCard{suit: suit, number: i + 1})
+		}
+	}
+	return d
+}
+
+// parseTarotCard()
+// Parses the name of a Tarot card, as written by tarotCard.toString().
+func parseTarotCard(s string) (tarotCard, error) {
+	name, reversed := strings.CutSuffix(s, tarotReversed)
+
+	// Major arcana
+	if number := slices.Index(tarotMajorArcana[:], name); number >= 0 {
+		return tarotCard{number: number, reversed: reversed}, nil
+	}
+
+	// Minor arcana: "<rank> of <suit>"
+	rank, suit, found := strings.Cut(name, " of ")
+	number := slices.Index(tarotRanks[:], rank)
+	if !found || number < 0 || !slices.Contains(tarotSuits[:], suit) {
+		return tarotCard{}, fmt.Errorf("invalid tarot card %q", s)
+	}
+	return tarotCard{suit: suit, number: number + 1, reversed: reversed}, nil
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// tarotCard.isMajor()
+// Receiver Function that tells if the card belongs to the major arcana.
+func (c tarotCard) isMajor() bool {
+	return c.suit == ""
+}
+
+// tarotCard.name()
+// Receiver Function that returns the name of the card, regardless of its orientation.
+func (c tarotCard) name() string {
+	if c.isMajor() {
+		return tarotMajorArcana[c.number]
+	}
+	return fmt.Sprintf("%s of %s", tarotRanks[c.number-1], c.suit)
+}
+
+// tarotCard.toString()
+// Receiver Function to convert the card into its text form, marking reversed cards.
+func (c tarotCard) toString() string {
+	if c.reversed {
+		return c.name() + tarotReversed
+	}
+	return c.name()
+}
+
+func (tarotCodec) encode(c tarotCard) string          { return c.toString() }
+func (tarotCodec) decode(s string) (tarotCard, error) { return parseTarotCard(s) }
+
+// Helper Functions
+// ****************
+
+// shuffleTarotDeck()
+// Shuffles a Tarot deck and sets the orientation of each card: Each card has an even chance of
+// coming out reversed, as when part of the deck is turned around while shuffling.
+func shuffleTarotDeck(d genericDeck[tarotCard], randGen *rand.Rand, times uint) {
+	d.shuffle(randGen, times)
+	for i := range d {
+		d[i].reversed = randGen.Intn(2) == 1
+	}
+}
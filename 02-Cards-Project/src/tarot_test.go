@@ -0,0 +1,91 @@
+/**
+ * @file: Unit tests for the Tarot deck
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"math/rand"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// Test Cases for newTarotDeck()
+// *****************************
+//   - The deck should hold 22 major and 56 minor arcana
+//   - Names should be parsed back into the same cards
+//   - Shuffling should keep every card and set the orientation
+//   - Saving and loading should keep the order and the orientation
+
+func Test_newTarotDeck(t *testing.T) {
+	// TEST CASE 1: Composition
+	// ------------------------
+	d := newTarotDeck()
+	majors := 0
+	for _, c := range d {
+		if c.isMajor() {
+			majors++
+		}
+	}
+	if len(d) != 78 || majors != 22 {
+		t.Errorf("Test Case 1: Expected 78 cards with 22 major arcana. Got %d with %d", len(d), majors)
+	}
+	if d[0].toString() != "The Fool" || d[21].toString() != "The World" || d[77].toString() != "King of Pentacles" {
+		t.Errorf("Test Case 1: Unexpected order: %s, %s, %s", d[0].toString(), d[21].toString(), d[77].toString())
+	}
+
+	// TEST CASE 2: Names are parsed back
+	// ----------------------------------
+	for _, c := range d {
+		c.reversed = true
+		parsed, err := parseTarotCard(c.toString())
+		if err != nil || parsed != c {
+			t.Errorf("Test Case 2: Expected to parse %q back. Got %+v (%v)", c.toString(), parsed, err)
+		}
+	}
+	if _, err := parseTarotCard("Jack of Cups"); err == nil {
+		t.Errorf("Test Case 2: Expected an error for a Jack")
+	}
+
+	// TEST CASE 3: Shuffling sets the orientation
+	// -------------------------------------------
+	shuffled := newTarotDeck()
+	shuffleTarotDeck(shuffled, rand.New(rand.NewSource(78)), 1)
+	reversed := 0
+	for _, c := range shuffled {
+		if c.reversed {
+			reversed++
+		}
+	}
+	if reversed == 0 || reversed == 78 {
+		t.Errorf("Test Case 3: Expected a mix of upright and reversed cards. Got %d reversed", reversed)
+	}
+	names := func(d genericDeck[tarotCard]) []string {
+		strs := []string{}
+		for _, c := range d {
+			strs = append(strs, c.name())
+		}
+		slices.Sort(strs)
+		return strs
+	}
+	if !slices.Equal(names(shuffled), names(d)) {
+		t.Errorf("Test Case 3: Expected the shuffled deck to hold the same 78 cards")
+	}
+
+	// TEST CASE 4: Save and load
+	// --------------------------
+	filename := filepath.Join(t.TempDir(), "tarot.sav")
+	hand, _, _ := shuffled.deal(10)
+	if err := hand.saveToFile(filename, tarotCodec{}); err != nil {
+		t.Fatalf("Test Case 4: Unexpected error: %v", err)
+	}
+	loaded, err := newGenericDeckFromFile(filename, tarotCodec{})
+	if err != nil || !slices.Equal(loaded, hand) {
+		t.Errorf("Test Case 4: Expected the same 10 cards back (%v)", err)
+	}
+}
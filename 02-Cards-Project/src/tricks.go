@@ -0,0 +1,381 @@
+/**
+ * @file: Describes a generic framework for trick-taking games (Hearts, Spades, ...).
+ * Rule sets plug into the framework through the trickRules interface.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+)
+
+// Errors
+// ******
+
+var errTrickIllegalPlay = errors.New("illegal play")
+
+// Interfaces
+// **********
+
+// trickRules describes everything that differs from one trick-taking game to another.
+type trickRules interface {
+	// Name of the game
+	name() string
+	// Number of seats at the table
+	seats() int
+	// Cards used by the game, dealt evenly between the seats
+	cards() deck
+	// Trump suit, or "" when the game has no trump
+	trump() string
+	// Whether players bid on the number of tricks before playing
+	bidding() bool
+	// Seat that leads the first trick of the round
+	firstLeader(round *trickRound) int
+	// Cards that the seat to play is allowed to play
+	legalPlays(round *trickRound, seat int) []card
+	// Strength of a card in a trick led with leadSuit: The strongest card wins, 0 can never win
+	strength(c card, leadSuit string) int
+	// Points scored by each seat at the end of a round
+	scoreRound(round *trickRound, scores []int) []int
+	// Whether the game is over given the total scores
+	isGameOver(scores []int) bool
+	// Seats that won the game given the total scores
+	winners(scores []int) []int
+}
+
+// trickPlayer is a seat at the table: Either a human or a bot.
+type trickPlayer interface {
+	// Number of tricks the player bids (only asked when the rules use bidding)
+	bid(view trickView) int
+	// Card the player plays, among the legal ones
+	play(view trickView, legal []card) card
+}
+
+// Type Declaration
+// ****************
+
+// A trickPlay is a card played by a seat.
+type trickPlay struct {
+	seat int
+	card card
+}
+
+// A trickRound holds the state of a single deal: Hands, bids, tricks and cards won.
+type trickRound struct {
+	dealer    int
+	hands     [][]card
+	bids      []int
+	tricksWon []int
+	cardsWon  [][]card
+	leader    int
+	trick     []trickPlay
+	played    []card
+	completed int
+}
+
+// A trickView is what a player is allowed to see when it is their turn.
+type trickView struct {
+	seat      int
+	hand      []card
+	trick     []trickPlay
+	leadSuit  string
+	trump     string
+	bids      []int
+	tricksWon []int
+	played    []card
+	scores    []int
+	rules     trickRules
+}
+
+// A trickGame is a table of players, the rules they play by and the running scores.
+type trickGame struct {
+	rules   trickRules
+	players []trickPlayer
+	scores  []int
+	dealer  int
+	rounds  int
+	randGen *rand.Rand
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newTrickGame()
+// Initializes a new trick-taking game. The seed makes the deals reproducible.
+// The rules may hold state across rounds, so each game needs its own rules value.
+func newTrickGame(rules trickRules, players []trickPlayer, seed int64) (*trickGame, error) {
+	if len(players) != rules.seats() {
+		return nil, fmt.Errorf("%s needs %d players, got %d", rules.name(), rules.seats(), len(players))
+	}
+
+	return &trickGame{
+		rules:   rules,
+		players: players,
+		scores:  make([]int, len(players)),
+		randGen: rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// trickGame.nextSeat()
+// Receiver Function that returns the seat playing after the given one (clockwise).
+func (g *trickGame) nextSeat(seat int) int {
+	return (seat + 1) % len(g.players)
+}
+
+// trickGame.deal()
+// Receiver Function that shuffles a new deck and deals it evenly between the seats.
+func (g *trickGame) deal() (*trickRound, error) {
+	d := g.rules.cards()
+	d.shuffleWith(g.randGen, 1)
+
+	seats := len(g.players)
+	handSize := len(d) / seats
+	round := &trickRound{
+		dealer:    g.dealer,
+		hands:     make([][]card, seats),
+		bids:      make([]int, seats),
+		tricksWon: make([]int, seats),
+		cardsWon:  make([][]card, seats),
+	}
+
+	// Deal starting with the seat after the dealer
+	seat := g.nextSeat(g.dealer)
+	for range seats {
+		var hand deck
+		hand, d = d.deal(handSize)
+		cards, err := parseCards(hand)
+		if err != nil {
+			return nil, err
+		}
+		sortCards(cards)
+		round.hands[seat] = cards
+		seat = g.nextSeat(seat)
+	}
+
+	return round, nil
+}
+
+// trickGame.view()
+// Receiver Function that builds what a seat can see of the round.
+func (g *trickGame) view(round *trickRound, seat int) trickView {
+	v := trickView{
+		seat:      seat,
+		hand:      slices.Clone(round.hands[seat]),
+		trick:     slices.Clone(round.trick),
+		trump:     g.rules.trump(),
+		bids:      slices.Clone(round.bids),
+		tricksWon: slices.Clone(round.tricksWon),
+		played:    slices.Clone(round.played),
+		scores:    slices.Clone(g.scores),
+		rules:     g.rules,
+	}
+	if len(round.trick) > 0 {
+		v.leadSuit = round.trick[0].card.suit
+	}
+	return v
+}
+
+// trickGame.playRound()
+// Receiver Function that deals, collects bids, plays every trick and updates the scores.
+// Returns the completed round.
+func (g *trickGame) playRound() (*trickRound, error) {
+	round, err := g.deal()
+	if err != nil {
+		return nil, err
+	}
+
+	// Bidding: Starting with the seat after the dealer
+	if g.rules.bidding() {
+		seat := g.nextSeat(g.dealer)
+		for range g.players {
+			bid := g.players[seat].bid(g.view(round, seat))
+			if bid < 0 || bid > len(round.hands[seat]) {
+				return nil, fmt.Errorf("seat %d: invalid bid %d", seat, bid)
+			}
+			round.bids[seat] = bid
+			seat = g.nextSeat(seat)
+		}
+	}
+
+	// Play every trick: The winner of a trick leads the next one
+	round.leader = g.rules.firstLeader(round)
+	for len(round.hands[round.leader]) > 0 {
+		seat := round.leader
+		for range g.players {
+			if err := g.playCard(round, seat); err != nil {
+				return nil, err
+			}
+			seat = g.nextSeat(seat)
+		}
+
+		winner := trickWinner(round.trick, g.rules)
+		round.tricksWon[winner]++
+		for _, p := range round.trick {
+			round.cardsWon[winner] = append(round.cardsWon[winner], p.card)
+			round.played = append(round.played, p.card)
+		}
+		round.trick = nil
+		round.leader = winner
+		round.completed++
+	}
+
+	// Scoring
+	points := g.rules.scoreRound(round, g.scores)
+	for seat, p := range points {
+		g.scores[seat] += p
+	}
+
+	g.dealer = g.nextSeat(g.dealer)
+	g.rounds++
+	return round, nil
+}
+
+// trickGame.playCard()
+// Receiver Function that asks a seat for a card and checks that it is legal.
+func (g *trickGame) playCard(round *trickRound, seat int) error {
+	legal := g.rules.legalPlays(round, seat)
+	c := g.players[seat].play(g.view(round, seat), legal)
+	if !slices.Contains(legal, c) {
+		return fmt.Errorf("%w: seat %d cannot play %s", errTrickIllegalPlay, seat, c.toString())
+	}
+
+	// Remove the card from the hand and put it on the table
+	hand := round.hands[seat]
+	i := slices.Index(hand, c)
+	round.hands[seat] = slices.Delete(hand, i, i+1)
+	round.trick = append(round.trick, trickPlay{seat: seat, card: c})
+	return nil
+}
+
+// trickGame.play()
+// Receiver Function that plays rounds until the rules declare the game over (or maxRounds is reached).
+// Returns the winning seats.
+func (g *trickGame) play(maxRounds int) ([]int, error) {
+	for g.rounds < maxRounds && !g.rules.isGameOver(g.scores) {
+		if _, err := g.playRound(); err != nil {
+			return nil, err
+		}
+	}
+	return g.rules.winners(g.scores), nil
+}
+
+// trickGame.summary()
+// Receiver Function that describes the scores of the game.
+func (g *trickGame) summary() string {
+	s := fmt.Sprintf("%s after %d round(s):", g.rules.name(), g.rounds)
+	for seat, score := range g.scores {
+		s += fmt.Sprintf(" [Seat %d: %d]", seat, score)
+	}
+	return s
+}
+
+// trickRound.suitPlayed()
+// Receiver Function that tells if a suit was played in a completed trick of the round ("broken").
+func (round *trickRound) suitPlayed(suit string) bool {
+	for _, c := range round.played {
+		if c.suit == suit {
+			return true
+		}
+	}
+	return false
+}
+
+// Helper Functions
+// ****************
+
+// highRank()
+// Returns the rank of a card with Aces high: 2..10, J=11, Q=12, K=13, A=14.
+func highRank(c card) int {
+	if c.rank() == 1 {
+		return 14
+	}
+	return c.rank()
+}
+
+// standardStrength()
+// Strength of a card in most trick-taking games: Trumps beat the lead suit,
+// higher ranks beat lower ones (Aces high), and any other suit can never win.
+func standardStrength(c card, leadSuit string, trump string) int {
+	switch {
+	case trump != "" && c.suit == trump:
+		return 100 + highRank(c)
+	case c.suit == leadSuit:
+		return highRank(c)
+	}
+	return 0
+}
+
+// trickWinner()
+// Returns the seat that wins a complete trick according to the rules.
+func trickWinner(trick []trickPlay, rules trickRules) int {
+	leadSuit := trick[0].card.suit
+	best := trick[0]
+	for _, p := range trick[1:] {
+		if rules.strength(p.card, leadSuit) > rules.strength(best.card, leadSuit) {
+			best = p
+		}
+	}
+	return best.seat
+}
+
+// followSuit()
+// Returns the cards of the hand that follow the lead suit, or the whole hand if there are none.
+func followSuit(hand []card, leadSuit string) []card {
+	following := []card{}
+	for _, c := range hand {
+		if c.suit == leadSuit {
+			following = append(following, c)
+		}
+	}
+	if len(following) == 0 {
+		return slices.Clone(hand)
+	}
+	return following
+}
+
+// sortCards()
+// Sorts cards by suit (in deckSuits order), then by rank with Aces high.
+func sortCards(cards []card) {
+	slices.SortFunc(cards, func(a, b card) int {
+		if a.suitIndex() != b.suitIndex() {
+			return a.suitIndex() - b.suitIndex()
+		}
+		return highRank(a) - highRank(b)
+	})
+}
+
+// lowestScores()
+// Returns the seats with the lowest score (for games where fewer points is better).
+func lowestScores(scores []int) []int {
+	best := slices.Min(scores)
+	seats := []int{}
+	for seat, score := range scores {
+		if score == best {
+			seats = append(seats, seat)
+		}
+	}
+	return seats
+}
+
+// highestScores()
+// Returns the seats with the highest score.
+func highestScores(scores []int) []int {
+	best := slices.Max(scores)
+	seats := []int{}
+	for seat, score := range scores {
+		if score == best {
+			seats = append(seats, seat)
+		}
+	}
+	return seats
+}
@@ -0,0 +1,96 @@
+/**
+ * @file: Describes a simple bot player for the trick-taking framework.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import "slices"
+
+// Type Declaration
+// ****************
+
+// A trickBot is a simple, deterministic computer player.
+//   - avoidTricks: Play to lose tricks (Hearts) instead of winning them (Spades)
+type trickBot struct {
+	avoidTricks bool
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// trickBot.bid()
+// Receiver Function that bids one trick per Ace, per King outside of trump,
+// per high trump (Q or better) and per trump beyond the third one.
+func (b trickBot) bid(view trickView) int {
+	bid, trumps := 0, 0
+	for _, c := range view.hand {
+		isTrump := view.trump != "" && c.suit == view.trump
+		if isTrump {
+			trumps++
+		}
+		switch {
+		case c.value == "A":
+			bid++
+		case c.value == "K" && !isTrump:
+			bid++
+		case isTrump && highRank(c) >= 12:
+			bid++
+		}
+	}
+	bid += max(trumps-3, 0)
+	return max(min(bid, len(view.hand)), 1)
+}
+
+// trickBot.play()
+// Receiver Function that picks a card among the legal ones.
+func (b trickBot) play(view trickView, legal []card) card {
+	// Sort from weakest to strongest
+	sorted := slices.Clone(legal)
+	slices.SortStableFunc(sorted, func(x, y card) int { return highRank(x) - highRank(y) })
+
+	// Leading: Play low to lose, high to win
+	if len(view.trick) == 0 {
+		if b.avoidTricks {
+			return sorted[0]
+		}
+		return sorted[len(sorted)-1]
+	}
+
+	// Strength of the card currently winning the trick
+	best := 0
+	for _, p := range view.trick {
+		best = max(best, view.rules.strength(p.card, view.leadSuit))
+	}
+
+	// Split the legal cards between the ones that would win and the ones that would not
+	winning, losing := []card{}, []card{}
+	for _, c := range sorted {
+		if view.rules.strength(c, view.leadSuit) > best {
+			winning = append(winning, c)
+		} else {
+			losing = append(losing, c)
+		}
+	}
+
+	if b.avoidTricks {
+		// Shed the worst card possible: Highest penalty first when not following suit
+		if len(losing) > 0 {
+			if losing[0].suit != view.leadSuit {
+				slices.SortStableFunc(losing, func(x, y card) int { return heartsPoints(x) - heartsPoints(y) })
+			}
+			return losing[len(losing)-1]
+		}
+		// Forced to win: Might as well win with the highest card
+		return winning[len(winning)-1]
+	}
+
+	// Win as cheaply as possible, otherwise throw the weakest card
+	if len(winning) > 0 {
+		return winning[0]
+	}
+	return losing[0]
+}
@@ -0,0 +1,190 @@
+/**
+ * @file: Unit tests for the trick-taking framework, Hearts and Spades
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// Test Helpers
+// ************
+
+// cheatingPlayer always plays the last card of its hand, legal or not.
+type cheatingPlayer struct{}
+
+func (cheatingPlayer) bid(trickView) int { return 1 }
+func (cheatingPlayer) play(view trickView, _ []card) card {
+	return view.hand[len(view.hand)-1]
+}
+
+// newBots() returns n bots playing the same way.
+func newBots(n int, avoidTricks bool) []trickPlayer {
+	players := []trickPlayer{}
+	for range n {
+		players = append(players, trickBot{avoidTricks: avoidTricks})
+	}
+	return players
+}
+
+// Test Cases for trickWinner()
+// ****************************
+//   - The highest card of the lead suit wins when there is no trump
+//   - Any trump beats the lead suit
+
+func Test_trickWinner(t *testing.T) {
+	cards := mustParseCards(t, "10 of Heart", "A of Club", "K of Heart", "2 of Spade")
+	trick := []trickPlay{}
+	for seat, c := range cards {
+		trick = append(trick, trickPlay{seat: seat, card: c})
+	}
+
+	// TEST CASE 1: No trump
+	// ---------------------
+	if winner := trickWinner(trick, heartsRules{}); winner != 2 {
+		t.Errorf("Test Case 1: Expected seat 2 to win with the K of Heart. Got seat %d", winner)
+	}
+
+	// TEST CASE 2: Spades are trump
+	// -----------------------------
+	if winner := trickWinner(trick, &spadesRules{}); winner != 3 {
+		t.Errorf("Test Case 2: Expected seat 3 to win with the 2 of Spade. Got seat %d", winner)
+	}
+}
+
+// Test Cases for heartsRules
+// **************************
+//   - The 2 of Club must lead the first trick
+//   - No points may be shed on the first trick
+//   - Shooting the moon gives 26 points to everybody else
+
+func Test_heartsRules(t *testing.T) {
+	rules := heartsRules{}
+	round := &trickRound{hands: [][]card{
+		mustParseCards(t, "2 of Club", "5 of Club", "3 of Heart"),
+		mustParseCards(t, "Q of Spade", "4 of Heart", "9 of Diamond"),
+		mustParseCards(t, "K of Club"),
+		mustParseCards(t, "7 of Club"),
+	}}
+
+	// TEST CASE 1: The 2 of Club leads
+	// --------------------------------
+	if leader := rules.firstLeader(round); leader != 0 {
+		t.Errorf("Test Case 1: Expected seat 0 to lead. Got %d", leader)
+	}
+	legal := rules.legalPlays(round, 0)
+	if len(legal) != 1 || legal[0].toString() != "2 of Club" {
+		t.Errorf("Test Case 1: Expected only the 2 of Club to be legal. Got %v", legal)
+	}
+
+	// TEST CASE 2: No points on the first trick
+	// -----------------------------------------
+	round.trick = []trickPlay{{seat: 0, card: round.hands[0][0]}}
+	legal = rules.legalPlays(round, 1)
+	if len(legal) != 1 || legal[0].toString() != "9 of Diamond" {
+		t.Errorf("Test Case 2: Expected only the 9 of Diamond to be legal. Got %v", legal)
+	}
+
+	// TEST CASE 3: Shooting the moon
+	// ------------------------------
+	round = &trickRound{hands: make([][]card, 4), cardsWon: make([][]card, 4)}
+	for _, c := range mustParseCards(t, newDeck()...) {
+		round.cardsWon[2] = append(round.cardsWon[2], c)
+	}
+	points := rules.scoreRound(round, nil)
+	if !slices.Equal(points, []int{26, 26, 0, 26}) {
+		t.Errorf("Test Case 3: Expected [26 26 0 26]. Got %v", points)
+	}
+}
+
+// Test Cases for spadesRules
+// **************************
+//   - A made contract scores 10 per trick bid plus bags, a failed one loses them
+//   - Nil bids score +/-100 on their own
+//   - 10 bags cost 100 points
+
+func Test_spadesRules(t *testing.T) {
+	rules := &spadesRules{}
+	round := &trickRound{
+		hands:     make([][]card, 4),
+		bids:      []int{4, 3, 0, 5},
+		tricksWon: []int{5, 4, 0, 4},
+	}
+
+	// TEST CASE 1: Contracts and nil bids
+	// -----------------------------------
+	// Team 0 bid 4 + nil, took 5 and the nil made: 40 + 1 bag + 100
+	// Team 1 bid 8, took 8: 80
+	points := rules.scoreRound(round, nil)
+	if !slices.Equal(points, []int{141, 80, 141, 80}) {
+		t.Errorf("Test Case 1: Expected [141 80 141 80]. Got %v", points)
+	}
+
+	// TEST CASE 2: Bag penalty
+	// ------------------------
+	rules.bags[0] = 9
+	round.bids = []int{1, 5, 1, 5}
+	round.tricksWon = []int{2, 5, 1, 5}
+	points = rules.scoreRound(round, nil)
+	// Team 0 bid 2, took 3: 20 + 1 bag, which is the 10th
+	if points[0] != 21-spadesBagPenalty || rules.bags[0] != 0 {
+		t.Errorf("Test Case 2: Expected team 0 to score %d with no bags left. Got %d with %d bags", 21-spadesBagPenalty, points[0], rules.bags[0])
+	}
+}
+
+// Test Cases for trickGame.play()
+// *******************************
+//   - All-bot games should run to completion and declare winners
+//   - The same seed should give the same game
+//   - Illegal plays should be rejected
+
+func Test_trickGamePlay(t *testing.T) {
+	// TEST CASE 1: All-bot Hearts and Spades games complete
+	// -----------------------------------------------------
+	for _, rules := range []func() trickRules{
+		func() trickRules { return heartsRules{} },
+		func() trickRules { return &spadesRules{} },
+	} {
+		r := rules()
+		g, err := newTrickGame(r, newBots(4, r.name() == "Hearts"), 1)
+		if err != nil {
+			t.Fatalf("Test Case 1: Unexpected error: %v", err)
+		}
+		winners, err := g.play(1000)
+		if err != nil {
+			t.Fatalf("Test Case 1: Unexpected error in %s: %v", r.name(), err)
+		}
+		if !r.isGameOver(g.scores) || len(winners) == 0 {
+			t.Errorf("Test Case 1: Expected %s to be over with winners. Got %s", r.name(), g.summary())
+		}
+
+		// TEST CASE 2: The same seed gives the same game
+		// ----------------------------------------------
+		r2 := rules()
+		g2, _ := newTrickGame(r2, newBots(4, r2.name() == "Hearts"), 1)
+		g2.play(1000)
+		if g.summary() != g2.summary() {
+			t.Errorf("Test Case 2: Expected the same result. Got %q and %q", g.summary(), g2.summary())
+		}
+	}
+
+	// TEST CASE 3: Illegal plays are rejected
+	// ---------------------------------------
+	g, _ := newTrickGame(heartsRules{}, []trickPlayer{cheatingPlayer{}, cheatingPlayer{}, cheatingPlayer{}, cheatingPlayer{}}, 1)
+	if _, err := g.playRound(); !errors.Is(err, errTrickIllegalPlay) {
+		t.Errorf("Test Case 3: Expected an illegal play error. Got %v", err)
+	}
+
+	// TEST CASE 4: The number of players must match the rules
+	// -------------------------------------------------------
+	if _, err := newTrickGame(heartsRules{}, newBots(3, true), 1); err == nil {
+		t.Errorf("Test Case 4: Expected an error for 3 players in Hearts")
+	}
+}
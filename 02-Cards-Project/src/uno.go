@@ -0,0 +1,130 @@
+/**
+ * @file: Describes the 108-card Uno deck on top of the generic deck.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Deck Composition
+// ****************
+
+// Colors of the Uno deck. Wild cards have no color.
+var unoColors = [4]string{"Red", "Yellow", "Green", "Blue"}
+
+// Action cards: Two of each per color.
+var unoActions = [3]string{"Skip", "Reverse", "Draw Two"}
+
+// Wild cards: Four of each.
+var unoWilds = [2]string{"Wild", "Wild Draw Four"}
+
+const (
+	// Points of an action card left in hand
+	unoActionPoints = 20
+	// Points of a wild card left in hand
+	unoWildPoints = 50
+)
+
+// Type Declaration
+// ****************
+
+// An unoCard is a colored number ("0" to "9") or action, or a wild card without color.
+type unoCard struct {
+	color string
+	value string
+}
+
+// An unoCodec saves Uno cards by name, such as "Red 7", "Blue Draw Two" or "Wild".
+type unoCodec struct{}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newUnoDeck()
+// Initializes a 108-card Uno deck. For each color: One 0, two of each number from 1 to 9 and
+// two of each action. Then four Wild and four Wild Draw Four.
+func newUnoDeck() genericDeck[unoCard] {
+	d := genericDeck[unoCard]{}
+	for _, color := range unoColors {
+		d = append(d, unoCard{color: color, value: "0"})
+		for range 2 {
+			for n := 1; n <= 9; n++ {
+				d = append(d, unoCard{color: color, value: strconv.Itoa(n)})
+			}
+			for _, action := range unoActions {
+				d = append(d, unoCard{color: color, value: action})
+			}
+		}
+	}
+	for _, wild := range unoWilds {
+		for range 4 {
+			d = append(d, unoCard{value: wild})
+		}
+	}
+	return d
+}
+
+// parseUnoCard()
+// Parses the name of an Uno card, as written by unoCard.toString().
+func parseUnoCard(s string) (unoCard, error) {
+	if slices.Contains(unoWilds[:], s) {
+		return unoCard{value: s}, nil
+	}
+
+	color, value, found := strings.Cut(s, " ")
+	c := unoCard{color: color, value: value}
+	if !found || !slices.Contains(unoColors[:], color) || (!c.isNumber() && !slices.Contains(unoActions[:], value)) {
+		return unoCard{}, fmt.Errorf("invalid uno card %q", s)
+	}
+	return c, nil
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// unoCard.isWild()
+// Receiver Function that tells if the card is a wild card.
+func (c unoCard) isWild() bool {
+	return c.color == ""
+}
+
+// unoCard.isNumber()
+// Receiver Function that tells if the card is a number card.
+func (c unoCard) isNumber() bool {
+	n, err := strconv.Atoi(c.value)
+	return err == nil && n >= 0 && n <= 9 && len(c.value) == 1
+}
+
+// unoCard.points()
+// Receiver Function that returns the points of the card left in hand at the end of a round.
+func (c unoCard) points() int {
+	switch {
+	case c.isWild():
+		return unoWildPoints
+	case c.isNumber():
+		n, _ := strconv.Atoi(c.value)
+		return n
+	}
+	return unoActionPoints
+}
+
+// unoCard.toString()
+// Receiver Function to convert the card into its text form.
+func (c unoCard) toString() string {
+	if c.isWild() {
+		return c.value
+	}
+	return fmt.Sprintf("%s %s", c.color, c.value)
+}
+
+func (unoCodec) encode(c unoCard) string          { return c.toString() }
+func (unoCodec) decode(s string) (unoCard, error) { return parseUnoCard(s) }
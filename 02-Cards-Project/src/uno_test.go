@@ -0,0 +1,73 @@
+/**
+ * @file: Unit tests for the Uno deck
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"math/rand"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// Test Cases for newUnoDeck()
+// ***************************
+//   - The deck should hold 108 cards: 25 per color and 8 wild cards
+//   - Names should be parsed back into the same cards
+//   - Shuffling, dealing and saving should work as for any generic deck
+
+func Test_newUnoDeck(t *testing.T) {
+	// TEST CASE 1: Composition
+	// ------------------------
+	d := newUnoDeck()
+	perColor := map[string]int{}
+	for _, c := range d {
+		perColor[c.color]++
+	}
+	if len(d) != 108 || perColor["Red"] != 25 || perColor["Blue"] != 25 || perColor[""] != 8 {
+		t.Errorf("Test Case 1: Expected 108 cards, 25 per color and 8 wild. Got %d cards: %v", len(d), perColor)
+	}
+	points := 0
+	for _, c := range d {
+		points += c.points()
+	}
+	// Numbers: 4 colors x (1..9 twice) = 360, actions: 24 x 20 = 480, wilds: 8 x 50 = 400
+	if points != 1240 {
+		t.Errorf("Test Case 1: Expected 1240 points in the deck. Got %d", points)
+	}
+
+	// TEST CASE 2: Names are parsed back
+	// ----------------------------------
+	for _, c := range d {
+		parsed, err := parseUnoCard(c.toString())
+		if err != nil || parsed != c {
+			t.Errorf("Test Case 2: Expected to parse %q back. Got %+v (%v)", c.toString(), parsed, err)
+		}
+	}
+	for _, s := range []string{"Purple 3", "Red 10", "Red Wild", "Green"} {
+		if _, err := parseUnoCard(s); err == nil {
+			t.Errorf("Test Case 2: Expected an error for %q", s)
+		}
+	}
+
+	// TEST CASE 3: Shuffle, deal and save
+	// -----------------------------------
+	d.shuffle(rand.New(rand.NewSource(108)), 1)
+	hand, rest, err := d.deal(7)
+	if err != nil || len(hand) != 7 || len(rest) != 101 {
+		t.Fatalf("Test Case 3: Expected a hand of 7 and 101 remaining (%v)", err)
+	}
+	filename := filepath.Join(t.TempDir(), "uno.sav")
+	if err := rest.saveToFile(filename, unoCodec{}); err != nil {
+		t.Fatalf("Test Case 3: Unexpected error: %v", err)
+	}
+	loaded, err := newGenericDeckFromFile(filename, unoCodec{})
+	if err != nil || !slices.Equal(loaded, rest) {
+		t.Errorf("Test Case 3: Expected the same 101 cards back (%v)", err)
+	}
+}
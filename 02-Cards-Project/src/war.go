@@ -0,0 +1,155 @@
+/**
+ * @file: Describes the game of War for 2 players.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Constants
+// *********
+
+const (
+	// Number of face-down cards each player puts in a war
+	warFaceDown = 3
+	// War can go on forever: Stop after this many battles and count the cards
+	warMaxBattles = 10000
+)
+
+// Interfaces
+// **********
+
+// warPlayer is a seat at a game of War. There is no choice to make: Only whether to keep going.
+type warPlayer interface {
+	// Whether the player is ready to flip the next card (false to concede)
+	ready(view warView) bool
+}
+
+// Type Declaration
+// ****************
+
+// A warView is what a player can see before a battle.
+type warView struct {
+	seat   int
+	battle int
+	piles  [2]int
+}
+
+// A warGame holds the pile of each player, top card first.
+//   - conceded: Seat that gave up the game, or -1
+type warGame struct {
+	players  [2]warPlayer
+	piles    [2][]card
+	battles  int
+	wars     int
+	conceded int
+}
+
+// A warBot is always ready.
+type warBot struct{}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newWarGame()
+// Initializes a game of War: A deck shuffled with the seed is split between the 2 players.
+func newWarGame(players [2]warPlayer, seed int64) (*warGame, error) {
+	hands, _, err := dealHands(2, 26, rand.New(rand.NewSource(seed)))
+	if err != nil {
+		return nil, err
+	}
+	return &warGame{players: players, piles: [2][]card{hands[0], hands[1]}, conceded: -1}, nil
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+func (warBot) ready(warView) bool { return true }
+
+// consolePlayer.ready()
+// Receiver Function that waits for the human to press Enter (or "q" to concede).
+func (p *consolePlayer) ready(view warView) bool {
+	prompt := fmt.Sprintf("Battle %d: You have %d card(s), your opponent %d. Press Enter to flip (q to quit): ",
+		view.battle+1, view.piles[view.seat], view.piles[1-view.seat])
+	line, ok := p.readLine(prompt)
+	return ok && line != "q"
+}
+
+// warGame.battle()
+// Receiver Function that plays a single battle (including any wars on ties).
+// Returns the seat that won it, or false if a player conceded instead.
+func (g *warGame) battle() (int, bool) {
+	for seat, player := range g.players {
+		if !player.ready(warView{seat: seat, battle: g.battles, piles: [2]int{len(g.piles[0]), len(g.piles[1])}}) {
+			g.conceded = seat
+			return 1 - seat, false
+		}
+	}
+	g.battles++
+
+	pot := []card{}
+	for {
+		// A player without cards to flip loses the battle
+		for seat := range g.piles {
+			if len(g.piles[seat]) == 0 {
+				g.piles[1-seat] = append(g.piles[1-seat], pot...)
+				return 1 - seat, true
+			}
+		}
+
+		// Flip the top card of each pile
+		flipped := [2]card{}
+		for seat := range g.piles {
+			flipped[seat] = g.piles[seat][0]
+			g.piles[seat] = g.piles[seat][1:]
+			pot = append(pot, flipped[seat])
+		}
+
+		// The highest card takes the pot
+		if highRank(flipped[0]) != highRank(flipped[1]) {
+			winner := 0
+			if highRank(flipped[1]) > highRank(flipped[0]) {
+				winner = 1
+			}
+			g.piles[winner] = append(g.piles[winner], pot...)
+			return winner, true
+		}
+
+		// Tie: War! Each player puts face-down cards in the pot (keeping one to flip if possible)
+		g.wars++
+		for seat := range g.piles {
+			n := min(warFaceDown, max(len(g.piles[seat])-1, 0))
+			pot = append(pot, g.piles[seat][:n]...)
+			g.piles[seat] = g.piles[seat][n:]
+		}
+	}
+}
+
+// warGame.play()
+// Receiver Function that plays battles until a player has every card, concedes, or the battle limit is reached.
+// The player with the most cards wins.
+func (g *warGame) play() gameSummary {
+	for g.battles < warMaxBattles && len(g.piles[0]) > 0 && len(g.piles[1]) > 0 {
+		if _, ok := g.battle(); !ok {
+			break
+		}
+	}
+
+	summary := gameSummary{
+		game:   "War",
+		turns:  g.battles,
+		scores: []int{len(g.piles[0]), len(g.piles[1])},
+	}
+	summary.winners = highestScores(summary.scores)
+	if g.conceded >= 0 {
+		summary.winners = []int{1 - g.conceded}
+	}
+	return summary
+}
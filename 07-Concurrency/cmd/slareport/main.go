@@ -0,0 +1,144 @@
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/maevadevs/Go-Developer-Foundation/07-Concurrency/history"
+)
+
+// Command Structure
+// *****************
+// slareport - Reports the uptime, incidents, MTTR and latency percentiles of each target from the history store
+//   -month YYYY-MM     Report on a calendar month, in UTC (default: the last full month)
+//   -from TIME         Start of the report: A date (2006-01-02) or a time (RFC 3339). Overrides -month
+//   -to TIME           End of the report, excluded (default: now)
+//   -sla PERCENT       Uptime promised to customers: List the targets below it (default: none)
+//   -json              Print the report as JSON
+//
+// The command exits with status 1 if a target is below the SLA.
+
+// Functions
+// *********
+
+// This is the main entry of the command.
+func main() {
+	month := flag.String("month", "", "calendar month to report on, in UTC (default: the last full month)")
+	from := flag.String("from", "", "start of the report: a date (2006-01-02) or a time (RFC 3339)")
+	to := flag.String("to", "", "end of the report, excluded (default: now)")
+	sla := flag.Float64("sla", 0, "uptime promised to customers, in percent: list the targets below it")
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: slareport [-month YYYY-MM | -from TIME [-to TIME]] [-sla PERCENT] [-json] DIR")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := flag.Arg(0)
+
+	// The time range: A month, or from a time to another
+	start, end, err := timeRange(*month, *from, *to, time.Now())
+	if err != nil {
+		fail(err)
+	}
+
+	// A missing directory is a mistake, not an empty report
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		fail(fmt.Errorf("%s is not a history store", dir))
+	}
+	store, err := history.Open(history.DefaultOptions(dir))
+	if err != nil {
+		fail(err)
+	}
+	report, err := history.NewReport(store, start, end)
+	if err != nil {
+		fail(err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(report)
+	} else {
+		report.WriteText(os.Stdout)
+	}
+
+	// The targets below the SLA
+	if *sla > 0 {
+		var missed []string
+		for _, t := range report.Targets {
+			if t.UptimePercent < *sla {
+				missed = append(missed, fmt.Sprintf("%s (%.3f%%)", t.Target, t.UptimePercent))
+			}
+		}
+		if len(missed) > 0 {
+			fmt.Fprintf(os.Stderr, "\nBelow the %g%% SLA: %s\n", *sla, strings.Join(missed, ", "))
+			os.Exit(1)
+		}
+	}
+}
+
+// timeRange returns the range of the report: From -from to -to if given, otherwise the month,
+// otherwise the last full month before now.
+func timeRange(month, from, to string, now time.Time) (time.Time, time.Time, error) {
+	if from != "" {
+		start, err := parseTime(from)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		end := now
+		if to != "" {
+			if end, err = parseTime(to); err != nil {
+				return time.Time{}, time.Time{}, err
+			}
+		}
+		return start, end, nil
+	}
+	if to != "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("-to needs -from")
+	}
+	var start time.Time
+	if month == "" {
+		thisMonth := time.Date(now.UTC().Year(), now.UTC().Month(), 1, 0, 0, 0, 0, time.UTC)
+		start = thisMonth.AddDate(0, -1, 0)
+	} else {
+		var err error
+		if start, err = time.Parse("2006-01", month); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("bad month %q: expected YYYY-MM", month)
+		}
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+// parseTime parses a date (2006-01-02, at midnight UTC) or a time (RFC 3339).
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("bad time %q: expected 2006-01-02 or RFC 3339", s)
+	}
+	return t, nil
+}
+
+// fail prints the error and exits.
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "Error:", err)
+	os.Exit(2)
+}
+
+// To run:                 go run ./07-Concurrency/cmd/slareport -month 2026-09 ./07-Concurrency/history-data
+// To check an SLA:        go run ./07-Concurrency/cmd/slareport -sla 99.9 ./07-Concurrency/history-data
+// To output JSON:         go run ./07-Concurrency/cmd/slareport -from 2026-09-01 -to 2026-09-15 -json ./07-Concurrency/history-data
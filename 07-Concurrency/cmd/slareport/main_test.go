@@ -0,0 +1,102 @@
+/**
+ * @file: Unit tests for the time range of the SLA report
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"testing"
+	"time"
+)
+
+// date returns midnight UTC of the day.
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// Test Cases for timeRange()
+// **************************
+//   - Without flags, the range should be the last full month in UTC, across years and time zones
+//   - -month should give a whole calendar month, leap years and December included
+//   - -from should start the range, up to -to or now, whatever -month says
+//   - -to without -from, and bad months and times, should be errors
+
+func Test_timeRange(t *testing.T) {
+	march15 := time.Date(2026, 3, 15, 10, 0, 0, 0, time.UTC)
+	// Already March 1 in UTC+2, still February 28 in UTC
+	aheadOfUtc := time.Date(2026, 3, 1, 1, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60))
+
+	tests := []struct {
+		name              string
+		month, from, to   string
+		now               time.Time
+		wantStart, wantTo time.Time
+		wantErr           bool
+	}{
+		{name: "Last full month", now: march15, wantStart: date(2026, 2, 1), wantTo: date(2026, 3, 1)},
+		{name: "Last full month of the year before", now: date(2026, 1, 10), wantStart: date(2025, 12, 1), wantTo: date(2026, 1, 1)},
+		{name: "First instant of a month", now: date(2026, 3, 1), wantStart: date(2026, 2, 1), wantTo: date(2026, 3, 1)},
+		{name: "Month of now in UTC", now: aheadOfUtc, wantStart: date(2026, 1, 1), wantTo: date(2026, 2, 1)},
+		{name: "Leap February", month: "2024-02", now: march15, wantStart: date(2024, 2, 1), wantTo: date(2024, 3, 1)},
+		{name: "December", month: "2025-12", now: march15, wantStart: date(2025, 12, 1), wantTo: date(2026, 1, 1)},
+		{name: "Current month", month: "2026-03", now: march15, wantStart: date(2026, 3, 1), wantTo: date(2026, 4, 1)},
+		{name: "From until now", from: "2026-03-01", now: march15, wantStart: date(2026, 3, 1), wantTo: march15},
+		{name: "From to", from: "2026-02-15", to: "2026-03-01T12:00:00Z", now: march15,
+			wantStart: date(2026, 2, 15), wantTo: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{name: "From over month", month: "2025-06", from: "2026-03-01", now: march15, wantStart: date(2026, 3, 1), wantTo: march15},
+		{name: "To without from", to: "2026-03-01", now: march15, wantErr: true},
+		{name: "To without from, with a month", month: "2026-02", to: "2026-03-01", now: march15, wantErr: true},
+		{name: "Bad month", month: "2026-13", now: march15, wantErr: true},
+		{name: "Month with a day", month: "2026-02-01", now: march15, wantErr: true},
+		{name: "Bad from", from: "March 1", now: march15, wantErr: true},
+		{name: "Bad to", from: "2026-03-01", to: "tomorrow", now: march15, wantErr: true},
+	}
+
+	for i, test := range tests {
+		start, end, err := timeRange(test.month, test.from, test.to, test.now)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("Test Case %d: %s: Expected an error. Got %v to %v", i+1, test.name, start, end)
+			}
+			continue
+		}
+		if err != nil || !start.Equal(test.wantStart) || !end.Equal(test.wantTo) {
+			t.Errorf("Test Case %d: %s: Expected %v to %v. Got %v to %v (%v)", i+1, test.name, test.wantStart, test.wantTo, start, end, err)
+		}
+	}
+}
+
+// Test Cases for parseTime()
+// **************************
+//   - A date should be midnight UTC
+//   - An RFC 3339 time should keep its instant, whatever its offset
+//   - Anything else should be an error
+
+func Test_parseTime(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{in: "2026-09-01", want: date(2026, 9, 1)},
+		{in: "2024-02-29", want: date(2024, 2, 29)},
+		{in: "2026-09-01T10:30:00Z", want: time.Date(2026, 9, 1, 10, 30, 0, 0, time.UTC)},
+		{in: "2026-09-01T02:00:00+02:00", want: date(2026, 9, 1)},
+		{in: "2026-09-01T10:30:00.5Z", want: time.Date(2026, 9, 1, 10, 30, 0, 5e8, time.UTC)},
+		{in: "2026-02-29", wantErr: true},
+		{in: "2026-09-01T10:30", wantErr: true},
+		{in: "2026/09/01", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for i, test := range tests {
+		got, err := parseTime(test.in)
+		if (err != nil) != test.wantErr || !got.Equal(test.want) {
+			t.Errorf("Test Case %d: Expected %v (error: %t) for %q. Got %v (%v)", i+1, test.want, test.wantErr, test.in, got, err)
+		}
+	}
+}
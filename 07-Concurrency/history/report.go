@@ -0,0 +1,265 @@
+/**
+ * @file: Describes the uptime and SLA reports computed from the history store.
+ *
+ * For each target, over a time range:
+ *   - Uptime: The share of its checks that were not down
+ *   - Incidents: How many times it went down or started flapping, and the mean time to repair (MTTR)
+ *     of the incidents that ended in the range
+ *   - Latency: p50, p95 and p99
+ *
+ * Raw records give exact figures. Where the range was compacted into rollups, the percentiles
+ * are estimated from the latency buckets: The report says so.
+ */
+
+// Package
+// *******
+package history
+
+// Imports
+// *******
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Type Declaration
+// ****************
+
+// A Report holds the uptime and SLA figures of every target over a time range, sorted by target.
+type Report struct {
+	From    time.Time      `json:"from"`
+	To      time.Time      `json:"to"`
+	Targets []TargetReport `json:"targets"`
+}
+
+// A TargetReport holds the uptime and SLA figures of a target over a time range.
+type TargetReport struct {
+	Target        string  `json:"target"`
+	URL           string  `json:"url,omitempty"`
+	Checks        int     `json:"checks"`
+	UpChecks      int     `json:"up_checks"`
+	UptimePercent float64 `json:"uptime_percent"`
+	// Incidents that started in the range
+	Incidents int `json:"incidents"`
+	// Incidents that ended in the range, and their mean duration
+	Repaired    int     `json:"repaired"`
+	MTTRSeconds float64 `json:"mttr_seconds"`
+	P50Ms       float64 `json:"p50_ms"`
+	P95Ms       float64 `json:"p95_ms"`
+	P99Ms       float64 `json:"p99_ms"`
+	// True when the percentiles are estimated from rollups: Part of the range was compacted
+	Estimated bool `json:"estimated"`
+}
+
+// A reportTotals adds up the records and rollups of a target.
+type reportTotals struct {
+	url           string
+	checks        int
+	upChecks      int
+	incidents     int
+	repaired      int
+	repairSeconds float64
+	latencies     []float64
+	buckets       []int
+	maxLatency    float64
+	rolledUp      bool
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// NewReport computes the report of every target of the store over [from, to).
+//
+// Incidents are followed from the oldest record kept: One that started before the range
+// and ended in it counts in the MTTR, not in the incidents.
+//
+// The report reads the store as the last compaction left it: Rollups for the hours before it,
+// and raw records from it onward. A segment left behind by a compaction cut short is not counted twice.
+func NewReport(s *Store, from, to time.Time) (Report, error) {
+	if !from.Before(to) {
+		return Report{}, errors.New("history: the report should end after it starts")
+	}
+	state, err := s.loadCompaction()
+	if err != nil {
+		return Report{}, err
+	}
+
+	// Raw records since the last compaction, in time order: The incidents open at the last compaction carry over
+	var records []Record
+	err = s.Scan(state.Before, to, func(r Record) error {
+		records = append(records, r)
+		return nil
+	})
+	if err != nil {
+		return Report{}, err
+	}
+	slices.SortStableFunc(records, func(x, y Record) int { return x.At.Compare(y.At) })
+	totals := map[string]*reportTotals{}
+	get := func(id string) *reportTotals {
+		t, found := totals[id]
+		if !found {
+			t = &reportTotals{buckets: make([]int, len(LatencyBuckets)+1)}
+			totals[id] = t
+		}
+		return t
+	}
+	tracker := &incidentTracker{open: maps.Clone(state.Open)}
+	for _, r := range records {
+		started, ended, lasted := tracker.observe(r)
+		if r.At.Before(from) {
+			continue
+		}
+		t := get(r.Target)
+		t.url = r.URL
+		t.checks++
+		if r.Verdict != VerdictDown {
+			t.upChecks++
+		}
+		t.latencies = append(t.latencies, r.LatencyMs)
+		t.buckets[bucketOf(r.LatencyMs)]++
+		t.maxLatency = max(t.maxLatency, r.LatencyMs)
+		if started {
+			t.incidents++
+		}
+		if ended {
+			t.repaired++
+			t.repairSeconds += lasted.Seconds()
+		}
+	}
+
+	// Rollups: The part of the range that was compacted
+	err = s.ScanRollups(from, minTime(to, state.Before), func(ru Rollup) error {
+		t := get(ru.Target)
+		if t.url == "" {
+			t.url = ru.URL
+		}
+		t.checks += ru.Checks
+		t.upChecks += ru.UpChecks
+		t.incidents += ru.Incidents
+		t.repaired += ru.Repaired
+		t.repairSeconds += ru.RepairSeconds
+		for i, n := range ru.Buckets {
+			if i < len(t.buckets) {
+				t.buckets[i] += n
+			}
+		}
+		t.maxLatency = max(t.maxLatency, ru.LatencyMaxMs)
+		t.rolledUp = true
+		return nil
+	})
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{From: from, To: to, Targets: make([]TargetReport, 0, len(totals))}
+	for id, t := range totals {
+		report.Targets = append(report.Targets, t.report(id))
+	}
+	slices.SortFunc(report.Targets, func(x, y TargetReport) int { return strings.Compare(x.Target, y.Target) })
+	return report, nil
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// WriteText writes the report as a table, one line per target. Estimated percentiles start with ~.
+func (r Report) WriteText(w io.Writer) error {
+	const layout = "2006-01-02 15:04 MST"
+	fmt.Fprintf(w, "Report from %s to %s\n\n", r.From.Format(layout), r.To.Format(layout))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TARGET\tCHECKS\tUPTIME\tINCIDENTS\tMTTR\tP50\tP95\tP99")
+	for _, t := range r.Targets {
+		mttr := "-"
+		if t.Repaired > 0 {
+			mttr = time.Duration(t.MTTRSeconds * float64(time.Second)).Round(time.Second).String()
+		}
+		approx := ""
+		if t.Estimated {
+			approx = "~"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%.3f%%\t%d\t%s\t%s%s\t%s%s\t%s%s\n", t.Target, t.Checks, t.UptimePercent, t.Incidents, mttr,
+			approx, formatMs(t.P50Ms), approx, formatMs(t.P95Ms), approx, formatMs(t.P99Ms))
+	}
+	return tw.Flush()
+}
+
+// report turns the totals of a target into its report.
+func (t *reportTotals) report(id string) TargetReport {
+	tr := TargetReport{
+		Target:    id,
+		URL:       t.url,
+		Checks:    t.checks,
+		UpChecks:  t.upChecks,
+		Incidents: t.incidents,
+		Repaired:  t.repaired,
+		Estimated: t.rolledUp,
+	}
+	if t.checks > 0 {
+		tr.UptimePercent = 100 * float64(t.upChecks) / float64(t.checks)
+	}
+	if t.repaired > 0 {
+		tr.MTTRSeconds = t.repairSeconds / float64(t.repaired)
+	}
+	percentile := func(q float64) float64 {
+		if t.rolledUp {
+			return bucketPercentile(t.buckets, t.maxLatency, q)
+		}
+		return exactPercentile(t.latencies, q)
+	}
+	slices.Sort(t.latencies)
+	tr.P50Ms, tr.P95Ms, tr.P99Ms = percentile(0.50), percentile(0.95), percentile(0.99)
+	return tr
+}
+
+// Helper Functions
+// ****************
+
+// exactPercentile returns the q-th percentile of sorted latencies (nearest rank). 0 when there is none.
+func exactPercentile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(q * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+// bucketPercentile estimates the q-th percentile from latency buckets: The upper bound of the bucket
+// it falls in, capped by the slowest latency. 0 when the buckets are empty.
+func bucketPercentile(buckets []int, slowest float64, q float64) float64 {
+	total := 0
+	for _, n := range buckets {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+	rank := max(int(math.Ceil(q*float64(total))), 1)
+	cumulative := 0
+	for i, n := range buckets {
+		cumulative += n
+		if cumulative >= rank && i < len(LatencyBuckets) {
+			return min(LatencyBuckets[i], slowest)
+		}
+	}
+	return slowest
+}
+
+// minTime returns the earlier of two times.
+func minTime(x, y time.Time) time.Time {
+	if y.Before(x) {
+		return y
+	}
+	return x
+}
+
+// formatMs formats a latency in milliseconds as a duration, such as 125ms or 1.5s.
+func formatMs(ms float64) string {
+	return time.Duration(ms * float64(time.Millisecond)).Round(time.Millisecond).String()
+}
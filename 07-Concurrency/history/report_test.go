@@ -0,0 +1,116 @@
+/**
+ * @file: Unit tests for the uptime and SLA reports
+ */
+
+// Package
+// *******
+package history
+
+// Imports
+// *******
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test Cases for Report
+// *********************
+//   - A report from raw records should have the exact uptime, incidents, MTTR and percentiles
+//   - A report over compacted records should have the same uptime, incidents and MTTR, and estimated percentiles
+//   - A report should only count the records of its range
+//   - The text report should have one line per target
+//   - A segment left behind by a compaction cut short should not count twice
+
+func Test_NewReport(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(DefaultOptions(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	appendAll(t, s, testRecords())
+	appendAll(t, s, []Record{newRecord("b", 3*time.Hour, VerdictUp, StateUp, 5)})
+	day := t0.Add(24 * time.Hour)
+
+	// TEST CASE 1: From raw records
+	// -----------------------------
+	report, err := NewReport(s, t0, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Targets) != 2 || report.Targets[0].Target != "a" || report.Targets[1].Target != "b" {
+		t.Fatalf("Test Case 1: Expected the reports of a and b. Got %+v", report.Targets)
+	}
+	a := report.Targets[0]
+	if a.Checks != 24 || a.UptimePercent != 75 || a.Incidents != 2 || a.Repaired != 2 || a.MTTRSeconds != 1800 {
+		t.Errorf("Test Case 1: Expected 24 checks, 75%% uptime, 2 incidents repaired in 30m on average. Got %+v", a)
+	}
+	if a.P50Ms != 120 || a.P95Ms != 230 || a.P99Ms != 240 || a.Estimated {
+		t.Errorf("Test Case 1: Expected the exact percentiles 120, 230 and 240ms. Got %+v", a)
+	}
+
+	// TEST CASE 2: Over compacted records
+	// -----------------------------------
+	if _, err := s.Compact(t0.Add(3*time.Hour + DefaultRawRetention)); err != nil {
+		t.Fatal(err)
+	}
+	report, err = NewReport(s, t0, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a = report.Targets[0]
+	if a.Checks != 24 || a.UptimePercent != 75 || a.Incidents != 2 || a.Repaired != 2 || a.MTTRSeconds != 1800 {
+		t.Errorf("Test Case 2: Expected the same uptime, incidents and MTTR after compaction. Got %+v", a)
+	}
+	// 110ms to 240ms fall in the bucket up to 250ms: Capped by the slowest
+	if a.P50Ms != 240 || a.P99Ms != 240 || !a.Estimated {
+		t.Errorf("Test Case 2: Expected estimated percentiles of 240ms. Got %+v", a)
+	}
+
+	// TEST CASE 3: Only the records of the range
+	// ------------------------------------------
+	report, err = NewReport(s, t0.Add(3*time.Hour), day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a = report.Targets[0]
+	// The second incident started before the range: It counts in the MTTR only
+	if a.Checks != 6 || a.UpChecks != 4 || a.Incidents != 0 || a.Repaired != 1 || a.MTTRSeconds != 1800 || a.Estimated {
+		t.Errorf("Test Case 3: Expected 6 checks, 4 up, and the repair of an incident started before. Got %+v", a)
+	}
+	if _, err := NewReport(s, day, t0); err == nil {
+		t.Error("Test Case 3: Expected an error for a range that ends before it starts. Got none")
+	}
+
+	// TEST CASE 4: Text report
+	// ------------------------
+	var sb strings.Builder
+	report, _ = NewReport(s, t0, day)
+	if err := report.WriteText(&sb); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[3], "a ") || !strings.Contains(lines[3], "75.000%") || !strings.Contains(lines[3], "~240ms") {
+		t.Errorf("Test Case 4: Expected a title, a header and a line per target. Got\n%s", sb.String())
+	}
+
+	// TEST CASE 5: Segment left behind
+	// --------------------------------
+	var left []Record
+	for _, r := range testRecords() {
+		if r.At.Before(t0.Add(time.Hour)) {
+			left = append(left, r)
+		}
+	}
+	behind, _ := Open(DefaultOptions(dir))
+	appendAll(t, behind, left)
+	behind.Close()
+	report, err = NewReport(s, t0, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := report.Targets[0]; a.Checks != 24 {
+		t.Errorf("Test Case 5: Expected the 24 checks counted once. Got %+v", a)
+	}
+}
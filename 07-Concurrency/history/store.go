@@ -0,0 +1,609 @@
+/**
+ * @file: Describes the history store: Every check result of the monitor, kept on disk.
+ *
+ * The store is an append-only log, split into segment files of one JSON record per line:
+ *   - A new segment starts every hour, or once the current one is too large
+ *   - Compaction rolls the segments older than the raw retention up into hourly rollups, then deletes them
+ *   - Rollups are kept in one file per month, and deleted after the rollup retention
+ *   - compaction.json tells up to when the records were rolled up: Readers take rollups before it, and raw records after
+ *
+ * The monitor is the only writer. Reports can read the store at the same time, from another process.
+ */
+
+// Package
+// *******
+package history
+
+// Imports
+// *******
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Constants
+// *********
+
+// Defaults of a store.
+const (
+	// A segment larger than this is closed, even before the end of its hour
+	DefaultMaxSegmentBytes = 16 << 20
+	// Raw records older than this are rolled up
+	DefaultRawRetention = 7 * 24 * time.Hour
+	// Rollups older than this are deleted: A bit over a year, for yearly reports
+	DefaultRollupRetention = 400 * 24 * time.Hour
+)
+
+// Verdicts of a check, and states of a target, as recorded by the monitor.
+const (
+	VerdictUp       = "up"
+	VerdictDegraded = "degraded"
+	VerdictDown     = "down"
+
+	StateUnknown  = "unknown"
+	StateUp       = "up"
+	StateDown     = "down"
+	StateFlapping = "flapping"
+)
+
+// Upper bounds of the latency buckets of a rollup, in milliseconds. The last bucket has no bound.
+var LatencyBuckets = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// Names of the files of a store.
+const (
+	segmentPrefix  = "segment-"
+	rollupPrefix   = "rollup-"
+	fileExt        = ".jsonl"
+	compactionFile = "compaction.json"
+	// Hour of a segment, and month of a rollup file, in their names: They sort in time order
+	hourLayout  = "20060102T15"
+	monthLayout = "200601"
+)
+
+// Errors
+// ******
+
+// ErrCorrupt is returned when a file of the store cannot be read back.
+var ErrCorrupt = errors.New("history store is corrupt")
+
+// Type Declaration
+// ****************
+
+// A Record is the result of one check, with the state of its target after it.
+type Record struct {
+	Target     string    `json:"target"`
+	URL        string    `json:"url,omitempty"`
+	At         time.Time `json:"at"`
+	LatencyMs  float64   `json:"latency_ms"`
+	Verdict    string    `json:"verdict"`
+	State      string    `json:"state"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// A Rollup sums up the records of a target over one hour.
+type Rollup struct {
+	Target       string    `json:"target"`
+	URL          string    `json:"url,omitempty"`
+	Hour         time.Time `json:"hour"`
+	Checks       int       `json:"checks"`
+	UpChecks     int       `json:"up_checks"`
+	LatencySumMs float64   `json:"latency_sum_ms"`
+	LatencyMaxMs float64   `json:"latency_max_ms"`
+	// Checks per latency bucket (not cumulative), with the unbounded bucket last
+	Buckets []int `json:"buckets"`
+	// Incidents that started in the hour
+	Incidents int `json:"incidents"`
+	// Incidents that ended in the hour, and their total duration
+	Repaired      int     `json:"repaired"`
+	RepairSeconds float64 `json:"repair_seconds"`
+}
+
+// Options tell where a store lives, and how long it keeps its records.
+type Options struct {
+	Dir             string
+	MaxSegmentBytes int64
+	RawRetention    time.Duration
+	RollupRetention time.Duration
+}
+
+// A CompactStats tells what a compaction did.
+type CompactStats struct {
+	Segments        int
+	Records         int
+	Rollups         int
+	RemovedRollups  int
+	CompactedBefore time.Time
+}
+
+// A Store is the history of the checks, on disk. Its methods are safe for concurrent use.
+type Store struct {
+	opts Options
+	mu   sync.Mutex
+	// Segment being written: nil until the first record
+	segment     *os.File
+	segmentName string
+	segmentHour time.Time
+	segmentSize int64
+}
+
+// A segmentFile is a segment on disk: Its name, and the hour it started.
+type segmentFile struct {
+	name string
+	hour time.Time
+}
+
+// A compaction is what compaction leaves for the readers: Up to when the raw records are gone,
+// and the incidents still open at that time.
+type compaction struct {
+	Before time.Time            `json:"before"`
+	Open   map[string]time.Time `json:"open"`
+}
+
+// An incidentTracker follows the incidents of every target from its records, in time order.
+// An incident starts when a target goes down or starts flapping, and ends when it is up again.
+type incidentTracker struct {
+	open map[string]time.Time
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// DefaultOptions returns the default options of a store in dir.
+func DefaultOptions(dir string) Options {
+	return Options{
+		Dir:             dir,
+		MaxSegmentBytes: DefaultMaxSegmentBytes,
+		RawRetention:    DefaultRawRetention,
+		RollupRetention: DefaultRollupRetention,
+	}
+}
+
+// Open opens the store in opts.Dir, and creates the directory if needed.
+// Records are appended to a new segment: A segment cut short by a crash is never written again.
+func Open(opts Options) (*Store, error) {
+	if opts.Dir == "" {
+		return nil, errors.New("history: no directory")
+	}
+	if opts.MaxSegmentBytes <= 0 || opts.RawRetention <= 0 || opts.RollupRetention <= 0 {
+		return nil, fmt.Errorf("history: the segment size and the retentions should be positive")
+	}
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Store{opts: opts}, nil
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// Append writes a record at the end of the log. The segment changes with the hour of the record,
+// or once it is full.
+func (s *Store) Append(r Record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hour := r.At.UTC().Truncate(time.Hour)
+	// A late record (its check started in the previous hour) stays in the current segment
+	if s.segment == nil || hour.After(s.segmentHour) || s.segmentSize+int64(len(line)) > s.opts.MaxSegmentBytes {
+		if hour.Before(s.segmentHour) {
+			hour = s.segmentHour
+		}
+		if err := s.rotate(hour); err != nil {
+			return err
+		}
+	}
+	n, err := s.segment.Write(line)
+	s.segmentSize += int64(n)
+	return err
+}
+
+// Close closes the segment being written.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.segment == nil {
+		return nil
+	}
+	err := s.segment.Close()
+	s.segment = nil
+	return err
+}
+
+// Scan calls fn with every raw record of the store in [from, to), segment by segment.
+// Records are in the order they were written: Roughly, but not strictly, in time order.
+func (s *Store) Scan(from, to time.Time, fn func(Record) error) error {
+	segments, err := s.segments()
+	if err != nil {
+		return err
+	}
+	for i, seg := range segments {
+		// A segment holds the records from its hour until the next segment (give or take a late record)
+		if seg.hour.After(to) {
+			break
+		}
+		if i+1 < len(segments) && segments[i+1].hour.Add(time.Hour).Before(from) {
+			continue
+		}
+		err := readLines(filepath.Join(s.opts.Dir, seg.name), func(r Record) error {
+			if r.At.Before(from) || !r.At.Before(to) {
+				return nil
+			}
+			return fn(r)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ScanRollups calls fn with every rollup of the store whose hour is in [from, to).
+func (s *Store) ScanRollups(from, to time.Time, fn func(Rollup) error) error {
+	names, err := s.files(rollupPrefix)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		month, err := time.Parse(monthLayout, strings.TrimSuffix(strings.TrimPrefix(name, rollupPrefix), fileExt))
+		if err != nil || !month.AddDate(0, 1, 0).After(from) || !month.Before(to) {
+			continue
+		}
+		err = readLines(filepath.Join(s.opts.Dir, name), func(r Rollup) error {
+			if r.Hour.Before(from) || !r.Hour.Before(to) {
+				return nil
+			}
+			return fn(r)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Compact rolls the segments older than the raw retention up into hourly rollups, and deletes them.
+// The segments of an hour are rolled up together, once the whole hour is older than the raw retention.
+// It then deletes the rollups older than the rollup retention. It never touches the segment being written.
+//
+// The segments a compaction cut short already rolled up are deleted, not rolled up again.
+// Rollup files are replaced at once: Only a crash between replacing them and saving compaction.json
+// counts records twice. Run it from one place only.
+func (s *Store) Compact(now time.Time) (CompactStats, error) {
+	s.mu.Lock()
+	current := s.segmentName
+	s.mu.Unlock()
+
+	var stats CompactStats
+	state, err := s.loadCompaction()
+	if err != nil {
+		return stats, err
+	}
+	segments, err := s.segments()
+	if err != nil {
+		return stats, err
+	}
+
+	// Roll up the old segments, in order: Incidents carry over from one to the next
+	cutoff := now.Add(-s.opts.RawRetention)
+	tracker := &incidentTracker{open: state.Open}
+	rollups := map[string]*Rollup{}
+	var done []segmentFile
+	for next := 0; next < len(segments); {
+		// The segments of an hour are rolled up together, once all of them are past the cutoff:
+		// They end where the segments of a later hour start, at least an hour after theirs
+		first := next
+		for next < len(segments) && segments[next].hour.Equal(segments[first].hour) {
+			next++
+		}
+		hour := segments[first:next]
+		end := hour[0].hour.Add(time.Hour)
+		if next < len(segments) {
+			end = segments[next].hour
+		}
+		if slices.ContainsFunc(hour, func(seg segmentFile) bool { return seg.name == current }) {
+			break
+		}
+		// Rolled up by a compaction cut short before deleting them
+		if !end.After(state.Before) {
+			for _, seg := range hour {
+				if err := os.Remove(filepath.Join(s.opts.Dir, seg.name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+					return stats, err
+				}
+			}
+			continue
+		}
+		if end.After(cutoff) {
+			break
+		}
+		var records []Record
+		for _, seg := range hour {
+			err := readLines(filepath.Join(s.opts.Dir, seg.name), func(r Record) error {
+				records = append(records, r)
+				return nil
+			})
+			if err != nil {
+				return stats, err
+			}
+		}
+		slices.SortStableFunc(records, func(x, y Record) int { return x.At.Compare(y.At) })
+		for _, r := range records {
+			rollupAdd(rollups, r, tracker)
+		}
+		stats.Records += len(records)
+		state.Before = end
+		done = append(done, hour...)
+	}
+
+	if len(done) > 0 {
+		if err := s.writeRollups(rollups); err != nil {
+			return stats, err
+		}
+		state.Open = tracker.open
+		if err := s.saveCompaction(state); err != nil {
+			return stats, err
+		}
+		for _, seg := range done {
+			if err := os.Remove(filepath.Join(s.opts.Dir, seg.name)); err != nil {
+				return stats, err
+			}
+		}
+	}
+	stats.Segments, stats.Rollups, stats.CompactedBefore = len(done), len(rollups), state.Before
+
+	// Delete the rollup files whose whole month is past the retention
+	names, err := s.files(rollupPrefix)
+	if err != nil {
+		return stats, err
+	}
+	for _, name := range names {
+		month, err := time.Parse(monthLayout, strings.TrimSuffix(strings.TrimPrefix(name, rollupPrefix), fileExt))
+		if err != nil || month.AddDate(0, 1, 0).After(now.Add(-s.opts.RollupRetention)) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.opts.Dir, name)); err != nil {
+			return stats, err
+		}
+		stats.RemovedRollups++
+	}
+	return stats, nil
+}
+
+// rotate closes the current segment, and starts a new one for the hour. The store must be locked.
+func (s *Store) rotate(hour time.Time) error {
+	if s.segment != nil {
+		if err := s.segment.Close(); err != nil {
+			return err
+		}
+		s.segment = nil
+	}
+	// Several segments can start in the same hour: Number them
+	prefix := segmentPrefix + hour.Format(hourLayout) + "-"
+	names, err := s.files(prefix)
+	if err != nil {
+		return err
+	}
+	seq := 0
+	for _, name := range names {
+		if n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, prefix), fileExt)); err == nil {
+			seq = max(seq, n+1)
+		}
+	}
+	name := fmt.Sprintf("%s%03d%s", prefix, seq, fileExt)
+	f, err := os.OpenFile(filepath.Join(s.opts.Dir, name), os.O_APPEND|os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	s.segment, s.segmentName, s.segmentHour, s.segmentSize = f, name, hour, 0
+	return nil
+}
+
+// segments returns the segments of the store, oldest first.
+func (s *Store) segments() ([]segmentFile, error) {
+	names, err := s.files(segmentPrefix)
+	if err != nil {
+		return nil, err
+	}
+	segments := make([]segmentFile, 0, len(names))
+	for _, name := range names {
+		stamp, _, _ := strings.Cut(strings.TrimPrefix(name, segmentPrefix), "-")
+		hour, err := time.Parse(hourLayout, stamp)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, segmentFile{name: name, hour: hour})
+	}
+	return segments, nil
+}
+
+// files returns the names of the files of the store with the prefix, sorted.
+func (s *Store) files(prefix string) ([]string, error) {
+	entries, err := os.ReadDir(s.opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), prefix) && strings.HasSuffix(e.Name(), fileExt) {
+			names = append(names, e.Name())
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// writeRollups adds the rollups to the file of their month, sorted by hour then target.
+// Each file is replaced at once: A crash leaves it as it was, or with every new rollup.
+func (s *Store) writeRollups(rollups map[string]*Rollup) error {
+	sorted := make([]*Rollup, 0, len(rollups))
+	for _, r := range rollups {
+		sorted = append(sorted, r)
+	}
+	slices.SortFunc(sorted, func(x, y *Rollup) int {
+		if c := x.Hour.Compare(y.Hour); c != 0 {
+			return c
+		}
+		return strings.Compare(x.Target, y.Target)
+	})
+	byMonth := map[string]*bytes.Buffer{}
+	var months []string
+	for _, r := range sorted {
+		month := r.Hour.Format(monthLayout)
+		if byMonth[month] == nil {
+			byMonth[month] = &bytes.Buffer{}
+			months = append(months, month)
+		}
+		line, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		byMonth[month].Write(append(line, '\n'))
+	}
+	for _, month := range months {
+		path := filepath.Join(s.opts.Dir, rollupPrefix+month+fileExt)
+		data, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		// A last line cut short (by an older version of the store) is dropped
+		data = append(data[:bytes.LastIndexByte(data, '\n')+1], byMonth[month].Bytes()...)
+		if err := writeFileAtomic(path, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadCompaction reads what the last compaction left. Nothing before the first one.
+func (s *Store) loadCompaction() (compaction, error) {
+	state := compaction{Open: map[string]time.Time{}}
+	data, err := os.ReadFile(filepath.Join(s.opts.Dir, compactionFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("%w: %s: %v", ErrCorrupt, compactionFile, err)
+	}
+	if state.Open == nil {
+		state.Open = map[string]time.Time{}
+	}
+	return state, nil
+}
+
+// saveCompaction replaces what the last compaction left, at once.
+func (s *Store) saveCompaction(state compaction) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(s.opts.Dir, compactionFile), data)
+}
+
+// observe follows the incident of the target of a record.
+// It tells if an incident started with it, or ended with it (and how long it lasted).
+func (t *incidentTracker) observe(r Record) (started, ended bool, lasted time.Duration) {
+	start, open := t.open[r.Target]
+	switch {
+	case (r.State == StateDown || r.State == StateFlapping) && !open:
+		t.open[r.Target] = r.At
+		return true, false, 0
+	case r.State == StateUp && open:
+		delete(t.open, r.Target)
+		return false, true, r.At.Sub(start)
+	}
+	return false, false, 0
+}
+
+// Helper Functions
+// ****************
+
+// rollupAdd counts a record in the rollup of its target and hour.
+func rollupAdd(rollups map[string]*Rollup, r Record, tracker *incidentTracker) {
+	hour := r.At.UTC().Truncate(time.Hour)
+	key := r.Target + " " + hour.Format(hourLayout)
+	ru, found := rollups[key]
+	if !found {
+		ru = &Rollup{Target: r.Target, Hour: hour, Buckets: make([]int, len(LatencyBuckets)+1)}
+		rollups[key] = ru
+	}
+	ru.URL = r.URL
+	ru.Checks++
+	if r.Verdict != VerdictDown {
+		ru.UpChecks++
+	}
+	ru.LatencySumMs += r.LatencyMs
+	ru.LatencyMaxMs = max(ru.LatencyMaxMs, r.LatencyMs)
+	ru.Buckets[bucketOf(r.LatencyMs)]++
+	started, ended, lasted := tracker.observe(r)
+	if started {
+		ru.Incidents++
+	}
+	if ended {
+		ru.Repaired++
+		ru.RepairSeconds += lasted.Seconds()
+	}
+}
+
+// bucketOf returns the latency bucket of a latency in milliseconds.
+func bucketOf(ms float64) int {
+	bucket, _ := slices.BinarySearch(LatencyBuckets, ms)
+	return bucket
+}
+
+// writeFileAtomic replaces the file at path with data at once: Through a temporary file, renamed over it.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// readLines decodes a file of one JSON value per line, and calls fn with each.
+// A last line without its newline is a write cut short (a crash, or a write in progress): It is skipped.
+func readLines[T any](path string, fn func(T) error) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// Compacted or deleted since listed
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	reader := bufio.NewReader(f)
+	for n := 1; ; n++ {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var v T
+		if err := json.Unmarshal(line, &v); err != nil {
+			return fmt.Errorf("%w: %s line %d: %v", ErrCorrupt, filepath.Base(path), n, err)
+		}
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+}
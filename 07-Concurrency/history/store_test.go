@@ -0,0 +1,291 @@
+/**
+ * @file: Unit tests for the history store
+ */
+
+// Package
+// *******
+package history
+
+// Imports
+// *******
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Test Helpers
+// ************
+
+// Start of the records of the tests.
+var t0 = time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+// newRecord returns a record of the target at t0+after.
+func newRecord(target string, after time.Duration, verdict, state string, latencyMs float64) Record {
+	return Record{Target: target, URL: "https://" + target, At: t0.Add(after), LatencyMs: latencyMs, Verdict: verdict, State: state}
+}
+
+// appendAll appends the records to the store, and fails the test on the first error.
+func appendAll(t *testing.T, s *Store, records []Record) {
+	t.Helper()
+	for _, r := range records {
+		if err := s.Append(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// scanAll returns the records of the store in [from, to).
+func scanAll(t *testing.T, s *Store, from, to time.Time) []Record {
+	t.Helper()
+	var records []Record
+	if err := s.Scan(from, to, func(r Record) error { records = append(records, r); return nil }); err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+// storeFiles returns the names of the files in the directory of the store.
+func storeFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+// Test Cases for Store
+// ********************
+//   - Records should go to one segment per hour, or more once a segment is full
+//   - Scan should return the records of a time range, and skip a line cut short
+//   - A store opened again should not write to the segments of the last run
+//   - Compaction should roll the old segments up, and retention should delete the old rollups
+//   - A compaction cut short should not roll a segment up twice, nor leave a rollup line cut short
+
+func Test_Store(t *testing.T) {
+	// TEST CASE 1: One segment per hour
+	// ---------------------------------
+	dir := t.TempDir()
+	s, err := Open(DefaultOptions(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []Record
+	for i := range 6 {
+		records = append(records, newRecord("a", time.Duration(i)*20*time.Minute, VerdictUp, StateUp, 10))
+	}
+	appendAll(t, s, records)
+	// A late record of the first hour stays in the segment of the second hour
+	appendAll(t, s, []Record{newRecord("a", 59*time.Minute, VerdictUp, StateUp, 10)})
+	want := []string{"segment-20260901T00-000.jsonl", "segment-20260901T01-000.jsonl"}
+	if files := storeFiles(t, dir); !slices.Equal(files, want) {
+		t.Errorf("Test Case 1: Expected the segments %v. Got %v", want, files)
+	}
+
+	// TEST CASE 2: Scan a time range
+	// ------------------------------
+	got := scanAll(t, s, t0.Add(20*time.Minute), t0.Add(80*time.Minute))
+	if len(got) != 4 || !got[0].At.Equal(t0.Add(20*time.Minute)) || got[0] != records[1] {
+		t.Errorf("Test Case 2: Expected the 4 records from 00:20 to 01:00. Got %v", got)
+	}
+
+	// TEST CASE 3: A full segment is closed within its hour
+	// -----------------------------------------------------
+	opts := DefaultOptions(t.TempDir())
+	opts.MaxSegmentBytes = 300
+	small, err := Open(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appendAll(t, small, records[:3])
+	if files := storeFiles(t, opts.Dir); len(files) != 2 || files[1] != "segment-20260901T00-001.jsonl" {
+		t.Errorf("Test Case 3: Expected 2 segments in the first hour. Got %v", files)
+	}
+	if got := scanAll(t, small, t0, t0.Add(time.Hour)); len(got) != 3 {
+		t.Errorf("Test Case 3: Expected the 3 records back. Got %d", len(got))
+	}
+
+	// TEST CASE 4: A line cut short is skipped, a corrupt line is an error
+	// --------------------------------------------------------------------
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	last := filepath.Join(dir, want[1])
+	f, err := os.OpenFile(last, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"target":"a","at":"2026-09-01T01:5`)
+	f.Close()
+	if got := scanAll(t, s, t0, t0.Add(2*time.Hour)); len(got) != 7 {
+		t.Errorf("Test Case 4: Expected the 7 whole records. Got %d", len(got))
+	}
+	os.WriteFile(filepath.Join(dir, "segment-20260901T02-000.jsonl"), []byte("not json\n"), 0o644)
+	err = s.Scan(t0, t0.Add(3*time.Hour), func(Record) error { return nil })
+	if !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Test Case 4: Expected ErrCorrupt. Got %v", err)
+	}
+	os.Remove(filepath.Join(dir, "segment-20260901T02-000.jsonl"))
+
+	// TEST CASE 5: A store opened again starts a new segment
+	// ------------------------------------------------------
+	again, err := Open(DefaultOptions(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	appendAll(t, again, []Record{newRecord("a", 90*time.Minute, VerdictUp, StateUp, 10)})
+	again.Close()
+	if files := storeFiles(t, dir); !slices.Contains(files, "segment-20260901T01-001.jsonl") {
+		t.Errorf("Test Case 5: Expected a new segment for the second run. Got %v", files)
+	}
+	if got := scanAll(t, again, t0, t0.Add(2*time.Hour)); len(got) != 8 {
+		t.Errorf("Test Case 5: Expected the 8 records of both runs. Got %d", len(got))
+	}
+}
+
+func Test_StoreCompact(t *testing.T) {
+	// Every 10 minutes for 4 hours: Down from 01:00 to 01:30, and from 02:50 to 03:20
+	dir := t.TempDir()
+	s, err := Open(DefaultOptions(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	appendAll(t, s, testRecords())
+
+	// TEST CASE 1: The old segments are rolled up, not the one being written
+	// ----------------------------------------------------------------------
+	stats, err := s.Compact(t0.Add(3*time.Hour + DefaultRawRetention))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Segments != 3 || stats.Records != 18 || stats.Rollups != 3 || !stats.CompactedBefore.Equal(t0.Add(3*time.Hour)) {
+		t.Errorf("Test Case 1: Expected 3 segments and 18 records rolled up into 3 rollups, before 03:00. Got %+v", stats)
+	}
+	want := []string{"compaction.json", "rollup-202609.jsonl", "segment-20260901T03-000.jsonl"}
+	if files := storeFiles(t, dir); !slices.Equal(files, want) {
+		t.Errorf("Test Case 1: Expected the files %v. Got %v", want, files)
+	}
+
+	// TEST CASE 2: The rollups sum up their hour
+	// ------------------------------------------
+	var rollups []Rollup
+	s.ScanRollups(t0, t0.Add(24*time.Hour), func(r Rollup) error { rollups = append(rollups, r); return nil })
+	if len(rollups) != 3 {
+		t.Fatalf("Test Case 2: Expected 3 rollups. Got %d", len(rollups))
+	}
+	second, third := rollups[1], rollups[2]
+	if second.Checks != 6 || second.UpChecks != 3 || second.Incidents != 1 || second.Repaired != 1 || second.RepairSeconds != 1800 {
+		t.Errorf("Test Case 2: Expected 6 checks, 3 up, 1 incident repaired in 30m in the second hour. Got %+v", second)
+	}
+	if second.LatencySumMs != 70+80+90+100+110+120 || second.LatencyMaxMs != 120 || second.Buckets[4] != 4 || second.Buckets[5] != 2 {
+		t.Errorf("Test Case 2: Expected the latencies of the second hour in the rollup. Got %+v", second)
+	}
+	if third.Incidents != 1 || third.Repaired != 0 {
+		t.Errorf("Test Case 2: Expected an incident still open at the end of the third hour. Got %+v", third)
+	}
+
+	// TEST CASE 3: Nothing left to roll up
+	// ------------------------------------
+	if stats, err := s.Compact(t0.Add(3*time.Hour + DefaultRawRetention)); err != nil || stats.Segments != 0 {
+		t.Errorf("Test Case 3: Expected nothing to compact. Got %+v (%v)", stats, err)
+	}
+
+	// TEST CASE 4: Old rollups are deleted
+	// ------------------------------------
+	s.Close()
+	later, _ := Open(DefaultOptions(dir))
+	stats, err = later.Compact(t0.AddDate(2, 0, 0))
+	if err != nil || stats.Segments != 1 || stats.RemovedRollups != 1 {
+		t.Errorf("Test Case 4: Expected the last segment compacted, and the rollups deleted. Got %+v (%v)", stats, err)
+	}
+	if files := storeFiles(t, dir); !slices.Equal(files, []string{"compaction.json"}) {
+		t.Errorf("Test Case 4: Expected only compaction.json left. Got %v", files)
+	}
+	later.Close()
+
+	// TEST CASE 5: Cut short after saving compaction.json
+	// ---------------------------------------------------
+	dir = t.TempDir()
+	cut, _ := Open(DefaultOptions(dir))
+	defer cut.Close()
+	appendAll(t, cut, testRecords())
+	first := filepath.Join(dir, "segment-20260901T00-000.jsonl")
+	left, err := os.ReadFile(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cut.Compact(t0.Add(3*time.Hour + DefaultRawRetention)); err != nil {
+		t.Fatal(err)
+	}
+	// The segment was not deleted yet, and the rollup file was cut short by an older version of the store
+	os.WriteFile(first, left, 0o644)
+	f, err := os.OpenFile(filepath.Join(dir, "rollup-202609.jsonl"), os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"target":"a","hour":"2026-09-01T0`)
+	f.Close()
+	stats, err = cut.Compact(t0.Add(4*time.Hour + DefaultRawRetention))
+	if err != nil || stats.Segments != 0 || slices.Contains(storeFiles(t, dir), filepath.Base(first)) {
+		t.Errorf("Test Case 5: Expected the segment left behind deleted, not rolled up again. Got %+v (%v)", stats, err)
+	}
+	checks := 0
+	err = cut.ScanRollups(t0, t0.Add(24*time.Hour), func(r Rollup) error { checks += r.Checks; return nil })
+	if err != nil || checks != 18 {
+		t.Errorf("Test Case 5: Expected the 18 checks rolled up once. Got %d (%v)", checks, err)
+	}
+
+	// TEST CASE 6: Several segments in the same hour
+	// ----------------------------------------------
+	dir = t.TempDir()
+	opts := DefaultOptions(dir)
+	opts.MaxSegmentBytes = 200
+	small, _ := Open(opts)
+	defer small.Close()
+	var records []Record
+	for i := range 6 {
+		records = append(records, newRecord("a", time.Duration(i)*10*time.Minute, VerdictUp, StateUp, 10))
+	}
+	appendAll(t, small, append(records, newRecord("a", 70*time.Minute, VerdictUp, StateUp, 10)))
+	if files := storeFiles(t, dir); !slices.Contains(files, "segment-20260901T00-005.jsonl") {
+		t.Fatalf("Test Case 6: Expected 6 segments in the first hour. Got %v", files)
+	}
+	// Half of the hour is past the cutoff: None of its segments is rolled up
+	stats, err = small.Compact(t0.Add(30*time.Minute + DefaultRawRetention))
+	if err != nil || stats.Segments != 0 || stats.Records != 0 {
+		t.Errorf("Test Case 6: Expected nothing rolled up before the whole hour is past the cutoff. Got %+v (%v)", stats, err)
+	}
+	stats, err = small.Compact(t0.Add(time.Hour + DefaultRawRetention))
+	if err != nil || stats.Segments != 6 || stats.Records != 6 || !stats.CompactedBefore.Equal(t0.Add(time.Hour)) {
+		t.Errorf("Test Case 6: Expected the 6 segments and 6 records of the hour rolled up, before 01:00. Got %+v (%v)", stats, err)
+	}
+	// Every record is either in the rollups or in the raw records after the compaction
+	checks = 0
+	err = small.ScanRollups(t0, stats.CompactedBefore, func(r Rollup) error { checks += r.Checks; return nil })
+	if raw := scanAll(t, small, stats.CompactedBefore, t0.Add(2*time.Hour)); err != nil || checks != 6 || len(raw) != 1 {
+		t.Errorf("Test Case 6: Expected 6 checks rolled up and 1 raw record. Got %d and %d (%v)", checks, len(raw), err)
+	}
+}
+
+// testRecords returns the checks of target a every 10 minutes for 4 hours, 10ms slower each time.
+// It is down from 01:00 to 01:30, and from 02:50 to 03:20.
+func testRecords() []Record {
+	var records []Record
+	for i := range 24 {
+		verdict, state := VerdictUp, StateUp
+		if (i >= 6 && i <= 8) || (i >= 17 && i <= 19) {
+			verdict, state = VerdictDown, StateDown
+		}
+		records = append(records, newRecord("a", time.Duration(i)*10*time.Minute, verdict, state, float64(10*(i+1))))
+	}
+	return records
+}
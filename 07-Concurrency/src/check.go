@@ -0,0 +1,277 @@
+/**
+ * @file: Describes a health check: What a URL answered, and whether that counts as up, degraded or down.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Constants
+// *********
+
+// Verdict of a check.
+type verdict string
+
+const (
+	// Expected status, within the latency threshold
+	verdictUp verdict = "up"
+	// Expected status, but slower than the latency threshold
+	verdictDegraded verdict = "degraded"
+	// No response, or an unexpected status
+	verdictDown verdict = "down"
+)
+
+// Class of the error that prevented a response.
+type errorClass string
+
+const (
+	// No error: The server responded
+	errorNone errorClass = ""
+	// The host name could not be resolved
+	errorDNS errorClass = "dns"
+	// The connection was refused or could not be established
+	errorConnect errorClass = "connect"
+	// The TLS handshake or the certificate failed
+	errorTLS errorClass = "tls"
+	// No response in time
+	errorTimeout errorClass = "timeout"
+	// The monitor was stopped during the check
+	errorCanceled errorClass = "canceled"
+	// Any other error while sending the request or reading the response
+	errorHTTP errorClass = "http"
+)
+
+// Defaults of a check rule.
+const (
+	defaultStatusMin       = 200
+	defaultStatusMax       = 399
+	defaultDegradedLatency = time.Second
+	defaultTimeout         = 10 * time.Second
+	// Largest part of a body read by a check, in bytes: The rest is left unread
+	defaultMaxBody = 1 << 20
+)
+
+// Type Declaration
+// ****************
+
+// A checkRule tells what counts as up for a URL: Its expected status range and its latency threshold.
+type checkRule struct {
+	statusMin       int
+	statusMax       int
+	degradedLatency time.Duration
+}
+
+// A target is a URL to check, with its rule, the time allowed for each check and the pause between two checks.
+type target struct {
+	// Unique name of the target: Its URL by default
+	id      string
+	url     string
+	method  string
+	headers http.Header
+	rule    checkRule
+	timeout time.Duration
+	// Bytes of the body read at most
+	maxBody int64
+	// Zero: The interval of the monitor
+	interval time.Duration
+	// Free labels, such as a team or an environment
+	tags []string
+	// When to try a failed check again, and when to alert
+	retry    retryPolicy
+	alerting alertPolicy
+	// An HTTP request, or a TLS handshake judged by the TLS policy
+	mode checkMode
+	tls  tlsPolicy
+}
+
+// A checkResult is what a check found.
+type checkResult struct {
+	target     target
+	checkedAt  time.Time
+	statusCode int
+	latency    time.Duration
+	size       int64
+	errClass   errorClass
+	err        error
+	verdict    verdict
+	// Checks made to get this result: More than 1 after retries
+	attempts int
+	// What a TLS check found: nil for an HTTP check, or a failed handshake
+	tls *tlsInfo
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newCheckRule()
+// Initializes the default rule: Any 2xx or 3xx status within a second is up.
+func newCheckRule() checkRule {
+	return checkRule{statusMin: defaultStatusMin, statusMax: defaultStatusMax, degradedLatency: defaultDegradedLatency}
+}
+
+// newTarget()
+// Initializes a GET target named after its URL, with the default rule, timeout, body cap, retries, alerts and TLS policy.
+func newTarget(url string) target {
+	return target{
+		id:       url,
+		url:      url,
+		method:   http.MethodGet,
+		rule:     newCheckRule(),
+		timeout:  defaultTimeout,
+		maxBody:  defaultMaxBody,
+		retry:    newRetryPolicy(),
+		alerting: newAlertPolicy(),
+		mode:     modeHTTP,
+		tls:      newTLSPolicy(),
+	}
+}
+
+// newHttpClient()
+// Initializes the HTTP client shared by every check. Each step of a request has its own timeout,
+// and the context of each check bounds the whole request.
+func newHttpClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: 5 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = 5 * time.Second
+	// Each worker can keep its connection to a host open between checks
+	transport.MaxIdleConnsPerHost = defaultWorkers
+	return &http.Client{Transport: transport}
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// checkRule.judge()
+// Receiver Function that gives the verdict of a result according to the rule.
+func (rule checkRule) judge(res checkResult) verdict {
+	switch {
+	case res.err != nil:
+		return verdictDown
+	case res.statusCode < rule.statusMin || res.statusCode > rule.statusMax:
+		return verdictDown
+	case rule.degradedLatency > 0 && res.latency > rule.degradedLatency:
+		return verdictDegraded
+	}
+	return verdictUp
+}
+
+// checkResult.toString()
+// Receiver Function to describe the result on one line.
+func (res checkResult) toString() string {
+	latency := res.latency.Round(time.Millisecond)
+	attempts := ""
+	if res.attempts > 1 {
+		attempts = fmt.Sprintf(" (attempt %d)", res.attempts)
+	}
+	if res.err != nil {
+		return fmt.Sprintf("%s is %s%s: %s error after %v (%v)", res.target.url, res.verdict, attempts, res.errClass, latency, res.err)
+	}
+	if res.tls != nil {
+		return fmt.Sprintf("%s is %s%s: %s, in %v", res.target.url, res.verdict, attempts, res.tls.toString(), latency)
+	}
+	return fmt.Sprintf("%s is %s%s: status %d, %d bytes in %v", res.target.url, res.verdict, attempts, res.statusCode, res.size, latency)
+}
+
+// Helper Functions
+// ****************
+
+// checkTarget()
+// Checks a target the way its mode says: An HTTP request, or a TLS handshake.
+func checkTarget(ctx context.Context, client *http.Client, t target) checkResult {
+	if t.mode == modeTLS {
+		return checkCertificate(ctx, t)
+	}
+	return checkUrl(ctx, client, t)
+}
+
+// checkUrl()
+// Checks a URL with the target's method and headers, and judges the result with the target's rule.
+// The check gives up when ctx is done, or after the target's timeout.
+func checkUrl(ctx context.Context, client *http.Client, t target) checkResult {
+	res := checkResult{target: t, checkedAt: time.Now(), attempts: 1}
+	if t.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+	}
+
+	// Test the url with the target's request
+	method := t.method
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequestWithContext(ctx, method, t.url, nil)
+	var resp *http.Response
+	if err == nil {
+		for key, values := range t.headers {
+			req.Header[key] = values
+		}
+		// The Host header is a field of the request, not a header
+		if host := t.headers.Get("Host"); host != "" {
+			req.Host = host
+		}
+		resp, err = client.Do(req)
+	}
+	if err == nil {
+		// Read the body up to the cap of the target: Its size and the time to read it are part of the check.
+		// A huge or endless body does not hold a worker until the timeout
+		res.statusCode = resp.StatusCode
+		res.size, err = io.Copy(io.Discard, io.LimitReader(resp.Body, t.maxBody))
+		resp.Body.Close()
+	}
+	res.latency = time.Since(res.checkedAt)
+
+	// Error Handling
+	if err != nil {
+		res.err = err
+		res.errClass = classifyError(err)
+	}
+	res.verdict = t.rule.judge(res)
+	return res
+}
+
+// classifyError()
+// Tells which step of a request failed: DNS, connection, TLS, timeout, or anything else.
+func classifyError(err error) errorClass {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	var opErr *net.OpError
+	var recordErr tls.RecordHeaderError
+	var certErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+
+	switch {
+	case err == nil:
+		return errorNone
+	case errors.Is(err, context.Canceled):
+		return errorCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return errorTimeout
+	case errors.As(err, &dnsErr):
+		return errorDNS
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return errorTimeout
+	case errors.As(err, &certErr), errors.As(err, &authorityErr), errors.As(err, &hostnameErr),
+		errors.As(err, &invalidErr), errors.As(err, &recordErr):
+		return errorTLS
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		return errorConnect
+	}
+	return errorHTTP
+}
@@ -0,0 +1,138 @@
+/**
+ * @file: Unit tests for health checks, against local httptest servers
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Test Helpers
+// ************
+
+// newStatusServer starts a local server answering with the status after the delay.
+// A client that gives up earlier releases the handler, so that the server can be closed.
+func newStatusServer(t *testing.T, status int, delay time.Duration) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		w.WriteHeader(status)
+		w.Write([]byte("hello"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// Test Cases for checkUrl()
+// *************************
+//   - A 200 within the threshold should be up, with its status, size and latency
+//   - A 500 or a 404 should be down, unless the rule expects it
+//   - A slow answer should be degraded
+//   - An endless body should be read up to the cap of the target only
+
+func Test_checkUrl(t *testing.T) {
+	// TEST CASE 1: Up
+	// ---------------
+	server := newStatusServer(t, http.StatusOK, 0)
+	res := checkUrl(context.Background(), http.DefaultClient, newTarget(server.URL))
+	if res.verdict != verdictUp || res.statusCode != 200 || res.size != 5 || res.latency <= 0 {
+		t.Errorf("Test Case 1: Expected up with status 200 and 5 bytes. Got %s", res.toString())
+	}
+
+	// TEST CASE 2: Unexpected status
+	// ------------------------------
+	for _, status := range []int{http.StatusInternalServerError, http.StatusNotFound} {
+		server := newStatusServer(t, status, 0)
+		if res := checkUrl(context.Background(), http.DefaultClient, newTarget(server.URL)); res.verdict != verdictDown || res.errClass != errorNone {
+			t.Errorf("Test Case 2: Expected down for status %d. Got %s", status, res.toString())
+		}
+	}
+	server = newStatusServer(t, http.StatusNotFound, 0)
+	expect404 := newTarget(server.URL)
+	expect404.rule.statusMin, expect404.rule.statusMax = 404, 404
+	if res := checkUrl(context.Background(), http.DefaultClient, expect404); res.verdict != verdictUp {
+		t.Errorf("Test Case 2: Expected up when the rule expects a 404. Got %s", res.toString())
+	}
+
+	// TEST CASE 3: Degraded
+	// ---------------------
+	server = newStatusServer(t, http.StatusOK, 50*time.Millisecond)
+	slow := newTarget(server.URL)
+	slow.rule.degradedLatency = 10 * time.Millisecond
+	if res := checkUrl(context.Background(), http.DefaultClient, slow); res.verdict != verdictDegraded {
+		t.Errorf("Test Case 3: Expected degraded. Got %s", res.toString())
+	}
+
+	// TEST CASE 4: Endless body
+	// -------------------------
+	endless := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chunk := make([]byte, 4096)
+		for r.Context().Err() == nil {
+			if _, err := w.Write(chunk); err != nil {
+				return
+			}
+		}
+	}))
+	defer endless.Close()
+	capped := newTarget(endless.URL)
+	capped.maxBody = 10_000
+	capped.timeout = 5 * time.Second
+	if res := checkUrl(context.Background(), http.DefaultClient, capped); res.verdict != verdictUp || res.size != 10_000 || res.latency > time.Second {
+		t.Errorf("Test Case 4: Expected up after 10000 bytes, well before the timeout. Got %s", res.toString())
+	}
+}
+
+// Test Cases for classifyError()
+// ******************************
+//   - Refused connections, TLS failures and timeouts should be told apart
+
+func Test_classifyError(t *testing.T) {
+	// TEST CASE 1: Connection refused
+	// -------------------------------
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	closedURL := "http://" + listener.Addr().String()
+	listener.Close()
+	if res := checkUrl(context.Background(), http.DefaultClient, newTarget(closedURL)); res.errClass != errorConnect || res.verdict != verdictDown {
+		t.Errorf("Test Case 1: Expected a connect error. Got %s", res.toString())
+	}
+
+	// TEST CASE 2: Untrusted certificate
+	// ----------------------------------
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer tlsServer.Close()
+	if res := checkUrl(context.Background(), http.DefaultClient, newTarget(tlsServer.URL)); res.errClass != errorTLS {
+		t.Errorf("Test Case 2: Expected a TLS error. Got %s", res.toString())
+	}
+
+	// TEST CASE 3: Timeout
+	// --------------------
+	server := newStatusServer(t, http.StatusOK, 200*time.Millisecond)
+	client := &http.Client{Timeout: 20 * time.Millisecond}
+	if res := checkUrl(context.Background(), client, newTarget(server.URL)); res.errClass != errorTimeout {
+		t.Errorf("Test Case 3: Expected a timeout. Got %s", res.toString())
+	}
+
+	// TEST CASE 4: DNS
+	// ----------------
+	if class := classifyError(&net.DNSError{Err: "no such host", Name: "example.invalid", IsNotFound: true}); class != errorDNS {
+		t.Errorf("Test Case 4: Expected a DNS error. Got %q", class)
+	}
+	if class := classifyError(errors.New("unexpected EOF")); class != errorHTTP {
+		t.Errorf("Test Case 4: Expected an HTTP error. Got %q", class)
+	}
+}
@@ -0,0 +1,577 @@
+/**
+ * @file: Describes the config file of the monitor: The targets to check, in JSON.
+ *
+ * {
+ *   "defaults": {"interval": "5s", "timeout": "3s"},
+ *   "targets": [
+ *     {"id": "go", "url": "https://go.dev", "degraded_latency": "500ms", "tags": ["docs"]},
+ *     {"url": "https://example.com/health", "method": "HEAD", "headers": {"Authorization": "Bearer ..."},
+ *      "expected_status": {"min": 200, "max": 299}, "max_body_bytes": 65536,
+ *      "retries": {"attempts": 3, "backoff": "500ms", "max_backoff": "10s"},
+ *      "alerting": {"down_after": 2, "up_after": 3, "flap_window": "30m", "flap_changes": 6}},
+ *     {"id": "cert", "url": "https://example.com", "mode": "tls",
+ *      "tls": {"warn_days": [30, 14, 7], "min_version": "1.2", "ca_file": "ca.pem"}}
+ *   ]
+ * }
+ *
+ * Every field of a target but "id" and "url" can also go in "defaults". The watcher polls the file,
+ * and hands every valid new version to the monitor. An invalid version is reported and ignored.
+ *
+ * The file can also list "notifiers": Where alerts go. They are read once, at start.
+ *
+ *   "notifiers": [
+ *     {"name": "ops", "type": "webhook", "url": "https://hooks.example.com/...", "tags": ["prod"], "on": ["down", "up"],
+ *      "dedup": "10m", "rate_limit": {"count": 10, "per": "1h"}, "subject": "{{.ID}} is {{.To}}"},
+ *     {"name": "mail", "type": "smtp", "addr": "smtp.example.com:587", "from": "monitor@example.com",
+ *      "to": ["oncall@example.com"], "username": "monitor", "password": "${SMTP_PASSWORD}"},
+ *     {"name": "hook", "type": "command", "command": ["./on-alert.sh", "--page"]},
+ *     {"name": "log", "type": "file", "path": "alerts.log"},
+ *     {"name": "syslog", "type": "syslog", "network": "udp", "address": "localhost:514", "tag": "monitor"}
+ *   ]
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Constants
+// *********
+
+// Default pause between two looks at the config file.
+const defaultConfigPoll = time.Second
+
+// Errors
+// ******
+
+// errInvalidConfig wraps every validation error of a config file.
+var errInvalidConfig = errors.New("invalid config")
+
+// Type Declaration
+// ****************
+
+// A duration is a time.Duration written as text in JSON, such as "1m30s".
+type duration time.Duration
+
+// A statusRange is the range of expected status codes, bounds included.
+type statusRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// A retryConfig is a retry policy as written in the config file. Missing fields keep their default.
+type retryConfig struct {
+	Attempts   *int      `json:"attempts"`
+	Backoff    *duration `json:"backoff"`
+	MaxBackoff *duration `json:"max_backoff"`
+}
+
+// An alertConfig is an alert policy as written in the config file. Missing fields keep their default.
+type alertConfig struct {
+	DownAfter   *int      `json:"down_after"`
+	UpAfter     *int      `json:"up_after"`
+	FlapWindow  *duration `json:"flap_window"`
+	FlapChanges *int      `json:"flap_changes"`
+}
+
+// A tlsConfig is a TLS policy as written in the config file. Missing fields keep their default.
+type tlsConfig struct {
+	WarnDays   []int  `json:"warn_days"`
+	MinVersion string `json:"min_version"`
+	CAFile     string `json:"ca_file"`
+}
+
+// A targetConfig is a target as written in the config file. Missing fields keep their default.
+type targetConfig struct {
+	ID              string            `json:"id"`
+	URL             string            `json:"url"`
+	Method          string            `json:"method"`
+	Headers         map[string]string `json:"headers"`
+	Interval        *duration         `json:"interval"`
+	Timeout         *duration         `json:"timeout"`
+	DegradedLatency *duration         `json:"degraded_latency"`
+	ExpectedStatus  *statusRange      `json:"expected_status"`
+	MaxBodyBytes    *int64            `json:"max_body_bytes"`
+	Tags            []string          `json:"tags"`
+	Retries         *retryConfig      `json:"retries"`
+	Alerting        *alertConfig      `json:"alerting"`
+	Mode            string            `json:"mode"`
+	TLS             *tlsConfig        `json:"tls"`
+}
+
+// A rateConfig is a rate limit as written in the config file: At most count messages per period.
+type rateConfig struct {
+	Count int      `json:"count"`
+	Per   duration `json:"per"`
+}
+
+// A notifierConfig is a channel of alerts as written in the config file.
+// Its type tells which of the fields in the middle it needs.
+type notifierConfig struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+
+	// webhook
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	// smtp
+	Addr     string   `json:"addr"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	// command
+	Command []string `json:"command"`
+	// file
+	Path string `json:"path"`
+	// syslog
+	Network string `json:"network"`
+	Address string `json:"address"`
+	Tag     string `json:"tag"`
+
+	// Routing, templates, deduplication and rate limit
+	Targets   []string    `json:"targets"`
+	Tags      []string    `json:"tags"`
+	On        []string    `json:"on"`
+	Subject   string      `json:"subject"`
+	Template  string      `json:"template"`
+	Dedup     *duration   `json:"dedup"`
+	RateLimit *rateConfig `json:"rate_limit"`
+}
+
+// A monitorConfig is the content of the config file.
+type monitorConfig struct {
+	Defaults  targetConfig     `json:"defaults"`
+	Targets   []targetConfig   `json:"targets"`
+	Notifiers []notifierConfig `json:"notifiers"`
+}
+
+// A configWatcher polls the config file, and sends each valid new version of its targets.
+type configWatcher struct {
+	path string
+	// Template of every target: Its fields apply when neither the target nor the defaults set them
+	base  target
+	every time.Duration
+	// Last version seen of the file
+	modTime time.Time
+	size    int64
+	// Called with each error, from the goroutine of configWatcher.watch()
+	report func(err error)
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newConfigWatcher()
+// Initializes a watcher of the config file, and loads its targets.
+// The watcher starts from this version of the file: Only later changes are sent.
+func newConfigWatcher(path string, base target) (*configWatcher, []target, error) {
+	w := &configWatcher{
+		path:   path,
+		base:   base,
+		every:  defaultConfigPoll,
+		report: func(err error) { fmt.Fprintln(os.Stderr, err) },
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	w.modTime, w.size = info.ModTime(), info.Size()
+	targets, err := loadConfig(path, base)
+	if err != nil {
+		return nil, nil, err
+	}
+	return w, targets, nil
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// duration.UnmarshalJSON()
+// Receiver Function that parses a duration written as text. It implements json.Unmarshaler.
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration should be text such as \"30s\": %s", data)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = duration(parsed)
+	return nil
+}
+
+// targetConfig.applyTo()
+// Receiver Function that sets the fields of the target that the config sets.
+func (tc targetConfig) applyTo(t *target) {
+	if tc.ID != "" {
+		t.id = tc.ID
+	}
+	if tc.URL != "" {
+		t.url = tc.URL
+	}
+	if tc.Method != "" {
+		t.method = strings.ToUpper(tc.Method)
+	}
+	if tc.Headers != nil {
+		// The headers of a target add up to the default ones
+		headers := t.headers.Clone()
+		if headers == nil {
+			headers = http.Header{}
+		}
+		for key, value := range tc.Headers {
+			headers.Set(key, value)
+		}
+		t.headers = headers
+	}
+	if tc.Interval != nil {
+		t.interval = time.Duration(*tc.Interval)
+	}
+	if tc.Timeout != nil {
+		t.timeout = time.Duration(*tc.Timeout)
+	}
+	if tc.DegradedLatency != nil {
+		t.rule.degradedLatency = time.Duration(*tc.DegradedLatency)
+	}
+	if tc.ExpectedStatus != nil {
+		t.rule.statusMin, t.rule.statusMax = tc.ExpectedStatus.Min, tc.ExpectedStatus.Max
+	}
+	setIf(&t.maxBody, tc.MaxBodyBytes)
+	if tc.Tags != nil {
+		t.tags = tc.Tags
+	}
+	if rc := tc.Retries; rc != nil {
+		setIf(&t.retry.attempts, rc.Attempts)
+		setIf(&t.retry.backoff, (*time.Duration)(rc.Backoff))
+		setIf(&t.retry.maxBackoff, (*time.Duration)(rc.MaxBackoff))
+	}
+	if ac := tc.Alerting; ac != nil {
+		setIf(&t.alerting.downAfter, ac.DownAfter)
+		setIf(&t.alerting.upAfter, ac.UpAfter)
+		setIf(&t.alerting.flapWindow, (*time.Duration)(ac.FlapWindow))
+		setIf(&t.alerting.flapChanges, ac.FlapChanges)
+	}
+	if tc.Mode != "" {
+		t.mode = checkMode(tc.Mode)
+	}
+	if lc := tc.TLS; lc != nil {
+		if lc.WarnDays != nil {
+			t.tls.warnDays = lc.WarnDays
+		}
+		if lc.MinVersion != "" {
+			t.tls.minVersion = lc.MinVersion
+		}
+		if lc.CAFile != "" {
+			t.tls.caFile = lc.CAFile
+		}
+	}
+}
+
+// notifierConfig.build()
+// Receiver Function that builds the channel of alerts, or tells what is wrong with its config.
+// Values of ${VARIABLES} in the password and the headers come from the environment.
+func (nc notifierConfig) build() (*notifyChannel, error) {
+	var n notifier
+	switch nc.Type {
+	case "webhook":
+		if u, err := url.Parse(nc.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("webhook url %q should be an http or https url", nc.URL)
+		}
+		headers := http.Header{}
+		for key, value := range nc.Headers {
+			headers.Set(key, os.ExpandEnv(value))
+		}
+		n = &webhookNotifier{url: nc.URL, headers: headers, client: newHttpClient()}
+	case "smtp":
+		if _, _, err := net.SplitHostPort(nc.Addr); err != nil || nc.From == "" || len(nc.To) == 0 {
+			return nil, fmt.Errorf("smtp needs an addr (host:port), a from and at least one to")
+		}
+		n = &smtpNotifier{addr: nc.Addr, from: nc.From, to: nc.To, username: nc.Username, password: os.ExpandEnv(nc.Password)}
+	case "command":
+		if len(nc.Command) == 0 || nc.Command[0] == "" {
+			return nil, fmt.Errorf("command needs a command to run")
+		}
+		n = &commandNotifier{command: nc.Command}
+	case "file":
+		if nc.Path == "" {
+			return nil, fmt.Errorf("file needs a path")
+		}
+		n = &fileNotifier{path: nc.Path}
+	case "syslog":
+		switch nc.Network {
+		case "udp", "tcp", "unix", "unixgram":
+		default:
+			return nil, fmt.Errorf("syslog network %q should be udp, tcp, unix or unixgram", nc.Network)
+		}
+		if nc.Address == "" {
+			return nil, fmt.Errorf("syslog needs an address")
+		}
+		n = &syslogNotifier{network: nc.Network, address: nc.Address, tag: nc.Tag}
+	default:
+		return nil, fmt.Errorf("unknown type %q: use webhook, smtp, command, file or syslog", nc.Type)
+	}
+
+	c := newNotifyChannel(nc.Name, n)
+	c.targets, c.tags = nc.Targets, nc.Tags
+	for _, on := range nc.On {
+		switch status := targetStatus(on); status {
+		case statusUp, statusDown, statusFlapping, statusWarning:
+			c.on = append(c.on, status)
+		default:
+			return nil, fmt.Errorf("on %q should be up, down, flapping or warning", on)
+		}
+	}
+	var err error
+	if nc.Subject != "" {
+		if c.subject, err = template.New("subject").Parse(nc.Subject); err != nil {
+			return nil, err
+		}
+	}
+	if nc.Template != "" {
+		if c.body, err = template.New("body").Parse(nc.Template); err != nil {
+			return nil, err
+		}
+	}
+	if nc.Dedup != nil {
+		c.dedup = time.Duration(*nc.Dedup)
+	}
+	if rc := nc.RateLimit; rc != nil {
+		if rc.Count < 1 || rc.Per <= 0 {
+			return nil, fmt.Errorf("rate_limit needs a count of at least 1 per a positive period")
+		}
+		c.rateCount, c.ratePer = rc.Count, time.Duration(rc.Per)
+	}
+	return c, nil
+}
+
+// configWatcher.watch()
+// Receiver Function that polls the config file until ctx is done, and sends each valid new version of its targets.
+// A change is seen from the modification time or the size of the file.
+func (w *configWatcher) watch(ctx context.Context) <-chan []target {
+	updates := make(chan []target)
+	go func() {
+		ticker := time.NewTicker(w.every)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			info, err := os.Stat(w.path)
+			if err != nil {
+				w.report(fmt.Errorf("config: keeping the current targets: %w", err))
+				continue
+			}
+			if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+				continue
+			}
+			// Report each bad version once: It is not read again until it changes
+			w.modTime, w.size = info.ModTime(), info.Size()
+			targets, err := loadConfig(w.path, w.base)
+			if err != nil {
+				w.report(fmt.Errorf("config: keeping the current targets: %w", err))
+				continue
+			}
+
+			select {
+			case updates <- targets:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return updates
+}
+
+// Helper Functions
+// ****************
+
+// loadConfig()
+// Loads the targets of a config file.
+func loadConfig(path string, base target) ([]target, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := parseConfig(data, base)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return targets, nil
+}
+
+// parseConfig()
+// Parses the targets of a config, and validates them all. Each target starts from base,
+// then takes the defaults of the config, then its own fields. Unknown fields are errors: They are likely typos.
+func parseConfig(data []byte, base target) ([]target, error) {
+	var config monitorConfig
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidConfig, err)
+	}
+	if config.Defaults.ID != "" || config.Defaults.URL != "" {
+		return nil, fmt.Errorf("%w: defaults cannot set an id or a url", errInvalidConfig)
+	}
+
+	var errs []error
+	targets := make([]target, 0, len(config.Targets))
+	seen := map[string]int{}
+	for i, tc := range config.Targets {
+		t := base
+		config.Defaults.applyTo(&t)
+		tc.applyTo(&t)
+		if tc.ID == "" {
+			t.id = t.url
+		}
+
+		if err := validateTarget(t); err != nil {
+			errs = append(errs, fmt.Errorf("target %d: %w", i+1, err))
+		}
+		if first, found := seen[t.id]; found {
+			errs = append(errs, fmt.Errorf("target %d: id %q already used by target %d", i+1, t.id, first))
+		}
+		seen[t.id] = i + 1
+		targets = append(targets, t)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%w: %w", errInvalidConfig, errors.Join(errs...))
+	}
+	return targets, nil
+}
+
+// loadNotifiers()
+// Loads the channels of alerts of a config file.
+func loadNotifiers(path string) ([]*notifyChannel, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	channels, err := parseNotifiers(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return channels, nil
+}
+
+// parseNotifiers()
+// Parses the channels of alerts of a config, and validates them all. Each needs a unique name.
+func parseNotifiers(data []byte) ([]*notifyChannel, error) {
+	var config monitorConfig
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidConfig, err)
+	}
+
+	var errs []error
+	var channels []*notifyChannel
+	seen := map[string]bool{}
+	for i, nc := range config.Notifiers {
+		switch {
+		case nc.Name == "":
+			errs = append(errs, fmt.Errorf("notifier %d: needs a name", i+1))
+		case seen[nc.Name]:
+			errs = append(errs, fmt.Errorf("notifier %d: name %q already used", i+1, nc.Name))
+		}
+		seen[nc.Name] = true
+		c, err := nc.build()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notifier %d (%s): %w", i+1, nc.Name, err))
+			continue
+		}
+		channels = append(channels, c)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%w: %w", errInvalidConfig, errors.Join(errs...))
+	}
+	return channels, nil
+}
+
+// validateTarget()
+// Tells what is wrong with a target, if anything.
+func validateTarget(t target) error {
+	var errs []error
+	if u, err := url.Parse(t.url); err != nil {
+		errs = append(errs, err)
+	} else if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("url %q should be an http or https url", t.url))
+	}
+	switch t.method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodOptions:
+	default:
+		errs = append(errs, fmt.Errorf("unknown method %q", t.method))
+	}
+	if t.interval < 0 {
+		errs = append(errs, fmt.Errorf("interval %v should not be negative", t.interval))
+	}
+	if t.timeout <= 0 {
+		errs = append(errs, fmt.Errorf("timeout %v should be positive", t.timeout))
+	}
+	if t.maxBody < 1 {
+		errs = append(errs, fmt.Errorf("max_body_bytes %d should be at least 1", t.maxBody))
+	}
+	if t.rule.degradedLatency < 0 {
+		errs = append(errs, fmt.Errorf("degraded latency %v should not be negative", t.rule.degradedLatency))
+	}
+	if t.retry.attempts < 0 || (t.retry.attempts > 0 && t.retry.backoff <= 0) || t.retry.maxBackoff < 0 {
+		errs = append(errs, fmt.Errorf("retries should not be negative, and need a positive backoff"))
+	}
+	if t.alerting.downAfter < 1 || t.alerting.upAfter < 1 {
+		errs = append(errs, fmt.Errorf("down_after %d and up_after %d should be at least 1", t.alerting.downAfter, t.alerting.upAfter))
+	}
+	if t.alerting.flapChanges < 0 || (t.alerting.flapChanges > 0 && t.alerting.flapWindow <= 0) {
+		errs = append(errs, fmt.Errorf("flap_changes should not be negative, and need a positive flap_window"))
+	}
+	if t.rule.statusMin < 100 || t.rule.statusMax > 599 || t.rule.statusMin > t.rule.statusMax {
+		errs = append(errs, fmt.Errorf("expected status %d-%d should be a range within 100-599", t.rule.statusMin, t.rule.statusMax))
+	}
+	switch t.mode {
+	case modeHTTP:
+	case modeTLS:
+		if !strings.HasPrefix(t.url, "https://") {
+			errs = append(errs, fmt.Errorf("mode tls needs an https url, not %q", t.url))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown mode %q: use http or tls", t.mode))
+	}
+	if slices.ContainsFunc(t.tls.warnDays, func(days int) bool { return days < 1 }) {
+		errs = append(errs, fmt.Errorf("warn_days %v should be at least 1 day", t.tls.warnDays))
+	}
+	if _, found := tlsVersions[t.tls.minVersion]; !found {
+		errs = append(errs, fmt.Errorf("unknown min_version %q: use 1.0, 1.1, 1.2 or 1.3", t.tls.minVersion))
+	}
+	if _, err := t.tls.roots(); err != nil {
+		errs = append(errs, fmt.Errorf("ca_file: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// setIf()
+// Sets a field to the value from the config, if the config sets it.
+func setIf[T any](field *T, value *T) {
+	if value != nil {
+		*field = *value
+	}
+}
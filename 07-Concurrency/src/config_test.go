@@ -0,0 +1,282 @@
+/**
+ * @file: Unit tests for the config file of the monitor, and its hot reload
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test Cases for parseConfig()
+// ****************************
+//   - Each target should take its own fields, then the defaults, then the base
+//   - Every validation error should be reported at once
+//   - Unknown fields and duplicate ids should be errors
+//   - Retries and alerting should take each field they set, and keep the defaults of the others
+//   - The TLS mode should need an https url, and a valid TLS policy
+
+func Test_parseConfig(t *testing.T) {
+	// TEST CASE 1: Defaults and overrides
+	// -----------------------------------
+	config := `{
+		"defaults": {"interval": "5s", "headers": {"User-Agent": "monitor"}, "tags": ["prod"]},
+		"targets": [
+			{"url": "https://go.dev"},
+			{"id": "api", "url": "https://example.com/health", "method": "head", "timeout": "2s",
+			 "headers": {"Authorization": "Bearer x"}, "expected_status": {"min": 200, "max": 204}, "tags": ["api"],
+			 "max_body_bytes": 4096}
+		]
+	}`
+	targets, err := parseConfig([]byte(config), newTarget(""))
+	if err != nil || len(targets) != 2 {
+		t.Fatalf("Test Case 1: Expected 2 targets. Got %d targets and error %v", len(targets), err)
+	}
+	goDev, api := targets[0], targets[1]
+	if goDev.id != "https://go.dev" || goDev.method != http.MethodGet || goDev.interval != 5*time.Second ||
+		goDev.timeout != defaultTimeout || goDev.headers.Get("User-Agent") != "monitor" || goDev.tags[0] != "prod" ||
+		goDev.maxBody != defaultMaxBody {
+		t.Errorf("Test Case 1: Expected the defaults on a bare target. Got %+v", goDev)
+	}
+	if api.id != "api" || api.method != http.MethodHead || api.timeout != 2*time.Second || api.rule.statusMax != 204 || api.maxBody != 4096 ||
+		api.headers.Get("Authorization") != "Bearer x" || api.headers.Get("User-Agent") != "monitor" || api.tags[0] != "api" {
+		t.Errorf("Test Case 1: Expected the fields of the target over the defaults. Got %+v", api)
+	}
+
+	// TEST CASE 2: Every validation error
+	// -----------------------------------
+	config = `{"targets": [
+		{"url": "ftp://example.com"},
+		{"url": "https://example.com", "method": "FETCH", "timeout": "0s", "expected_status": {"min": 300, "max": 200},
+		 "max_body_bytes": 0}
+	]}`
+	_, err = parseConfig([]byte(config), newTarget(""))
+	if !errors.Is(err, errInvalidConfig) {
+		t.Fatalf("Test Case 2: Expected an invalid config. Got %v", err)
+	}
+	for _, want := range []string{"target 1: url", "FETCH", "timeout 0s", "status 300-200", "max_body_bytes 0"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Test Case 2: Expected %q in the errors. Got %v", want, err)
+		}
+	}
+
+	// TEST CASE 3: Unknown fields and duplicate ids
+	// ---------------------------------------------
+	for _, config := range []string{
+		`{"targets": [{"url": "https://go.dev", "intervall": "5s"}]}`,
+		`{"targets": [{"url": "https://go.dev"}, {"url": "https://go.dev"}]}`,
+		`{"targets": [{"url": "https://go.dev", "interval": 5}]}`,
+		`{"defaults": {"url": "https://go.dev"}}`,
+	} {
+		if _, err := parseConfig([]byte(config), newTarget("")); !errors.Is(err, errInvalidConfig) {
+			t.Errorf("Test Case 3: Expected an invalid config for %s. Got %v", config, err)
+		}
+	}
+
+	// TEST CASE 4: Retries and alerting
+	// ---------------------------------
+	config = `{
+		"defaults": {"retries": {"attempts": 4}, "alerting": {"down_after": 5}},
+		"targets": [{"url": "https://go.dev", "retries": {"backoff": "1s"}, "alerting": {"flap_changes": 0}}]
+	}`
+	targets, err = parseConfig([]byte(config), newTarget(""))
+	if err != nil {
+		t.Fatalf("Test Case 4: Expected a valid config. Got %v", err)
+	}
+	wantRetry := retryPolicy{attempts: 4, backoff: time.Second, maxBackoff: defaultRetryMaxBackoff}
+	wantAlerting := alertPolicy{downAfter: 5, upAfter: defaultUpAfter, flapWindow: defaultFlapWindow, flapChanges: 0}
+	if targets[0].retry != wantRetry || targets[0].alerting != wantAlerting {
+		t.Errorf("Test Case 4: Expected %+v and %+v. Got %+v and %+v", wantRetry, wantAlerting, targets[0].retry, targets[0].alerting)
+	}
+	config = `{"targets": [{"url": "https://go.dev", "retries": {"attempts": -1}, "alerting": {"up_after": 0}}]}`
+	if _, err := parseConfig([]byte(config), newTarget("")); !errors.Is(err, errInvalidConfig) {
+		t.Errorf("Test Case 4: Expected an invalid config. Got %v", err)
+	}
+
+	// TEST CASE 5: TLS mode
+	// ---------------------
+	config = `{
+		"defaults": {"tls": {"min_version": "1.3"}},
+		"targets": [{"url": "https://go.dev", "mode": "tls", "tls": {"warn_days": [21, 3]}}, {"url": "https://example.com"}]
+	}`
+	targets, err = parseConfig([]byte(config), newTarget(""))
+	if err != nil {
+		t.Fatalf("Test Case 5: Expected a valid config. Got %v", err)
+	}
+	if cert := targets[0]; cert.mode != modeTLS || cert.tls.minVersion != "1.3" || len(cert.tls.warnDays) != 2 || cert.tls.warnDays[0] != 21 {
+		t.Errorf("Test Case 5: Expected a TLS target with its policy over the defaults. Got %+v", cert)
+	}
+	if targets[1].mode != modeHTTP {
+		t.Errorf("Test Case 5: Expected an HTTP target by default. Got %q", targets[1].mode)
+	}
+	config = `{"targets": [{"url": "http://go.dev", "mode": "tls", "tls": {"warn_days": [0], "min_version": "1.4", "ca_file": "missing.pem"}},
+		{"url": "https://go.dev", "mode": "ping"}]}`
+	_, err = parseConfig([]byte(config), newTarget(""))
+	for _, want := range []string{"https url", "warn_days", "min_version", "ca_file", "unknown mode"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Test Case 5: Expected %q in the errors. Got %v", want, err)
+		}
+	}
+}
+
+// Test Cases for parseNotifiers()
+// *******************************
+//   - Each type of notifier should be built with its routing, templates, dedup and rate limit
+//   - Every invalid notifier should be reported at once
+
+func Test_parseNotifiers(t *testing.T) {
+	// TEST CASE 1: Every type
+	// -----------------------
+	t.Setenv("TEST_SMTP_PASSWORD", "secret")
+	config := `{"notifiers": [
+		{"name": "ops", "type": "webhook", "url": "https://hooks.example.com/x", "tags": ["prod"], "on": ["down"],
+		 "dedup": "10m", "rate_limit": {"count": 5, "per": "1h"}, "subject": "{{.ID}} {{.To}}"},
+		{"name": "mail", "type": "smtp", "addr": "smtp.example.com:587", "from": "m@example.com", "to": ["o@example.com"],
+		 "username": "m", "password": "${TEST_SMTP_PASSWORD}"},
+		{"name": "hook", "type": "command", "command": ["./on-alert.sh"]},
+		{"name": "log", "type": "file", "path": "alerts.log"},
+		{"name": "syslog", "type": "syslog", "network": "udp", "address": "localhost:514"}
+	]}`
+	channels, err := parseNotifiers([]byte(config))
+	if err != nil || len(channels) != 5 {
+		t.Fatalf("Test Case 1: Expected 5 notifiers. Got %d and error %v", len(channels), err)
+	}
+	ops := channels[0]
+	if _, ok := ops.notifier.(*webhookNotifier); !ok || ops.tags[0] != "prod" || ops.on[0] != statusDown ||
+		ops.dedup != 10*time.Minute || ops.rateCount != 5 || ops.ratePer != time.Hour || ops.subject.Root.String() != "{{.ID}} {{.To}}" {
+		t.Errorf("Test Case 1: Expected the webhook with its rules. Got %+v", ops)
+	}
+	if mail, ok := channels[1].notifier.(*smtpNotifier); !ok || mail.password != "secret" {
+		t.Errorf("Test Case 1: Expected the SMTP password from the environment. Got %+v", channels[1].notifier)
+	}
+
+	// TEST CASE 2: Every error
+	// ------------------------
+	config = `{"notifiers": [
+		{"name": "a", "type": "pager"},
+		{"name": "a", "type": "webhook", "url": "ftp://x"},
+		{"type": "smtp", "addr": "smtp.example.com"},
+		{"name": "b", "type": "file", "path": "x.log", "on": ["degraded"]},
+		{"name": "c", "type": "file", "path": "x.log", "template": "{{.ID"}
+	]}`
+	_, err = parseNotifiers([]byte(config))
+	if !errors.Is(err, errInvalidConfig) {
+		t.Fatalf("Test Case 2: Expected an invalid config. Got %v", err)
+	}
+	for _, want := range []string{"unknown type \"pager\"", "name \"a\" already used", "webhook url", "needs a name",
+		"smtp needs", "on \"degraded\"", "notifier 5 (c)"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Test Case 2: Expected %q in the errors. Got %v", want, err)
+		}
+	}
+}
+
+// Test Cases for configWatcher
+// ****************************
+//   - A valid change of the file should be sent
+//   - An invalid change should be reported, and keep the current targets
+//   - The monitor should apply the adds, removes and changes without a restart
+
+func Test_configWatcher(t *testing.T) {
+	// One server for every target: It counts the requests by path, and checks the headers
+	var mu sync.Mutex
+	hits := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.Method+" "+r.URL.Path+" "+r.Header.Get("X-Team")]++
+		mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+	count := func(key string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return hits[key]
+	}
+
+	path := filepath.Join(t.TempDir(), "targets.json")
+	write := func(config string) {
+		// Change the size or the time of the file, so that the watcher sees every version
+		if err := os.WriteFile(path, []byte(strings.ReplaceAll(config, "URL", server.URL)), 0o666); err != nil {
+			t.Fatal(err)
+		}
+		later := time.Now().Add(time.Duration(len(config)) * time.Second)
+		os.Chtimes(path, later, later)
+	}
+	write(`{"defaults": {"interval": "10ms"}, "targets": [{"id": "a", "url": "URL/a"}, {"id": "b", "url": "URL/b"}]}`)
+
+	watcher, targets, err := newConfigWatcher(path, newTarget(""))
+	if err != nil || len(targets) != 2 {
+		t.Fatalf("Expected 2 targets. Got %d targets and error %v", len(targets), err)
+	}
+	watcher.every = 5 * time.Millisecond
+	reported := make(chan error, 10)
+	watcher.report = func(err error) { reported <- err }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := newMonitor(targets)
+	m.report = func(res checkResult) {}
+	notices := make(chan string, 10)
+	m.notify = func(msg string) { notices <- msg }
+	m.updates = watcher.watch(ctx)
+	done := make(chan *monitorSummary)
+	go func() { done <- m.run(ctx) }()
+
+	// TEST CASE 1: Valid change
+	// -------------------------
+	write(`{"defaults": {"interval": "10ms"}, "targets": [
+		{"id": "a", "url": "URL/a", "method": "HEAD", "headers": {"X-Team": "ops"}},
+		{"id": "c", "url": "URL/c"}
+	]}`)
+	select {
+	case msg := <-notices:
+		if msg != "Targets updated: 1 added, 1 changed, 1 removed" {
+			t.Errorf("Test Case 1: Expected 1 add, 1 change and 1 remove. Got %q", msg)
+		}
+	case err := <-reported:
+		t.Fatalf("Test Case 1: Expected the new targets. Got %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Test Case 1: Expected the new targets. Got nothing")
+	}
+	time.Sleep(100 * time.Millisecond)
+	bBefore := count("GET /b ")
+
+	// TEST CASE 2: Invalid change
+	// ---------------------------
+	write(`{"targets": [{"id": "a", "url": "URL/a", "method": "FETCH"}]}`)
+	select {
+	case err := <-reported:
+		if !errors.Is(err, errInvalidConfig) {
+			t.Errorf("Test Case 2: Expected an invalid config. Got %v", err)
+		}
+	case msg := <-notices:
+		t.Fatalf("Test Case 2: Expected the invalid targets to be ignored. Got %q", msg)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Test Case 2: Expected an error report. Got nothing")
+	}
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	<-done
+
+	// TEST CASE 3: Applied without a restart
+	// --------------------------------------
+	if count("HEAD /a ops") < 2 || count("GET /c ") < 2 {
+		t.Errorf("Test Case 3: Expected the changed and added targets to be checked. Got %v", hits)
+	}
+	if count("GET /b ") != bBefore {
+		t.Errorf("Test Case 3: Expected the removed target to stop being checked. Got %d then %d checks", bBefore, count("GET /b "))
+	}
+}
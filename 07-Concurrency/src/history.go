@@ -0,0 +1,82 @@
+/**
+ * @file: Describes how the monitor keeps its history: Each result goes to the history store on disk,
+ * which is compacted while running.
+ *
+ * The store lives in the history package. The uptime and SLA reports are made by cmd/slareport.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/maevadevs/Go-Developer-Foundation/07-Concurrency/history"
+)
+
+// Constants
+// *********
+
+// Pause between two compactions of the history store.
+const defaultCompactEvery = time.Hour
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newHistoryRecord()
+// Initializes the record of a result in the history store, with the state of its target after it.
+func newHistoryRecord(res checkResult, status targetStatus) history.Record {
+	r := history.Record{
+		Target:     res.target.id,
+		URL:        res.target.url,
+		At:         res.checkedAt,
+		LatencyMs:  float64(res.latency.Microseconds()) / 1000,
+		Verdict:    string(res.verdict),
+		State:      string(status),
+		StatusCode: res.statusCode,
+	}
+	if res.err != nil {
+		r.Error = res.err.Error()
+	}
+	return r
+}
+
+// Helper Functions
+// ****************
+
+// recordTo()
+// Returns a recorder of the results into the store, for monitor.record. An error is reported, and the monitor goes on.
+func recordTo(store *history.Store, report func(err error)) func(res checkResult, status targetStatus) {
+	return func(res checkResult, status targetStatus) {
+		if err := store.Append(newHistoryRecord(res, status)); err != nil {
+			report(fmt.Errorf("history: %w", err))
+		}
+	}
+}
+
+// compactHistory()
+// Compacts the store now, then every so often until ctx is done. Each compaction that did something is reported.
+func compactHistory(ctx context.Context, store *history.Store, every time.Duration, report func(msg string)) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		stats, err := store.Compact(time.Now())
+		switch {
+		case err != nil:
+			report(fmt.Sprintf("History: compaction failed: %v", err))
+		case stats.Segments > 0 || stats.RemovedRollups > 0:
+			report(fmt.Sprintf("History: %d segments (%d records) rolled up into %d rollups, %d old rollup files deleted",
+				stats.Segments, stats.Records, stats.Rollups, stats.RemovedRollups))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,71 @@
+/**
+ * @file: Unit tests for the history of the monitor
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/maevadevs/Go-Developer-Foundation/07-Concurrency/history"
+)
+
+// Test Cases for the history of the monitor
+// *****************************************
+//   - Each result should become a record, with the state of its target
+//   - A running monitor should record every check in the store
+
+func Test_monitorHistory(t *testing.T) {
+	// TEST CASE 1: A result as a record
+	// ---------------------------------
+	tg := newTarget("https://example.com")
+	tg.id = "example"
+	at := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	res := checkResult{target: tg, checkedAt: at, latency: 1500 * time.Microsecond, verdict: verdictDown, err: errors.New("refused")}
+	want := history.Record{Target: "example", URL: "https://example.com", At: at, LatencyMs: 1.5, Verdict: "down", State: "flapping", Error: "refused"}
+	if got := newHistoryRecord(res, statusFlapping); got != want {
+		t.Errorf("Test Case 1: Expected %+v. Got %+v", want, got)
+	}
+
+	// TEST CASE 2: Every check of a run in the store
+	// ----------------------------------------------
+	store, err := history.Open(history.DefaultOptions(t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	up := newStatusServer(t, http.StatusOK, 0)
+	down := newStatusServer(t, http.StatusInternalServerError, 0)
+	failing := newTarget(down.URL)
+	failing.retry.attempts = 0
+	m := newMonitor([]target{newTarget(up.URL), failing})
+	m.interval = 20 * time.Millisecond
+	m.report = func(res checkResult) {}
+	m.alert = func(change stateChange) {}
+	m.record = recordTo(store, func(err error) { t.Error(err) })
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	summary := m.run(ctx)
+
+	states := map[string]string{}
+	recorded := 0
+	err = store.Scan(time.Time{}, time.Now().Add(time.Hour), func(r history.Record) error {
+		recorded++
+		states[r.Target] = r.State
+		return nil
+	})
+	if err != nil || recorded != summary.checks {
+		t.Errorf("Test Case 2: Expected the %d checks in the store. Got %d (%v)", summary.checks, recorded, err)
+	}
+	if states[up.URL] != "up" || states[down.URL] != "down" {
+		t.Errorf("Test Case 2: Expected the last states up and down. Got %v", states)
+	}
+}
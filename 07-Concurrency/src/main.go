@@ -1,6 +1,6 @@
-// PART 4
+// PART 5
 // ******
-// To run this file, rename it to main.go and run as the listed call at the end of the file
+// The status checker grows into a URL monitor, split into several files
 
 // Package
 // *******
@@ -9,112 +9,203 @@ package main
 // Imports
 // *******
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
+
+	"github.com/maevadevs/Go-Developer-Foundation/07-Concurrency/history"
 )
 
+// Project Structure
+// *****************
+// 07-Concurrency (Module)
+// |- targets.json   - Config file of the targets, read by default
+// |- history/       - Library package: The history store (segmented log, rollups, retention) and the SLA reports
+// |- cmd/slareport/main.go - SLA report: Uptime, incidents, MTTR and latency percentiles of each target over a time range
+// |- cmd/slareport/main_test.go - Automated tests for the time range of the SLA report
+// |- main.go        - Executable: The URL monitor
+// |- check.go       - Health checks: Status code, latency, size, error class and up/degraded/down verdict
+// |- check_test.go  - Automated tests for check.go, against local httptest servers
+// |- config.go      - Config file of the targets, in JSON: Parsing, validation, and a watcher for hot reload
+// |- config_test.go - Automated tests for config.go
+// |- history.go     - History: Each result recorded in the history store, compacted while running
+// |- history_test.go - Automated tests for history.go
+// |- metrics.go     - Prometheus metrics of the checks and the scheduler, in the text exposition format
+// |- metrics_test.go - Automated tests for metrics.go
+// |- monitor.go     - The monitor: Checks targets until stopped, drains checks in flight, sums up the run
+// |- monitor_test.go - Automated tests for monitor.go
+// |- notify.go      - Alerts: The notifier interface, and a dispatcher with routing, templates, dedup and rate limits
+// |- notify_test.go - Automated tests for notify.go
+// |- notifiers.go   - Built-in notifiers: Webhook (JSON POST), SMTP email, command hook, file and syslog
+// |- notifiers_test.go - Automated tests for notifiers.go, against httptest, a fake SMTP server and a UDP listener
+// |- retry.go       - Retries of a failed check, with exponential backoff and jitter
+// |- retry_test.go  - Automated tests for retry.go
+// |- scheduler.go   - The scheduler: A priority queue of targets keyed by next run time, with jitter
+// |- scheduler_test.go - Automated tests for scheduler.go and the worker pool, up to 10k targets
+// |- status.go      - Status board: Recent checks, uptime and incidents of each target
+// |- status_test.go - Automated tests for status.go
+// |- state.go       - State of a target: up/down after N checks in a row, flap detection, alerts on changes
+// |- state_test.go  - Automated tests for state.go
+// |- tls.go         - TLS check mode: Certificate expiry, issuer, SANs, chain and host name, protocol version
+// |- tls_test.go    - Automated tests for tls.go, against httptest TLS servers with generated certificates
+// |- web.go         - Web interface: Status page, JSON API (/api/targets), /metrics, and the static export
+// |- web_test.go    - Automated tests for web.go
+// |- main.go.0X.*.gopart - Earlier parts of the status checker
+
 // Functions
 // ********
 
 // This is the main entry of the application.
 func main() {
-	// A slice of urls
-	urls := []string{
-		"https://google.com",
-		"https://facebook.com",
-		"https://stackoverflow.com",
-		"https://go.dev",
-		"https://amazon.com",
+	config := flag.String("config", "07-Concurrency/targets.json", "config file of the targets, reloaded when it changes")
+	interval := flag.Duration("interval", defaultInterval, "pause between two checks of a target, unless the config sets it")
+	timeout := flag.Duration("timeout", defaultTimeout, "time allowed for each check, unless the config sets it")
+	jitter := flag.Float64("jitter", defaultJitter, "stretch or shrink each interval at random by up to this fraction")
+	workers := flag.Int("workers", defaultWorkers, "number of checks that can run at the same time")
+	listen := flag.String("listen", "localhost:8080", "address of the status page, the JSON API and /metrics (empty: none)")
+	export := flag.String("export", "", "file to export the static status page to, every -export-every and once stopped")
+	exportEvery := flag.Duration("export-every", time.Minute, "pause between two exports of the status page")
+	historyDir := flag.String("history", "", "directory of the history store, to keep every check for reports (empty: none)")
+	retention := flag.Duration("retention", history.DefaultRawRetention, "keep the checks in the history this long, then roll them up by the hour")
+	rollupRetention := flag.Duration("rollup-retention", history.DefaultRollupRetention, "keep the hourly rollups in the history this long")
+	runFor := flag.Duration("run-for", 0, "stop after this long (default: run until interrupted)")
+	flag.Parse()
+
+	// The targets come from the config file: Each url with the rule that tells what counts as up
+	base := newTarget("")
+	base.timeout = *timeout
+	watcher, targets, err := newConfigWatcher(*config, base)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	channels, err := loadNotifiers(*config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	var store *history.Store
+	if *historyDir != "" {
+		opts := history.DefaultOptions(*historyDir)
+		opts.RawRetention, opts.RollupRetention = *retention, *rollupRetention
+		if store, err = history.Open(opts); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
 	}
 
-	// Channel for communicating with go routines
-	ch := make(chan string)
-
-	// Loop through the urls
-	for _, url := range urls {
-		// Create a new Go Routine for each call
-		// Pass the channel to the new routine
-		go checkUrl(url, ch)
+	// Stop on Ctrl-C (SIGINT) or SIGTERM, or once the run is over
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if *runFor > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *runFor)
+		defer cancel()
 	}
 
-	// Receive the messages from the channel
-	// NOTE: This code is blocking
-	// The main routine will wait here until something happen
-	// Once something happen, it continues execution
-	// fmt.Println(<-ch)
-
-	// So we would need to check the channel multiple times for each urls
-	// for i := 0; i < len(urls); i++ {
-	// 	// Receive message from the channel
-	// 	// This is a blocking call
-	// 	fmt.Println(<-ch)
-	// }
-
-	// Keep on checking the url in an infinite loop
-	// for {
-	// 	// Receive message from the channel
-	//     // Span a new go routine to recheck the url again
-	//     // This is a blocking call
-	// 	go checkUrl(<-ch, ch)
-	// }
-
-	// Alternative Syntax: `range` can also be used with channels
-	// Keep on checking the url in an infinite loop
-	// for l := range ch {
-	//     // Receive message from the channel
-	//     // Span a new go routine to recheck the url again
-	//     // This is a blocking call
-	//     go checkUrl(l, ch)
-	// }
-
-	// We should add a slight pause between each new call
-	// Keep on checking the url in an infinite loop
-	for l := range ch {
-		// Receive message from the channel
-		// This is a blocking call
-		// Use a function literal so to not block the main routine
-		go func(lnk string) {
-			// NOTE: time.Sleep pauses the current Go Routine, which is the function literal
-			// Pause of 2 seconds
-			time.Sleep(time.Second * 2)
-
-			// Recheck the url again
-			checkUrl(lnk, ch)
-		}(l)
+	// Check the targets until stopped
+	m := newMonitor(targets)
+	m.interval = *interval
+	m.jitter = *jitter
+	m.workers = *workers
+	if err := m.validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	m.updates = watcher.watch(ctx)
+	// Each alert is printed, and sent to the notifiers that want it
+	alerts := newDispatcher(channels)
+	alerts.start()
+	m.alert = func(change stateChange) {
+		fmt.Println(change.toString())
+		alerts.dispatch(change)
 	}
-}
 
-// Helper Functions
-// ****************
+	// Keep every check in the history store, compacted while running
+	compacted := make(chan struct{})
+	if store != nil {
+		m.record = recordTo(store, func(err error) { fmt.Fprintln(os.Stderr, err) })
+		go func() {
+			defer close(compacted)
+			compactHistory(ctx, store, defaultCompactEvery, func(msg string) { fmt.Println(msg) })
+		}()
+	} else {
+		close(compacted)
+	}
 
-// Check if a URL is reachable or not.
-func checkUrl(url string, ch chan string) {
-	// Test the url with a Get call
-	_, err := http.Get(url)
-	// Error Handling
-	if err != nil {
-		fmt.Println(url, "might be down")
-		// Send the url via the channel
-		ch <- url
-		return
+	// Serve the status page, the JSON API and the metrics while running
+	var server *http.Server
+	if *listen != "" {
+		server = &http.Server{Addr: *listen, Handler: newWebHandler(m.board, m.metrics), ReadHeaderTimeout: 5 * time.Second}
+		go func() {
+			if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}()
 	}
 
-	// Else, we are good
-	fmt.Println(url, "is up")
+	// Export the static status page while running
+	exported := make(chan struct{})
+	go func() {
+		defer close(exported)
+		if *export == "" {
+			return
+		}
+		ticker := time.NewTicker(*exportEvery)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := exportStatusPage(*export, m.board); err != nil {
+					fmt.Fprintln(os.Stderr, err)
+				}
+			}
+		}
+	}()
+
+	summary := m.run(ctx)
+	<-exported
+	<-compacted
+	if store != nil {
+		if err := store.Close(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+	if *export != "" {
+		if err := exportStatusPage(*export, m.board); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+	alerts.stop(defaultSendTimeout)
+	if server != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		server.Shutdown(shutdownCtx)
+		cancel()
+	}
 
-	// Send the url via the channel
-	ch <- url
+	// Once stopped, the checks in flight are drained: Sum up the run
+	fmt.Println()
+	fmt.Print(summary.toString())
 }
 
 // FOR WINDOWS:
-//  To run:                 go run 07-Concurrency\src\main.go
-//  To compile:             go build -o 07-Concurrency\bin\concurrency.exe 07-Concurrency\src\main.go
+//  To run:                 go run .\07-Concurrency\src
+//  To test:                go test .\07-Concurrency\src
+//  To compile:             go build -o 07-Concurrency\bin\concurrency.exe .\07-Concurrency\src
 //  To run after compile:   .\07-Concurrency\bin\concurrency.exe
-//  Compile + Run:          go build -o 07-Concurrency\bin\concurrency.exe 07-Concurrency\src\main.go && .\07-Concurrency\bin\concurrency.exe
+//  Compile + Run:          go build -o 07-Concurrency\bin\concurrency.exe .\07-Concurrency\src && .\07-Concurrency\bin\concurrency.exe
 
 // FOR LINUX:
-//  To run:                 go run 07-Concurrency/src/main.go
-//  To compile:             go build -o 07-Concurrency/bin/concurrency 07-Concurrency/src/main.go
+//  To run:                 go run ./07-Concurrency/src
+//  To test:                go test ./07-Concurrency/src
+//  To compile:             go build -o 07-Concurrency/bin/concurrency ./07-Concurrency/src
 //  To run after compile:   ./07-Concurrency/bin/concurrency
-//  Compile + Run:          go build -o 07-Concurrency/bin/concurrency 07-Concurrency/src/main.go && ./07-Concurrency/bin/concurrency
+//  Compile + Run:          go build -o 07-Concurrency/bin/concurrency ./07-Concurrency/src && ./07-Concurrency/bin/concurrency
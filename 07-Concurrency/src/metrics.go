@@ -0,0 +1,250 @@
+/**
+ * @file: Describes the metrics of the monitor, served in the Prometheus text exposition format.
+ *
+ * The exposition is written by hand: No client library needed.
+ *   - monitor_target_up: 1 if the last check of the target was not down, 0 otherwise
+ *   - monitor_check_duration_seconds: Histogram of the latency of the checks
+ *   - monitor_checks_total: Checks by outcome (up, degraded, down)
+ *   - monitor_check_errors_total: Failed checks by error class
+ *   - monitor_target_last_success_timestamp_seconds: Time of the last check that was not down
+ *   - monitor_tls_cert_expiry_timestamp_seconds: Expiry of the certificate seen by the last TLS check
+ *   - monitor_scheduler_queue_depth, monitor_checks_in_flight, monitor_targets: The scheduler
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Constants
+// *********
+
+// Upper bounds of the latency buckets, in seconds.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Outcomes of a check, in the order of the exposition.
+var outcomes = []verdict{verdictUp, verdictDegraded, verdictDown}
+
+// Type Declaration
+// ****************
+
+// A monitorMetrics holds the metrics of a monitor. It is safe for concurrent use.
+type monitorMetrics struct {
+	mu       sync.Mutex
+	targets  map[string]*targetMetrics
+	queued   int
+	inFlight int
+}
+
+// A targetMetrics holds the metrics of one target.
+type targetMetrics struct {
+	url         string
+	up          bool
+	checks      map[verdict]uint64
+	errors      map[errorClass]uint64
+	lastSuccess time.Time
+	// Expiry of the certificate: Zero until a TLS check sees one
+	certExpiry time.Time
+	// Latency histogram: Count per bucket (not cumulative), with +Inf last
+	buckets    []uint64
+	latencySum float64
+	count      uint64
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newMonitorMetrics()
+// Initializes empty metrics.
+func newMonitorMetrics() *monitorMetrics {
+	return &monitorMetrics{targets: map[string]*targetMetrics{}}
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// monitorMetrics.observe()
+// Receiver Function that counts a result in the metrics of its target.
+func (mm *monitorMetrics) observe(res checkResult) {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	tm, found := mm.targets[res.target.id]
+	if !found {
+		tm = &targetMetrics{checks: map[verdict]uint64{}, errors: map[errorClass]uint64{}, buckets: make([]uint64, len(latencyBuckets)+1)}
+		mm.targets[res.target.id] = tm
+	}
+	tm.url = res.target.url
+	tm.up = res.verdict != verdictDown
+	tm.checks[res.verdict]++
+	if res.errClass != errorNone {
+		tm.errors[res.errClass]++
+	}
+	if tm.up {
+		tm.lastSuccess = res.checkedAt
+	}
+	if res.tls != nil && !res.tls.NotAfter.IsZero() {
+		tm.certExpiry = res.tls.NotAfter
+	}
+	seconds := res.latency.Seconds()
+	bucket, _ := slices.BinarySearch(latencyBuckets, seconds)
+	tm.buckets[bucket]++
+	tm.latencySum += seconds
+	tm.count++
+}
+
+// monitorMetrics.setScheduler()
+// Receiver Function that records the state of the scheduler: Targets waiting in the queue, and checks in flight.
+func (mm *monitorMetrics) setScheduler(queued, inFlight int) {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	mm.queued, mm.inFlight = queued, inFlight
+}
+
+// monitorMetrics.retain()
+// Receiver Function that forgets the targets that are no longer monitored.
+func (mm *monitorMetrics) retain(keep func(id string) bool) {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	for id := range mm.targets {
+		if !keep(id) {
+			delete(mm.targets, id)
+		}
+	}
+}
+
+// monitorMetrics.writeTo()
+// Receiver Function that writes the metrics in the Prometheus text exposition format, targets sorted by id.
+// The metrics are rendered under the lock, and written after it: A slow reader does not hold up the checks.
+func (mm *monitorMetrics) writeTo(w io.Writer) error {
+	_, err := w.Write(mm.render())
+	return err
+}
+
+// monitorMetrics.render()
+// Receiver Function that renders the metrics in the Prometheus text exposition format.
+func (mm *monitorMetrics) render() []byte {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	var buf bytes.Buffer
+	ids := make([]string, 0, len(mm.targets))
+	for id := range mm.targets {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	header := func(name, kind, help string) {
+		fmt.Fprintf(&buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+
+	header("monitor_target_up", "gauge", "1 if the last check of the target was not down, 0 otherwise.")
+	for _, id := range ids {
+		tm := mm.targets[id]
+		fmt.Fprintf(&buf, "monitor_target_up{%s} %d\n", labels("target", id, "url", tm.url), boolToInt(tm.up))
+	}
+
+	header("monitor_check_duration_seconds", "histogram", "Latency of the checks, retries included.")
+	for _, id := range ids {
+		tm := mm.targets[id]
+		cumulative := uint64(0)
+		for i, bound := range latencyBuckets {
+			cumulative += tm.buckets[i]
+			le := strconv.FormatFloat(bound, 'g', -1, 64)
+			fmt.Fprintf(&buf, "monitor_check_duration_seconds_bucket{%s} %d\n", labels("target", id, "le", le), cumulative)
+		}
+		fmt.Fprintf(&buf, "monitor_check_duration_seconds_bucket{%s} %d\n", labels("target", id, "le", "+Inf"), tm.count)
+		fmt.Fprintf(&buf, "monitor_check_duration_seconds_sum{%s} %s\n", labels("target", id), formatFloat(tm.latencySum))
+		fmt.Fprintf(&buf, "monitor_check_duration_seconds_count{%s} %d\n", labels("target", id), tm.count)
+	}
+
+	header("monitor_checks_total", "counter", "Checks by outcome: up, degraded or down.")
+	for _, id := range ids {
+		for _, outcome := range outcomes {
+			fmt.Fprintf(&buf, "monitor_checks_total{%s} %d\n", labels("target", id, "outcome", string(outcome)), mm.targets[id].checks[outcome])
+		}
+	}
+
+	header("monitor_check_errors_total", "counter", "Checks without a response, by error class.")
+	for _, id := range ids {
+		tm := mm.targets[id]
+		classes := make([]errorClass, 0, len(tm.errors))
+		for class := range tm.errors {
+			classes = append(classes, class)
+		}
+		slices.Sort(classes)
+		for _, class := range classes {
+			fmt.Fprintf(&buf, "monitor_check_errors_total{%s} %d\n", labels("target", id, "class", string(class)), tm.errors[class])
+		}
+	}
+
+	header("monitor_target_last_success_timestamp_seconds", "gauge", "Unix time of the last check that was not down.")
+	for _, id := range ids {
+		if tm := mm.targets[id]; !tm.lastSuccess.IsZero() {
+			seconds := float64(tm.lastSuccess.UnixNano()) / float64(time.Second)
+			fmt.Fprintf(&buf, "monitor_target_last_success_timestamp_seconds{%s} %s\n", labels("target", id), formatFloat(seconds))
+		}
+	}
+
+	header("monitor_tls_cert_expiry_timestamp_seconds", "gauge", "Unix time the certificate seen by the last TLS check expires.")
+	for _, id := range ids {
+		if tm := mm.targets[id]; !tm.certExpiry.IsZero() {
+			fmt.Fprintf(&buf, "monitor_tls_cert_expiry_timestamp_seconds{%s} %d\n", labels("target", id), tm.certExpiry.Unix())
+		}
+	}
+
+	header("monitor_scheduler_queue_depth", "gauge", "Targets waiting in the queue of the scheduler.")
+	fmt.Fprintf(&buf, "monitor_scheduler_queue_depth %d\n", mm.queued)
+	header("monitor_checks_in_flight", "gauge", "Checks running on the workers.")
+	fmt.Fprintf(&buf, "monitor_checks_in_flight %d\n", mm.inFlight)
+	header("monitor_targets", "gauge", "Targets with metrics.")
+	fmt.Fprintf(&buf, "monitor_targets %d\n", len(ids))
+	return buf.Bytes()
+}
+
+// monitorMetrics.ServeHTTP()
+// Receiver Function that serves the metrics to Prometheus. It implements http.Handler.
+func (mm *monitorMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	mm.writeTo(w)
+}
+
+// Helper Functions
+// ****************
+
+// labels()
+// Formats label pairs: labels("a", "1", "b", "2") is a="1",b="2". Values are escaped.
+func labels(pairs ...string) string {
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	parts := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, pairs[i], escape.Replace(pairs[i+1])))
+	}
+	return strings.Join(parts, ",")
+}
+
+// formatFloat()
+// Formats a sample value as Prometheus reads it.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// boolToInt()
+// Converts a boolean to 1 or 0.
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,157 @@
+/**
+ * @file: Unit tests for the Prometheus metrics of the monitor
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test Cases for monitorMetrics
+// *****************************
+//   - Each result should count in the up gauge, the histogram, the counters and the last success
+//   - Label values should be escaped
+//   - The metrics should be served in the text exposition format
+//   - The monitor should fill the metrics while running, and forget removed targets
+//   - A TLS check should set the expiry of the certificate
+//   - A slow reader should not hold up the checks
+
+func Test_monitorMetrics(t *testing.T) {
+	// TEST CASE 1: Results
+	// --------------------
+	mm := newMonitorMetrics()
+	api := newTarget("https://api.example.com")
+	api.id = "api"
+	at := time.Unix(1700000000, 500_000_000)
+	mm.observe(checkResult{target: api, checkedAt: at, latency: 20 * time.Millisecond, verdict: verdictUp})
+	mm.observe(checkResult{target: api, checkedAt: at.Add(time.Minute), latency: 2 * time.Second, verdict: verdictDegraded})
+	mm.observe(checkResult{target: api, checkedAt: at.Add(2 * time.Minute), latency: 20 * time.Second, verdict: verdictDown,
+		errClass: errorTimeout, err: errors.New("timeout")})
+	mm.setScheduler(7, 2)
+
+	var sb strings.Builder
+	if err := mm.writeTo(&sb); err != nil {
+		t.Fatal(err)
+	}
+	exposition := sb.String()
+	for _, want := range []string{
+		"# TYPE monitor_target_up gauge\n",
+		`monitor_target_up{target="api",url="https://api.example.com"} 0` + "\n",
+		"# TYPE monitor_check_duration_seconds histogram\n",
+		`monitor_check_duration_seconds_bucket{target="api",le="0.01"} 0` + "\n",
+		`monitor_check_duration_seconds_bucket{target="api",le="0.025"} 1` + "\n",
+		`monitor_check_duration_seconds_bucket{target="api",le="2.5"} 2` + "\n",
+		`monitor_check_duration_seconds_bucket{target="api",le="10"} 2` + "\n",
+		`monitor_check_duration_seconds_bucket{target="api",le="+Inf"} 3` + "\n",
+		`monitor_check_duration_seconds_sum{target="api"} 22.02` + "\n",
+		`monitor_check_duration_seconds_count{target="api"} 3` + "\n",
+		`monitor_checks_total{target="api",outcome="up"} 1` + "\n",
+		`monitor_checks_total{target="api",outcome="degraded"} 1` + "\n",
+		`monitor_checks_total{target="api",outcome="down"} 1` + "\n",
+		`monitor_check_errors_total{target="api",class="timeout"} 1` + "\n",
+		`monitor_target_last_success_timestamp_seconds{target="api"} 1.7000000605e+09` + "\n",
+		"monitor_scheduler_queue_depth 7\n",
+		"monitor_checks_in_flight 2\n",
+		"monitor_targets 1\n",
+	} {
+		if !strings.Contains(exposition, want) {
+			t.Errorf("Test Case 1: Expected %q. Got:\n%s", want, exposition)
+		}
+	}
+	if strings.Contains(exposition, "monitor_tls_cert_expiry_timestamp_seconds{") {
+		t.Errorf("Test Case 1: Expected no certificate expiry without a TLS check. Got:\n%s", exposition)
+	}
+
+	// TEST CASE 2: Escaping
+	// ---------------------
+	if got := labels("target", "a\"b\\c\nd"); got != `target="a\"b\\c\nd"` {
+		t.Errorf("Test Case 2: Expected an escaped label. Got %s", got)
+	}
+
+	// TEST CASE 3: Served
+	// -------------------
+	server := httptest.NewServer(mm)
+	t.Cleanup(server.Close)
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain; version=0.0.4") || string(body) != exposition {
+		t.Errorf("Test Case 3: Expected the exposition as text/plain. Got %q", resp.Header.Get("Content-Type"))
+	}
+
+	// TEST CASE 4: Filled by the monitor
+	// ----------------------------------
+	fast := newStatusServer(t, http.StatusOK, 0)
+	a, b := newTarget(fast.URL+"/a"), newTarget(fast.URL+"/b")
+	updates := make(chan []target, 1)
+	updates <- []target{a}
+	m := newMonitor([]target{a, b})
+	m.interval = 10 * time.Millisecond
+	m.report, m.notify = func(res checkResult) {}, func(msg string) {}
+	m.updates = updates
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	m.run(ctx)
+	sb.Reset()
+	m.metrics.writeTo(&sb)
+	if exposition := sb.String(); !strings.Contains(exposition, `monitor_target_up{target="`+a.id+`",url="`+a.url+`"} 1`) ||
+		strings.Contains(exposition, b.url) || !strings.Contains(exposition, "monitor_scheduler_queue_depth 1\n") ||
+		!strings.Contains(exposition, "monitor_checks_in_flight 0\n") {
+		t.Errorf("Test Case 4: Expected the metrics of a only, a in the queue and no check in flight once stopped. Got:\n%s", exposition)
+	}
+
+	// TEST CASE 5: Certificate expiry
+	// -------------------------------
+	cert := newTarget("https://cert.example.com")
+	cert.id, cert.mode = "cert", modeTLS
+	mm.observe(checkResult{target: cert, checkedAt: at, verdict: verdictUp, tls: &tlsInfo{NotAfter: time.Unix(1800000000, 0)}})
+	sb.Reset()
+	mm.writeTo(&sb)
+	if want := `monitor_tls_cert_expiry_timestamp_seconds{target="cert"} 1800000000` + "\n"; !strings.Contains(sb.String(), want) {
+		t.Errorf("Test Case 5: Expected %q. Got:\n%s", want, sb.String())
+	}
+
+	// TEST CASE 6: Slow reader
+	// ------------------------
+	observed := make(chan struct{})
+	reader := writerFunc(func(p []byte) (int, error) {
+		// The reader is stuck until a check is observed
+		go func() {
+			mm.observe(checkResult{target: api, checkedAt: at, verdict: verdictUp})
+			close(observed)
+		}()
+		select {
+		case <-observed:
+		case <-time.After(time.Second):
+			t.Errorf("Test Case 6: Expected a check observed while the metrics are written. Got none in 1s")
+		}
+		return len(p), nil
+	})
+	if err := mm.writeTo(reader); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writerFunc is an io.Writer made of a function.
+type writerFunc func(p []byte) (int, error)
+
+// writerFunc.Write()
+// Receiver Function that calls the function. It implements io.Writer.
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
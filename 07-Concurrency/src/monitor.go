@@ -0,0 +1,289 @@
+/**
+ * @file: Describes the URL monitor: It checks its targets over and over until it is stopped,
+ * then drains the checks in flight and sums up what it saw.
+ *
+ * One goroutine schedules the checks, and a fixed pool of workers runs them:
+ * Memory and goroutines stay flat whether the monitor watches 5 URLs or 10k.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Constants
+// *********
+
+// Default pause between two checks of a target.
+const defaultInterval = 2 * time.Second
+
+// Type Declaration
+// ****************
+
+// A monitor checks its targets every interval, with a shared HTTP client and a pool of workers.
+type monitor struct {
+	client  *http.Client
+	targets []target
+	// Interval of the targets that do not have their own
+	interval time.Duration
+	// Each interval is stretched or shrunk at random by up to this fraction
+	jitter float64
+	// Number of checks that can run at the same time
+	workers int
+	// New sets of targets to apply while running: nil for none
+	updates <-chan []target
+	// Called with each result, from the goroutine of monitor.run()
+	report func(res checkResult)
+	// Called with each notice, such as a new set of targets, from the goroutine of monitor.run()
+	notify func(msg string)
+	// Called with each change of state of a target, from the goroutine of monitor.run()
+	alert func(change stateChange)
+	// Metrics of the checks and of the scheduler, updated while running
+	metrics *monitorMetrics
+	// Recent history of the targets, updated while running
+	board *statusBoard
+	// Called with each result and the state of its target after it, from the goroutine of monitor.run()
+	record func(res checkResult, status targetStatus)
+}
+
+// A monitorSummary sums up the checks of a run.
+type monitorSummary struct {
+	started  time.Time
+	duration time.Duration
+	checks   int
+	alerts   int
+	targets  []*targetSummary
+	byId     map[string]*targetSummary
+}
+
+// A targetSummary sums up the checks of one target.
+type targetSummary struct {
+	id           string
+	checks       int
+	verdicts     map[verdict]int
+	totalLatency time.Duration
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newMonitor()
+// Initializes a monitor of the targets with the default interval, jitter and workers, and a shared HTTP client.
+func newMonitor(targets []target) *monitor {
+	return &monitor{
+		client:   newHttpClient(),
+		targets:  targets,
+		interval: defaultInterval,
+		jitter:   defaultJitter,
+		workers:  defaultWorkers,
+		report:   func(res checkResult) { fmt.Println(res.toString()) },
+		notify:   func(msg string) { fmt.Println(msg) },
+		alert:    func(change stateChange) { fmt.Println(change.toString()) },
+		metrics:  newMonitorMetrics(),
+		board:    newStatusBoard(),
+		record:   func(res checkResult, status targetStatus) {},
+	}
+}
+
+// newMonitorSummary()
+// Initializes an empty summary of the targets.
+func newMonitorSummary(targets []target) *monitorSummary {
+	summary := &monitorSummary{started: time.Now(), byId: map[string]*targetSummary{}}
+	for _, t := range targets {
+		if _, found := summary.byId[t.id]; !found {
+			ts := &targetSummary{id: t.id, verdicts: map[verdict]int{}}
+			summary.targets = append(summary.targets, ts)
+			summary.byId[t.id] = ts
+		}
+	}
+	return summary
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// monitor.run()
+// Receiver Function that checks the targets until ctx is done. It then stops scheduling checks,
+// waits for the checks in flight to finish (each is bounded by its target's timeout),
+// and returns the summary of the run.
+//
+// A target is never checked twice at once: It goes back in the queue once its check is done.
+func (m *monitor) run(ctx context.Context) *monitorSummary {
+	summary := newMonitorSummary(m.targets)
+	queue := newScheduler(m.withDefaults(m.targets), m.jitter, time.Now())
+
+	// The workers: Checks in flight are not canceled with ctx, they finish or time out on their own.
+	// Only their retries are.
+	jobs := make(chan target)
+	results := make(chan checkResult)
+	var workers sync.WaitGroup
+	for range max(m.workers, 1) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for t := range jobs {
+				results <- checkWithRetries(ctx, m.client, t)
+			}
+		}()
+	}
+
+	// The scheduler: Hand the due targets to the workers, and put them back in the queue once checked
+	states := map[string]*targetState{}
+	expiry := newExpiryWarner()
+	collect := func(res checkResult) {
+		summary.add(res)
+		m.report(res)
+		// A target removed or changed during its check: The result is of a version that is gone.
+		// A removed target has its metrics, state and history forgotten already
+		if current, found := queue.targets[res.target.id]; !found || !reflect.DeepEqual(current, res.target) {
+			return
+		}
+		m.metrics.observe(res)
+		state, found := states[res.target.id]
+		if !found {
+			state = newTargetState()
+			states[res.target.id] = state
+		}
+		change, changed := state.observe(res)
+		m.board.observe(res, state.status)
+		m.record(res, state.status)
+		if warning, warn := expiry.observe(res); warn {
+			summary.alerts++
+			m.alert(stateChange{target: res.target, from: state.status, to: statusWarning, at: res.checkedAt, result: res, warning: warning})
+		}
+		if changed {
+			summary.alerts++
+			m.board.change(change)
+			m.alert(change)
+		}
+	}
+	inFlight := 0
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for running := true; running; {
+		m.metrics.setScheduler(queue.len(), inFlight)
+
+		// Offer the next target to the workers only once it is due: A nil channel blocks the send
+		var dispatch chan<- target
+		var due target
+		if next := queue.peek(); next != nil {
+			if wait := time.Until(next.next); wait > 0 {
+				timer.Reset(wait)
+			} else {
+				dispatch, due = jobs, next.target
+			}
+		}
+
+		select {
+		case dispatch <- due:
+			queue.pop()
+			inFlight++
+		case res := <-results:
+			inFlight--
+			collect(res)
+			queue.reschedule(res.target, time.Now())
+		case targets := <-m.updates:
+			added, changed, removed := queue.apply(m.withDefaults(targets), time.Now())
+			// Forget the removed targets
+			known := func(id string) bool {
+				_, found := queue.targets[id]
+				return found
+			}
+			for id := range states {
+				if !known(id) {
+					delete(states, id)
+				}
+			}
+			expiry.retain(known)
+			m.metrics.retain(known)
+			m.board.retain(known)
+			m.notify(fmt.Sprintf("Targets updated: %d added, %d changed, %d removed", added, changed, removed))
+		case <-timer.C:
+		case <-ctx.Done():
+			running = false
+		}
+	}
+
+	// Drain: Collect the checks in flight, without scheduling new ones.
+	// Each target goes back in the queue, so the last queue depth counts every target
+	close(jobs)
+	for ; inFlight > 0; inFlight-- {
+		res := <-results
+		collect(res)
+		queue.reschedule(res.target, time.Now())
+	}
+	workers.Wait()
+	m.metrics.setScheduler(queue.len(), 0)
+
+	summary.duration = time.Since(summary.started)
+	return summary
+}
+
+// monitor.validate()
+// Receiver Function that checks the interval and the jitter of the monitor, before it runs.
+func (m *monitor) validate() error {
+	if m.interval <= 0 {
+		return fmt.Errorf("interval %v should be positive", m.interval)
+	}
+	// Written so that NaN is refused too
+	if !(m.jitter >= 0 && m.jitter < 1) {
+		return fmt.Errorf("jitter %g should be at least 0 and less than 1", m.jitter)
+	}
+	return nil
+}
+
+// monitor.withDefaults()
+// Receiver Function that gives the interval of the monitor to the targets that do not have their own.
+func (m *monitor) withDefaults(targets []target) []target {
+	filled := make([]target, len(targets))
+	for i, t := range targets {
+		if t.interval <= 0 {
+			t.interval = m.interval
+		}
+		filled[i] = t
+	}
+	return filled
+}
+
+// monitorSummary.add()
+// Receiver Function that counts a result in the summary.
+func (s *monitorSummary) add(res checkResult) {
+	ts, found := s.byId[res.target.id]
+	if !found {
+		ts = &targetSummary{id: res.target.id, verdicts: map[verdict]int{}}
+		s.targets = append(s.targets, ts)
+		s.byId[res.target.id] = ts
+	}
+	s.checks++
+	ts.checks++
+	ts.verdicts[res.verdict]++
+	ts.totalLatency += res.latency
+}
+
+// monitorSummary.toString()
+// Receiver Function to describe the run: One line per target.
+func (s *monitorSummary) toString() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Summary: %d checks and %d alerts in %v\n", s.checks, s.alerts, s.duration.Round(time.Millisecond))
+	for _, ts := range s.targets {
+		average := time.Duration(0)
+		if ts.checks > 0 {
+			average = ts.totalLatency / time.Duration(ts.checks)
+		}
+		fmt.Fprintf(&sb, "  %s: %d checks, %d up, %d degraded, %d down, %v average latency\n",
+			ts.id, ts.checks, ts.verdicts[verdictUp], ts.verdicts[verdictDegraded], ts.verdicts[verdictDown],
+			average.Round(time.Millisecond))
+	}
+	return sb.String()
+}
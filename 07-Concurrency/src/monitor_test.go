@@ -0,0 +1,152 @@
+/**
+ * @file: Unit tests for the URL monitor
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"context"
+	"math"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Test Cases for monitor.run()
+// ****************************
+//   - Targets should be checked again every interval until the monitor stops
+//   - A hung server should not outlive its target's timeout
+//   - Checks in flight when the monitor stops should be drained, not canceled
+//   - The result of a target removed during its check should be counted, and then dropped
+//   - The result of a target removed and added again with another URL during its check should be dropped too,
+//     and counted under the id of the target
+
+func Test_monitorRun(t *testing.T) {
+	// TEST CASE 1: Repeated checks until stopped
+	// ------------------------------------------
+	fast := newStatusServer(t, http.StatusOK, 0)
+	hung := newStatusServer(t, http.StatusOK, time.Hour)
+	hungTarget := newTarget(hung.URL)
+	hungTarget.timeout = 50 * time.Millisecond
+
+	m := newMonitor([]target{newTarget(fast.URL), hungTarget})
+	m.interval = 20 * time.Millisecond
+	var reported atomic.Int32
+	m.report = func(res checkResult) { reported.Add(1) }
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	summary := m.run(ctx)
+	up := summary.byId[fast.URL]
+	if up.checks < 3 || up.verdicts[verdictUp] != up.checks {
+		t.Errorf("Test Case 1: Expected several up checks. Got %d checks: %v", up.checks, up.verdicts)
+	}
+	if int(reported.Load()) != summary.checks {
+		t.Errorf("Test Case 1: Expected every check to be reported. Got %d reports for %d checks", reported.Load(), summary.checks)
+	}
+
+	// TEST CASE 2: Hung server
+	// ------------------------
+	down := summary.byId[hung.URL]
+	if down.checks < 1 || down.verdicts[verdictDown] != down.checks {
+		t.Errorf("Test Case 2: Expected the hung server to time out. Got %d checks: %v", down.checks, down.verdicts)
+	}
+	if summary.duration > time.Second {
+		t.Errorf("Test Case 2: Expected the run to stop soon after 200ms. Got %v", summary.duration)
+	}
+
+	// TEST CASE 3: Drain
+	// ------------------
+	slow := newStatusServer(t, http.StatusOK, 100*time.Millisecond)
+	m = newMonitor([]target{newTarget(slow.URL)})
+	m.report = func(res checkResult) {}
+	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	summary = m.run(ctx)
+	if summary.checks != 1 || summary.byId[slow.URL].verdicts[verdictUp] != 1 {
+		t.Errorf("Test Case 3: Expected the check in flight to finish up. Got %s", summary.toString())
+	}
+
+	// TEST CASE 4: Removed during its check
+	// -------------------------------------
+	m = newMonitor([]target{newTarget(slow.URL)})
+	m.report = func(res checkResult) {}
+	m.notify = func(msg string) {}
+	var recorded atomic.Int32
+	m.record = func(res checkResult, status targetStatus) { recorded.Add(1) }
+	updates := make(chan []target)
+	m.updates = updates
+	ctx, cancel = context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		updates <- []target{}
+	}()
+	summary = m.run(ctx)
+	if summary.checks != 1 || recorded.Load() != 0 || len(m.metrics.targets) != 0 || len(m.board.targets) != 0 {
+		t.Errorf("Test Case 4: Expected the check counted, then dropped. Got %d checks, %d records, %d metrics and %d boards",
+			summary.checks, recorded.Load(), len(m.metrics.targets), len(m.board.targets))
+	}
+
+	// TEST CASE 5: Removed, then added again with another URL, during its check
+	// -------------------------------------------------------------------------
+	before, after := newTarget(slow.URL), newTarget(fast.URL)
+	before.id, after.id = "service", "service"
+	m = newMonitor([]target{before})
+	m.interval = 50 * time.Millisecond
+	m.report = func(res checkResult) {}
+	m.notify = func(msg string) {}
+	var recordedUrls []string
+	m.record = func(res checkResult, status targetStatus) { recordedUrls = append(recordedUrls, res.target.url) }
+	updates = make(chan []target)
+	m.updates = updates
+	ctx, cancel = context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		updates <- []target{}
+		updates <- []target{after}
+	}()
+	summary = m.run(ctx)
+	if len(recordedUrls) == 0 || slices.Contains(recordedUrls, slow.URL) {
+		t.Errorf("Test Case 5: Expected only the checks of the new URL recorded. Got %v", recordedUrls)
+	}
+	if len(summary.targets) != 1 || summary.byId["service"].checks != len(recordedUrls)+1 {
+		t.Errorf("Test Case 5: Expected every check counted under the id. Got %s", summary.toString())
+	}
+}
+
+// Test Cases for monitor.validate()
+// *********************************
+//   - The default interval and jitter should be valid
+//   - An interval of 0 or less, and a jitter outside [0, 1), should be refused
+
+func Test_monitorValidate(t *testing.T) {
+	tests := []struct {
+		interval time.Duration
+		jitter   float64
+		valid    bool
+	}{
+		{defaultInterval, defaultJitter, true},
+		{time.Millisecond, 0, true},
+		{time.Second, 0.99, true},
+		{0, defaultJitter, false},
+		{-time.Second, defaultJitter, false},
+		{time.Second, -0.1, false},
+		{time.Second, 1, false},
+		{time.Second, math.NaN(), false},
+	}
+	for i, test := range tests {
+		m := newMonitor(nil)
+		m.interval, m.jitter = test.interval, test.jitter
+		if err := m.validate(); (err == nil) != test.valid {
+			t.Errorf("Test Case %d: Expected valid=%t for interval %v and jitter %g. Got %v", i+1, test.valid, test.interval, test.jitter, err)
+		}
+	}
+}
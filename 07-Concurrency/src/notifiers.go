@@ -0,0 +1,251 @@
+/**
+ * @file: Describes the built-in notifiers: Webhook (JSON POST), SMTP email, command hook, file and syslog.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Type Declaration
+// ****************
+
+// A webhookNotifier POSTs each message as JSON to a URL.
+type webhookNotifier struct {
+	url     string
+	headers http.Header
+	client  *http.Client
+}
+
+// A webhookPayload is the JSON body of a webhook.
+type webhookPayload struct {
+	ID         string       `json:"id"`
+	URL        string       `json:"url"`
+	Tags       []string     `json:"tags,omitempty"`
+	From       targetStatus `json:"from"`
+	To         targetStatus `json:"to"`
+	At         time.Time    `json:"at"`
+	StatusCode int          `json:"status_code,omitempty"`
+	Error      string       `json:"error,omitempty"`
+	LatencyMs  int64        `json:"latency_ms"`
+	Attempts   int          `json:"attempts"`
+	Warning    string       `json:"warning,omitempty"`
+	Subject    string       `json:"subject"`
+	Message    string       `json:"message"`
+}
+
+// An smtpNotifier emails each message. It uses STARTTLS when the server offers it.
+type smtpNotifier struct {
+	// host:port of the server
+	addr string
+	from string
+	to   []string
+	// Empty for no authentication
+	username string
+	password string
+}
+
+// A commandNotifier runs a command for each message: The body on its standard input,
+// and the alert in MONITOR_* environment variables.
+type commandNotifier struct {
+	command []string
+}
+
+// A fileNotifier appends each message to a file, on one line.
+type fileNotifier struct {
+	path string
+}
+
+// A syslogNotifier sends each message to a syslog server (RFC 5424), over udp, tcp or a unix socket.
+type syslogNotifier struct {
+	network string
+	address string
+	tag     string
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// webhookNotifier.send()
+// Receiver Function that POSTs the message as JSON. Any status but 2xx is an error.
+func (n *webhookNotifier) send(ctx context.Context, msg message) error {
+	a := msg.alert
+	payload, err := json.Marshal(webhookPayload{
+		ID: a.ID, URL: a.URL, Tags: a.Tags, From: a.From, To: a.To, At: a.At,
+		StatusCode: a.StatusCode, Error: a.Error, LatencyMs: a.Latency.Milliseconds(), Attempts: a.Attempts,
+		Warning: a.Warning, Subject: msg.subject, Message: msg.body,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	for key, values := range n.headers {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s answered %s", n.url, resp.Status)
+	}
+	return nil
+}
+
+// smtpNotifier.send()
+// Receiver Function that emails the message to every recipient, within the deadline of ctx.
+func (n *smtpNotifier) send(ctx context.Context, msg message) error {
+	host, _, err := net.SplitHostPort(n.addr)
+	if err != nil {
+		return err
+	}
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", n.addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if n.username != "" {
+		// PlainAuth only sends the password over TLS, or to localhost
+		if err := client.Auth(smtp.PlainAuth("", n.username, n.password, host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(n.from); err != nil {
+		return err
+	}
+	for _, to := range n.to {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	var mail strings.Builder
+	fmt.Fprintf(&mail, "From: %s\r\n", n.from)
+	fmt.Fprintf(&mail, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&mail, "Subject: %s\r\n", msg.subject)
+	fmt.Fprintf(&mail, "Date: %s\r\n", msg.alert.At.Format(time.RFC1123Z))
+	fmt.Fprintf(&mail, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	mail.WriteString(strings.ReplaceAll(msg.body, "\n", "\r\n"))
+	mail.WriteString("\r\n")
+	if _, err := w.Write([]byte(mail.String())); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// commandNotifier.send()
+// Receiver Function that runs the command, and waits for it. A command that fails is an error, with its output.
+func (n *commandNotifier) send(ctx context.Context, msg message) error {
+	a := msg.alert
+	cmd := exec.CommandContext(ctx, n.command[0], n.command[1:]...)
+	cmd.Env = append(os.Environ(),
+		"MONITOR_ID="+a.ID,
+		"MONITOR_URL="+a.URL,
+		"MONITOR_FROM="+string(a.From),
+		"MONITOR_TO="+string(a.To),
+		"MONITOR_AT="+a.At.Format(time.RFC3339),
+		"MONITOR_WARNING="+a.Warning,
+		"MONITOR_SUBJECT="+msg.subject,
+	)
+	cmd.Stdin = strings.NewReader(msg.body)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", n.command[0], err, bytes.TrimSpace(output))
+	}
+	return nil
+}
+
+// fileNotifier.send()
+// Receiver Function that appends the message to the file: Its time, its subject and its body on one line.
+func (n *fileNotifier) send(ctx context.Context, msg message) error {
+	f, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	body := strings.NewReplacer("\r", " ", "\n", " ").Replace(msg.body)
+	_, err = fmt.Fprintf(f, "%s %s: %s\n", msg.alert.At.Format(time.RFC3339), msg.subject, body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// syslogNotifier.send()
+// Receiver Function that sends the message to syslog, from the daemon facility.
+// Its severity follows the alert: err when down, warning when flapping or for a warning, notice otherwise.
+func (n *syslogNotifier) send(ctx context.Context, msg message) error {
+	severity := 5
+	switch msg.alert.To {
+	case statusDown:
+		severity = 3
+	case statusFlapping, statusWarning:
+		severity = 4
+	}
+	const facilityDaemon = 3
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+	tag := n.tag
+	if tag == "" {
+		tag = "monitor"
+	}
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, n.network, n.address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	body := strings.NewReplacer("\r", " ", "\n", " ").Replace(msg.body)
+	line := fmt.Sprintf("<%d>1 %s %s %s %d - - %s: %s", facilityDaemon*8+severity,
+		msg.alert.At.Format(time.RFC3339), hostname, tag, os.Getpid(), msg.subject, body)
+	// A stream needs a frame around each message (RFC 6587): Its length first
+	if n.network == "tcp" || n.network == "unix" {
+		line = fmt.Sprintf("%d %s", len(line), line)
+	}
+	_, err = conn.Write([]byte(line))
+	return err
+}
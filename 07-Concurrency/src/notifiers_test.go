@@ -0,0 +1,228 @@
+/**
+ * @file: Unit tests for the built-in notifiers, against httptest, a fake SMTP server and a UDP listener
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test Helpers
+// ************
+
+// newMessage renders a change of state with the default templates.
+func newMessage(t *testing.T, change stateChange) message {
+	t.Helper()
+	msg, err := newNotifyChannel("test", &fakeNotifier{}).render(newAlertData(change))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+// newFakeSMTPServer starts a local SMTP server that takes any mail, and sends its sender,
+// recipients and data on the channel. It offers neither STARTTLS nor AUTH.
+func newFakeSMTPServer(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	mails := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				var mail strings.Builder
+				fmt.Fprintf(conn, "220 localhost fake SMTP\r\n")
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					command := strings.ToUpper(strings.Fields(line + " ")[0])
+					switch command {
+					case "EHLO", "HELO":
+						fmt.Fprintf(conn, "250-localhost\r\n250 8BITMIME\r\n")
+					case "MAIL", "RCPT":
+						mail.WriteString(line)
+						fmt.Fprintf(conn, "250 OK\r\n")
+					case "DATA":
+						fmt.Fprintf(conn, "354 End data with <CR><LF>.<CR><LF>\r\n")
+						for {
+							line, err := r.ReadString('\n')
+							if err != nil || line == ".\r\n" {
+								break
+							}
+							mail.WriteString(line)
+						}
+						fmt.Fprintf(conn, "250 OK\r\n")
+						mails <- mail.String()
+					case "QUIT":
+						fmt.Fprintf(conn, "221 Bye\r\n")
+						return
+					default:
+						fmt.Fprintf(conn, "502 Not implemented\r\n")
+					}
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String(), mails
+}
+
+// Test Cases for webhookNotifier
+// ******************************
+//   - The alert should be POSTed as JSON, with the headers of the notifier
+//   - A status other than 2xx should be an error
+
+func Test_webhookNotifier(t *testing.T) {
+	// TEST CASE 1: JSON POST
+	// ----------------------
+	var got webhookPayload
+	var method, auth, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, auth, contentType = r.Method, r.Header.Get("Authorization"), r.Header.Get("Content-Type")
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	t.Cleanup(server.Close)
+	n := &webhookNotifier{url: server.URL, headers: http.Header{"Authorization": {"Bearer x"}}, client: http.DefaultClient}
+	if err := n.send(context.Background(), newMessage(t, newChange("api", statusDown, "prod"))); err != nil {
+		t.Fatalf("Test Case 1: Expected the webhook to be sent. Got %v", err)
+	}
+	if method != http.MethodPost || auth != "Bearer x" || contentType != "application/json" {
+		t.Errorf("Test Case 1: Expected a JSON POST with the headers. Got %s, %q, %q", method, auth, contentType)
+	}
+	if got.ID != "api" || got.To != statusDown || got.From != statusUp || got.StatusCode != 503 || got.Attempts != 3 ||
+		got.Tags[0] != "prod" || got.Subject != "[down] api is down" || !strings.Contains(got.Message, "status 503") {
+		t.Errorf("Test Case 1: Expected the alert in the payload. Got %+v", got)
+	}
+
+	// TEST CASE 2: Error status
+	// -------------------------
+	failing := newStatusServer(t, http.StatusInternalServerError, 0)
+	n = &webhookNotifier{url: failing.URL, client: http.DefaultClient}
+	if err := n.send(context.Background(), newMessage(t, newChange("api", statusDown))); err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("Test Case 2: Expected an error with the status. Got %v", err)
+	}
+}
+
+// Test Cases for smtpNotifier
+// ***************************
+//   - The mail should reach every recipient, with its subject and body
+
+func Test_smtpNotifier(t *testing.T) {
+	// TEST CASE 1: Mail
+	// -----------------
+	addr, mails := newFakeSMTPServer(t)
+	n := &smtpNotifier{addr: addr, from: "monitor@example.com", to: []string{"a@example.com", "b@example.com"}}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := n.send(ctx, newMessage(t, newChange("api", statusDown))); err != nil {
+		t.Fatalf("Test Case 1: Expected the mail to be sent. Got %v", err)
+	}
+	select {
+	case mail := <-mails:
+		for _, want := range []string{"MAIL FROM:<monitor@example.com>", "RCPT TO:<a@example.com>", "RCPT TO:<b@example.com>",
+			"Subject: [down] api is down\r\n", "api (https://api.example.com) is down"} {
+			if !strings.Contains(mail, want) {
+				t.Errorf("Test Case 1: Expected %q in the mail. Got %q", want, mail)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Test Case 1: Expected a mail. Got nothing")
+	}
+}
+
+// Test Cases for commandNotifier, fileNotifier and syslogNotifier
+// ***************************************************************
+//   - The command should get the body on its input, and the alert in its environment
+//   - A failing command should be an error, with its output
+//   - Each message should be appended to the file on one line
+//   - Syslog should get one RFC 5424 message, with the severity of the alert
+
+func Test_localNotifiers(t *testing.T) {
+	msg := newMessage(t, newChange("api", statusDown))
+	dir := t.TempDir()
+
+	// TEST CASE 1: Command
+	// --------------------
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Log("Test Case 1: Skipped without sh")
+	} else {
+		out := filepath.Join(dir, "command.out")
+		n := &commandNotifier{command: []string{"sh", "-c", `{ echo "$MONITOR_ID $MONITOR_TO"; cat; } > "$0"`, out}}
+		if err := n.send(context.Background(), msg); err != nil {
+			t.Fatalf("Test Case 1: Expected the command to run. Got %v", err)
+		}
+		if got, _ := os.ReadFile(out); !strings.HasPrefix(string(got), "api down\napi (https://api.example.com) is down") {
+			t.Errorf("Test Case 1: Expected the alert from the environment and the body. Got %q", got)
+		}
+
+		// TEST CASE 2: Failing command
+		// ----------------------------
+		n = &commandNotifier{command: []string{"sh", "-c", "echo oops; exit 3"}}
+		if err := n.send(context.Background(), msg); err == nil || !strings.Contains(err.Error(), "oops") {
+			t.Errorf("Test Case 2: Expected an error with the output. Got %v", err)
+		}
+	}
+
+	// TEST CASE 3: File
+	// -----------------
+	path := filepath.Join(dir, "alerts.log")
+	n := &fileNotifier{path: path}
+	n.send(context.Background(), msg)
+	n.send(context.Background(), newMessage(t, newChange("api", statusUp)))
+	got, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSuffix(string(got), "\n"), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "[down] api is down: api") || !strings.Contains(lines[1], "[up] api is up") {
+		t.Errorf("Test Case 3: Expected 2 lines. Got %q", got)
+	}
+
+	// TEST CASE 4: Syslog
+	// -------------------
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	s := &syslogNotifier{network: "udp", address: listener.LocalAddr().String(), tag: "monitor"}
+	if err := s.send(context.Background(), msg); err != nil {
+		t.Fatalf("Test Case 4: Expected the message to be sent. Got %v", err)
+	}
+	listener.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 4096)
+	size, _, err := listener.ReadFrom(buf)
+	if err != nil && err != io.EOF {
+		t.Fatalf("Test Case 4: Expected a message. Got %v", err)
+	}
+	// daemon (3) * 8 + err (3)
+	if line := string(buf[:size]); !strings.HasPrefix(line, "<27>1 ") || !strings.Contains(line, " monitor ") ||
+		!strings.Contains(line, "[down] api is down: api") {
+		t.Errorf("Test Case 4: Expected an RFC 5424 message. Got %q", line)
+	}
+}
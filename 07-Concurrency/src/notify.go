@@ -0,0 +1,267 @@
+/**
+ * @file: Describes how alerts reach people: Notifiers, and the dispatcher that routes each alert to them.
+ *
+ * Each channel of the dispatcher is a notifier with its own rules:
+ *   - Routing: Which targets (by id or tag) and which states (or warnings) it cares about
+ *   - Templates: The subject and the body of its messages (text/template, fed with an alertData)
+ *   - Deduplication: The same alert for the same target is sent once within the dedup window
+ *   - Rate limit: At most so many messages per period, whatever the target
+ *
+ * The dispatcher sends from its own goroutine: A slow notifier never holds up the monitor.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Constants
+// *********
+
+// Default templates of a message.
+const (
+	defaultSubjectTemplate = `[{{.To}}] {{.ID}} {{if .Warning}}has a warning{{else}}is {{.To}}{{end}}`
+	defaultBodyTemplate    = `{{if .Warning}}{{.ID}} ({{.URL}}): {{.Warning}}{{else}}` +
+		`{{.ID}} ({{.URL}}) is {{.To}}, was {{.From}}, at {{.At.Format "2006-01-02 15:04:05 MST"}}` +
+		`{{if .StatusCode}}, status {{.StatusCode}}{{end}}{{if .Error}}, error: {{.Error}}{{end}}{{end}}`
+)
+
+// Defaults of the dispatcher.
+const (
+	// Alerts waiting to be sent: Any more are dropped, and reported
+	defaultDispatchQueue = 100
+	// Time allowed to each notifier for each message
+	defaultSendTimeout = 10 * time.Second
+)
+
+// Type Declaration
+// ****************
+
+// A notifier delivers messages somewhere: A webhook, a mailbox, a command, a file, syslog...
+type notifier interface {
+	send(ctx context.Context, msg message) error
+}
+
+// An alertData is what templates know about an alert. Its fields are exported for text/template.
+type alertData struct {
+	ID         string
+	URL        string
+	Tags       []string
+	From       targetStatus
+	To         targetStatus
+	At         time.Time
+	StatusCode int
+	Error      string
+	Latency    time.Duration
+	Attempts   int
+	// What a warning is about: Empty for a change of state
+	Warning string
+}
+
+// A message is an alert rendered by the templates of a channel.
+type message struct {
+	alert   alertData
+	subject string
+	body    string
+}
+
+// A notifyChannel is a notifier with its routing, templates, deduplication and rate limit.
+type notifyChannel struct {
+	name     string
+	notifier notifier
+	// Routing: An empty list matches everything
+	targets []string
+	tags    []string
+	on      []targetStatus
+	subject *template.Template
+	body    *template.Template
+	// The same alert for the same target is sent once within dedup: 0 to send them all
+	dedup time.Duration
+	// At most rateCount messages per ratePer: 0 for no limit
+	rateCount int
+	ratePer   time.Duration
+	// Last message of each target and state, and recent messages: Used by the dispatcher only
+	lastSent map[string]time.Time
+	sent     []time.Time
+}
+
+// A dispatcher routes each alert to the channels that want it.
+type dispatcher struct {
+	channels []*notifyChannel
+	queue    chan alertData
+	timeout  time.Duration
+	done     chan struct{}
+	// Called with each error, from the goroutine of the dispatcher or of the caller
+	report func(err error)
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newAlertData()
+// Initializes what templates know about a change of state.
+func newAlertData(change stateChange) alertData {
+	res := change.result
+	data := alertData{
+		ID:         change.target.id,
+		URL:        change.target.url,
+		Tags:       change.target.tags,
+		From:       change.from,
+		To:         change.to,
+		At:         change.at,
+		StatusCode: res.statusCode,
+		Latency:    res.latency,
+		Attempts:   res.attempts,
+		Warning:    change.warning,
+	}
+	if res.err != nil {
+		data.Error = res.err.Error()
+	}
+	return data
+}
+
+// newNotifyChannel()
+// Initializes a channel of the notifier with the default templates, sending every alert without limit.
+func newNotifyChannel(name string, n notifier) *notifyChannel {
+	return &notifyChannel{
+		name:     name,
+		notifier: n,
+		subject:  template.Must(template.New("subject").Parse(defaultSubjectTemplate)),
+		body:     template.Must(template.New("body").Parse(defaultBodyTemplate)),
+		lastSent: map[string]time.Time{},
+	}
+}
+
+// newDispatcher()
+// Initializes a dispatcher to the channels. It sends nothing until started.
+func newDispatcher(channels []*notifyChannel) *dispatcher {
+	return &dispatcher{
+		channels: channels,
+		queue:    make(chan alertData, defaultDispatchQueue),
+		timeout:  defaultSendTimeout,
+		done:     make(chan struct{}),
+		report:   func(err error) { fmt.Println(err) },
+	}
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// notifyChannel.matches()
+// Receiver Function that tells if the channel wants the alert: Its target by id or tag, and its new state.
+func (c *notifyChannel) matches(a alertData) bool {
+	if len(c.on) > 0 && !slices.Contains(c.on, a.To) {
+		return false
+	}
+	if len(c.targets) == 0 && len(c.tags) == 0 {
+		return true
+	}
+	if slices.Contains(c.targets, a.ID) {
+		return true
+	}
+	return slices.ContainsFunc(a.Tags, func(tag string) bool { return slices.Contains(c.tags, tag) })
+}
+
+// notifyChannel.allow()
+// Receiver Function that tells if the alert can be sent now, and counts it if so.
+// Returns why not otherwise: A duplicate, or over the rate limit.
+func (c *notifyChannel) allow(a alertData, now time.Time) (bool, string) {
+	key := a.ID + " " + string(a.To)
+	if last, found := c.lastSent[key]; found && c.dedup > 0 && now.Sub(last) < c.dedup {
+		return false, "duplicate"
+	}
+	if c.rateCount > 0 {
+		// Forget the messages older than the period
+		recent := 0
+		for recent < len(c.sent) && now.Sub(c.sent[recent]) >= c.ratePer {
+			recent++
+		}
+		c.sent = c.sent[recent:]
+		if len(c.sent) >= c.rateCount {
+			return false, "rate limited"
+		}
+		c.sent = append(c.sent, now)
+	}
+	c.lastSent[key] = now
+	return true, ""
+}
+
+// notifyChannel.render()
+// Receiver Function that renders the alert with the templates of the channel.
+// The subject is kept on one line: It may end up in a mail header.
+func (c *notifyChannel) render(a alertData) (message, error) {
+	var subject, body strings.Builder
+	if err := c.subject.Execute(&subject, a); err != nil {
+		return message{}, err
+	}
+	if err := c.body.Execute(&body, a); err != nil {
+		return message{}, err
+	}
+	oneLine := strings.NewReplacer("\r", " ", "\n", " ").Replace(subject.String())
+	return message{alert: a, subject: oneLine, body: body.String()}, nil
+}
+
+// dispatcher.start()
+// Receiver Function that starts sending the alerts, one at a time, until stopped.
+func (d *dispatcher) start() {
+	go func() {
+		defer close(d.done)
+		for a := range d.queue {
+			d.deliver(a, time.Now())
+		}
+	}()
+}
+
+// dispatcher.dispatch()
+// Receiver Function that queues an alert to send. It never blocks: When the queue is full, the alert is dropped.
+func (d *dispatcher) dispatch(change stateChange) {
+	select {
+	case d.queue <- newAlertData(change):
+	default:
+		d.report(fmt.Errorf("notify: queue full, dropped the alert of %s (%s)", change.target.id, change.to))
+	}
+}
+
+// dispatcher.stop()
+// Receiver Function that stops taking alerts, and waits for the queued ones to be sent, up to timeout.
+func (d *dispatcher) stop(timeout time.Duration) {
+	close(d.queue)
+	select {
+	case <-d.done:
+	case <-time.After(timeout):
+		d.report(fmt.Errorf("notify: gave up on the alerts left after %v", timeout))
+	}
+}
+
+// dispatcher.deliver()
+// Receiver Function that sends an alert to every channel that wants it and allows it.
+func (d *dispatcher) deliver(a alertData, now time.Time) {
+	for _, c := range d.channels {
+		if !c.matches(a) {
+			continue
+		}
+		if ok, why := c.allow(a, now); !ok {
+			d.report(fmt.Errorf("notify %s: skipped the alert of %s (%s): %s", c.name, a.ID, a.To, why))
+			continue
+		}
+		msg, err := c.render(a)
+		if err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+			err = c.notifier.send(ctx, msg)
+			cancel()
+		}
+		if err != nil {
+			d.report(fmt.Errorf("notify %s: %w", c.name, err))
+		}
+	}
+}
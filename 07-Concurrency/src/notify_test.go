@@ -0,0 +1,181 @@
+/**
+ * @file: Unit tests for alerts: Routing, templates, deduplication, rate limits and the dispatcher
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"text/template"
+	"time"
+)
+
+// Test Helpers
+// ************
+
+// A fakeNotifier records the messages it is sent, and fails when told to.
+type fakeNotifier struct {
+	mu       sync.Mutex
+	messages []message
+	err      error
+}
+
+func (n *fakeNotifier) send(ctx context.Context, msg message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.messages = append(n.messages, msg)
+	return n.err
+}
+
+// subjects returns the subjects of the messages sent so far.
+func (n *fakeNotifier) subjects() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var subjects []string
+	for _, msg := range n.messages {
+		subjects = append(subjects, msg.subject)
+	}
+	return subjects
+}
+
+// newChange returns a change of state of a target with tags.
+func newChange(id string, to targetStatus, tags ...string) stateChange {
+	tg := newTarget("https://" + id + ".example.com")
+	tg.id, tg.tags = id, tags
+	res := checkResult{target: tg, statusCode: 503, verdict: verdictDown, attempts: 3}
+	return stateChange{target: tg, from: statusUp, to: to, at: time.Now(), result: res}
+}
+
+// Test Cases for notifyChannel
+// ****************************
+//   - A channel should only take the alerts of its targets, tags and states
+//   - The same alert should be sent once within the dedup window
+//   - The rate limit should hold whatever the target
+//   - Templates should render the alert, or the warning, with the subject on one line
+
+func Test_notifyChannel(t *testing.T) {
+	now := time.Now()
+
+	// TEST CASE 1: Routing
+	// --------------------
+	c := newNotifyChannel("ops", &fakeNotifier{})
+	c.targets, c.tags, c.on = []string{"api"}, []string{"prod"}, []targetStatus{statusDown}
+	for _, test := range []struct {
+		change stateChange
+		want   bool
+	}{
+		{newChange("api", statusDown), true},
+		{newChange("web", statusDown, "dev", "prod"), true},
+		{newChange("web", statusDown, "dev"), false},
+		{newChange("api", statusUp), false},
+	} {
+		if got := c.matches(newAlertData(test.change)); got != test.want {
+			t.Errorf("Test Case 1: Expected %v for %s %s %v. Got %v", test.want, test.change.target.id, test.change.to, test.change.target.tags, got)
+		}
+	}
+	if !newNotifyChannel("all", &fakeNotifier{}).matches(newAlertData(newChange("web", statusUp))) {
+		t.Errorf("Test Case 1: Expected a channel without routing to take every alert")
+	}
+
+	// TEST CASE 2: Deduplication
+	// --------------------------
+	c = newNotifyChannel("ops", &fakeNotifier{})
+	c.dedup = 10 * time.Minute
+	down := newAlertData(newChange("api", statusDown))
+	for i, test := range []struct {
+		a    alertData
+		at   time.Time
+		want bool
+	}{
+		{down, now, true},
+		{down, now.Add(time.Minute), false},
+		{newAlertData(newChange("api", statusUp)), now.Add(time.Minute), true},
+		{newAlertData(newChange("web", statusDown)), now.Add(time.Minute), true},
+		{down, now.Add(11 * time.Minute), true},
+	} {
+		if got, why := c.allow(test.a, test.at); got != test.want {
+			t.Errorf("Test Case 2: Expected alert %d allowed: %v. Got %v (%s)", i+1, test.want, got, why)
+		}
+	}
+
+	// TEST CASE 3: Rate limit
+	// -----------------------
+	c = newNotifyChannel("ops", &fakeNotifier{})
+	c.rateCount, c.ratePer = 2, time.Hour
+	var allowed []bool
+	for i, at := range []time.Duration{0, time.Minute, 2 * time.Minute, 61 * time.Minute} {
+		ok, _ := c.allow(newAlertData(newChange(fmt.Sprint("t", i), statusDown)), now.Add(at))
+		allowed = append(allowed, ok)
+	}
+	if fmt.Sprint(allowed) != "[true true false true]" {
+		t.Errorf("Test Case 3: Expected 2 alerts per hour. Got %v", allowed)
+	}
+
+	// TEST CASE 4: Templates
+	// ----------------------
+	c = newNotifyChannel("ops", &fakeNotifier{})
+	msg, err := c.render(down)
+	if err != nil || msg.subject != "[down] api is down" || !strings.Contains(msg.body, "api (https://api.example.com) is down, was up") ||
+		!strings.Contains(msg.body, "status 503") {
+		t.Errorf("Test Case 4: Expected the default templates. Got %q / %q (%v)", msg.subject, msg.body, err)
+	}
+	warning := newChange("api", statusWarning)
+	warning.warning = "The certificate of api expires in 10 days"
+	if msg, _ := c.render(newAlertData(warning)); msg.subject != "[warning] api has a warning" ||
+		msg.body != "api (https://api.example.com): The certificate of api expires in 10 days" {
+		t.Errorf("Test Case 4: Expected the default templates of a warning. Got %q / %q", msg.subject, msg.body)
+	}
+	c.subject = template.Must(template.New("subject").Parse("{{.ID}}\n{{.To}} after {{.Attempts}} attempts"))
+	if msg, _ := c.render(down); msg.subject != "api down after 3 attempts" {
+		t.Errorf("Test Case 4: Expected a custom subject on one line. Got %q", msg.subject)
+	}
+}
+
+// Test Cases for dispatcher
+// *************************
+//   - Each alert should reach every channel that wants it, in order, even when stopped right away
+//   - A failing notifier should be reported, without holding up the others
+
+func Test_dispatcher(t *testing.T) {
+	all, prod, failing := &fakeNotifier{}, &fakeNotifier{}, &fakeNotifier{err: errors.New("unreachable")}
+	prodChannel := newNotifyChannel("prod", prod)
+	prodChannel.tags = []string{"prod"}
+	d := newDispatcher([]*notifyChannel{newNotifyChannel("failing", failing), newNotifyChannel("all", all), prodChannel})
+	var mu sync.Mutex
+	var reported []error
+	d.report = func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, err)
+	}
+
+	// TEST CASE 1: Routing
+	// --------------------
+	d.start()
+	d.dispatch(newChange("api", statusDown, "prod"))
+	d.dispatch(newChange("web", statusDown))
+	d.dispatch(newChange("api", statusUp, "prod"))
+	// Stop right away: The queued alerts are still sent
+	d.stop(time.Second)
+	if got := all.subjects(); fmt.Sprint(got) != "[[down] api is down [down] web is down [up] api is up]" {
+		t.Errorf("Test Case 1: Expected every alert in order. Got %v", got)
+	}
+	if got := prod.subjects(); len(got) != 2 {
+		t.Errorf("Test Case 1: Expected the 2 alerts of prod. Got %v", got)
+	}
+
+	// TEST CASE 2: Failing notifier
+	// -----------------------------
+	if len(failing.subjects()) != 3 || len(reported) != 3 || !strings.Contains(reported[0].Error(), "notify failing: unreachable") {
+		t.Errorf("Test Case 2: Expected 3 failures reported. Got %v", reported)
+	}
+}
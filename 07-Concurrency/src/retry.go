@@ -0,0 +1,90 @@
+/**
+ * @file: Describes the retries of a check: A failed check is tried again after an exponential backoff,
+ * before it counts as a failure.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// Constants
+// *********
+
+// Defaults of a retry policy.
+const (
+	defaultRetryAttempts   = 2
+	defaultRetryBackoff    = 250 * time.Millisecond
+	defaultRetryMaxBackoff = 5 * time.Second
+)
+
+// Type Declaration
+// ****************
+
+// A retryPolicy tells how many times a failed check is tried again, and how long to wait before each retry.
+// The wait doubles after each retry, up to maxBackoff.
+type retryPolicy struct {
+	attempts   int
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newRetryPolicy()
+// Initializes the default policy: 2 retries, after about 250ms then 500ms.
+func newRetryPolicy() retryPolicy {
+	return retryPolicy{attempts: defaultRetryAttempts, backoff: defaultRetryBackoff, maxBackoff: defaultRetryMaxBackoff}
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// retryPolicy.delay()
+// Receiver Function that gives the wait before a retry (1 for the first one): backoff * 2^(retry-1), up to maxBackoff.
+// The jitter keeps a random half of it, so that targets failing together do not retry together.
+func (p retryPolicy) delay(retry int) time.Duration {
+	d := p.backoff
+	for i := 1; i < retry && (p.maxBackoff <= 0 || d < p.maxBackoff); i++ {
+		d *= 2
+	}
+	if p.maxBackoff > 0 {
+		d = min(d, p.maxBackoff)
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// Helper Functions
+// ****************
+
+// checkWithRetries()
+// Checks a target, and tries again while it is down, as many times as its retry policy allows.
+// Returns the last result. Each attempt runs to its end even if ctx is done, but no retry starts after that.
+func checkWithRetries(ctx context.Context, client *http.Client, t target) checkResult {
+	checkCtx := context.WithoutCancel(ctx)
+	res := checkTarget(checkCtx, client, t)
+	for retry := 1; res.verdict == verdictDown && retry <= t.retry.attempts; retry++ {
+		timer := time.NewTimer(t.retry.delay(retry))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return res
+		case <-timer.C:
+		}
+		res = checkTarget(checkCtx, client, t)
+		res.attempts = retry + 1
+	}
+	return res
+}
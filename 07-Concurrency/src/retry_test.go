@@ -0,0 +1,90 @@
+/**
+ * @file: Unit tests for the retries of a check
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Test Cases for retryPolicy.delay()
+// **********************************
+//   - The delay should double after each retry, within its jitter
+//   - The delay should stop growing at maxBackoff
+
+func Test_retryPolicyDelay(t *testing.T) {
+	policy := retryPolicy{attempts: 10, backoff: 100 * time.Millisecond, maxBackoff: time.Second}
+
+	// TEST CASE 1: Exponential backoff with jitter
+	// --------------------------------------------
+	for retry, full := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond} {
+		for range 100 {
+			if d := policy.delay(retry); d < full/2 || d > full {
+				t.Fatalf("Test Case 1: Expected retry %d within [%v, %v]. Got %v", retry, full/2, full, d)
+			}
+		}
+	}
+
+	// TEST CASE 2: Capped
+	// -------------------
+	for range 100 {
+		if d := policy.delay(30); d < 500*time.Millisecond || d > time.Second {
+			t.Fatalf("Test Case 2: Expected retry 30 within [500ms, 1s]. Got %v", d)
+		}
+	}
+}
+
+// Test Cases for checkWithRetries()
+// *********************************
+//   - A target failing twice then answering should be up on the third attempt
+//   - A target failing every time should be down after all its attempts
+//   - No retry should start once the monitor stops
+
+func Test_checkWithRetries(t *testing.T) {
+	// A server that fails its first requests
+	var requests, failures atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(server.Close)
+	tg := newTarget(server.URL)
+	tg.retry = retryPolicy{attempts: 2, backoff: 10 * time.Millisecond, maxBackoff: time.Second}
+
+	// TEST CASE 1: Up after retries
+	// -----------------------------
+	failures.Store(2)
+	if res := checkWithRetries(context.Background(), http.DefaultClient, tg); res.verdict != verdictUp || res.attempts != 3 {
+		t.Errorf("Test Case 1: Expected up on attempt 3. Got %s", res.toString())
+	}
+
+	// TEST CASE 2: Down after every attempt
+	// -------------------------------------
+	requests.Store(0)
+	failures.Store(100)
+	if res := checkWithRetries(context.Background(), http.DefaultClient, tg); res.verdict != verdictDown || res.attempts != 3 || requests.Load() != 3 {
+		t.Errorf("Test Case 2: Expected down on attempt 3, after 3 requests. Got %s after %d requests", res.toString(), requests.Load())
+	}
+
+	// TEST CASE 3: Stopped monitor
+	// ----------------------------
+	requests.Store(0)
+	tg.retry.backoff = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	if res := checkWithRetries(ctx, http.DefaultClient, tg); res.verdict != verdictDown || res.attempts != 1 || time.Since(start) > time.Second {
+		t.Errorf("Test Case 3: Expected one attempt, without waiting. Got %s in %v", res.toString(), time.Since(start))
+	}
+}
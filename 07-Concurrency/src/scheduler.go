@@ -0,0 +1,196 @@
+/**
+ * @file: Describes the scheduler of the monitor: A priority queue of targets keyed by their next run time.
+ *
+ * The scheduler only decides when each target is due. A fixed pool of workers runs the checks,
+ * so the number of goroutines does not grow with the number of targets.
+ * It also keeps the current version of each target, so that a new config applies without a restart.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"container/heap"
+	"math/rand"
+	"reflect"
+	"time"
+)
+
+// Constants
+// *********
+
+const (
+	// Default number of workers running checks at the same time
+	defaultWorkers = 16
+	// Default jitter: Each interval is stretched or shrunk by up to 10% at random
+	defaultJitter = 0.1
+)
+
+// Type Declaration
+// ****************
+
+// A job is a target waiting in the queue for its next run.
+type job struct {
+	target target
+	next   time.Time
+	// Position in the heap, maintained by jobQueue
+	index int
+}
+
+// A jobQueue is a min-heap of jobs by next run time. Use it through container/heap.
+type jobQueue []*job
+
+// A scheduler holds the jobs of the monitor, and tells which one is due next.
+type scheduler struct {
+	queue jobQueue
+	// Current version of every target, by id: Queued or in flight
+	targets map[string]target
+	// Queued jobs, by target id
+	queued map[string]*job
+	// Ids of the targets popped and not rescheduled yet: Their check is in flight
+	inFlight map[string]bool
+	jitter   float64
+	randGen  *rand.Rand
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newScheduler()
+// Initializes a scheduler with every target due now. The workers take them in turn,
+// so the next runs spread out on their own.
+func newScheduler(targets []target, jitter float64, now time.Time) *scheduler {
+	s := &scheduler{
+		queue:    make(jobQueue, 0, len(targets)),
+		targets:  make(map[string]target, len(targets)),
+		queued:   make(map[string]*job, len(targets)),
+		inFlight: map[string]bool{},
+		jitter:   jitter,
+		randGen:  rand.New(rand.NewSource(now.UnixNano())),
+	}
+	s.apply(targets, now)
+	return s
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// scheduler.peek()
+// Receiver Function that returns the job due next, without removing it. Returns nil if the queue is empty.
+func (s *scheduler) peek() *job {
+	if len(s.queue) == 0 {
+		return nil
+	}
+	return s.queue[0]
+}
+
+// scheduler.pop()
+// Receiver Function that removes the job due next from the queue, and returns its target: In flight until rescheduled.
+func (s *scheduler) pop() target {
+	j := heap.Pop(&s.queue).(*job)
+	delete(s.queued, j.target.id)
+	s.inFlight[j.target.id] = true
+	return j.target
+}
+
+// scheduler.reschedule()
+// Receiver Function that puts a checked target back in the queue, for one interval after now, give or take the jitter.
+// The current version of the target is queued: It may have changed during the check, been removed, or been
+// removed and added again.
+func (s *scheduler) reschedule(t target, now time.Time) {
+	delete(s.inFlight, t.id)
+	current, found := s.targets[t.id]
+	if _, queued := s.queued[t.id]; !found || queued {
+		return
+	}
+	j := &job{target: current, next: now.Add(s.nextInterval(current.interval))}
+	heap.Push(&s.queue, j)
+	s.queued[current.id] = j
+}
+
+// scheduler.apply()
+// Receiver Function that replaces the targets with a new set, by id: New ones are due now,
+// changed ones keep their place (sooner if their interval is now shorter), and removed ones leave the queue.
+// A target in flight picks up its new version once checked, even if it was removed and is now added again:
+// It is never queued while in flight.
+func (s *scheduler) apply(targets []target, now time.Time) (added, changed, removed int) {
+	keep := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		keep[t.id] = true
+		old, found := s.targets[t.id]
+		s.targets[t.id] = t
+		switch {
+		case !found:
+			if !s.inFlight[t.id] {
+				j := &job{target: t, next: now}
+				heap.Push(&s.queue, j)
+				s.queued[t.id] = j
+			}
+			added++
+		case !reflect.DeepEqual(old, t):
+			if j, queued := s.queued[t.id]; queued {
+				j.target = t
+				if soonest := now.Add(t.interval); soonest.Before(j.next) {
+					j.next = soonest
+					heap.Fix(&s.queue, j.index)
+				}
+			}
+			changed++
+		}
+	}
+	for id := range s.targets {
+		if keep[id] {
+			continue
+		}
+		delete(s.targets, id)
+		if j, queued := s.queued[id]; queued {
+			heap.Remove(&s.queue, j.index)
+			delete(s.queued, id)
+		}
+		removed++
+	}
+	return added, changed, removed
+}
+
+// scheduler.nextInterval()
+// Receiver Function that stretches or shrinks the interval at random, by up to the jitter.
+func (s *scheduler) nextInterval(interval time.Duration) time.Duration {
+	factor := 1 + s.jitter*(2*s.randGen.Float64()-1)
+	return time.Duration(factor * float64(interval))
+}
+
+// scheduler.len()
+// Receiver Function that returns the number of targets waiting in the queue.
+func (s *scheduler) len() int {
+	return len(s.queue)
+}
+
+// heap.Interface of jobQueue
+
+func (q jobQueue) Len() int           { return len(q) }
+func (q jobQueue) Less(i, j int) bool { return q[i].next.Before(q[j].next) }
+
+func (q jobQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *jobQueue) Push(x any) {
+	j := x.(*job)
+	j.index = len(*q)
+	*q = append(*q, j)
+}
+
+func (q *jobQueue) Pop() any {
+	old := *q
+	n := len(old)
+	j := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	j.index = -1
+	return j
+}
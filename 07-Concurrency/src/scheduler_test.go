@@ -0,0 +1,128 @@
+/**
+ * @file: Unit tests for the scheduler and the worker pool of the monitor
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// Test Cases for scheduler
+// ************************
+//   - Targets should come out of the queue by next run time
+//   - The jitter should keep each interval within its bounds
+//   - A target removed during its check should not come back, and one added again should be queued once, as its new version
+
+func Test_scheduler(t *testing.T) {
+	// TEST CASE 1: Order by next run time
+	// -----------------------------------
+	now := time.Now()
+	var targets []target
+	for _, delay := range []int{30, 10, 50, 20, 40} {
+		tg := newTarget(fmt.Sprint(delay))
+		tg.interval = time.Duration(delay) * time.Millisecond
+		targets = append(targets, tg)
+	}
+	// Every target is due now: Check them all, then queue them for their next run
+	s := newScheduler(targets, 0, now)
+	var checked []target
+	for s.len() > 0 {
+		checked = append(checked, s.pop())
+	}
+	for _, tg := range checked {
+		s.reschedule(tg, now)
+	}
+	var got []string
+	for s.len() > 0 {
+		got = append(got, s.pop().url)
+	}
+	if fmt.Sprint(got) != "[10 20 30 40 50]" {
+		t.Errorf("Test Case 1: Expected the targets by next run time. Got %v", got)
+	}
+
+	// TEST CASE 2: Jitter bounds
+	// --------------------------
+	s = newScheduler(nil, 0.1, now)
+	for range 1000 {
+		if d := s.nextInterval(time.Second); d < 900*time.Millisecond || d > 1100*time.Millisecond {
+			t.Fatalf("Test Case 2: Expected an interval within 10%% of 1s. Got %v", d)
+		}
+	}
+
+	// TEST CASE 3: Removed, then added again, during a check
+	// ------------------------------------------------------
+	old := newTarget("https://example.com")
+	s = newScheduler([]target{old}, 0, now)
+	checking := s.pop()
+	if _, _, removed := s.apply(nil, now); removed != 1 {
+		t.Fatalf("Test Case 3: Expected the target removed. Got %d removed", removed)
+	}
+	s.reschedule(checking, now)
+	if s.len() != 0 {
+		t.Errorf("Test Case 3: Expected a removed target to stay out of the queue. Got %d queued", s.len())
+	}
+	s.apply([]target{old}, now)
+	checking = s.pop()
+	updated := old
+	updated.method = http.MethodHead
+	s.apply(nil, now)
+	if added, _, _ := s.apply([]target{updated}, now); added != 1 || s.len() != 0 {
+		t.Errorf("Test Case 3: Expected the target added again, and not queued while in flight. Got %d added, %d queued", added, s.len())
+	}
+	s.reschedule(checking, now)
+	if s.len() != 1 || s.pop().method != http.MethodHead {
+		t.Errorf("Test Case 3: Expected the new version queued once. Got %d queued", s.len()+1)
+	}
+}
+
+// Test Cases for the worker pool
+// ******************************
+//   - Each target should be checked at its own interval
+//   - 10k targets should not need more goroutines than the pool
+
+func Test_monitorWorkers(t *testing.T) {
+	// TEST CASE 1: Per-target intervals
+	// ---------------------------------
+	server := newStatusServer(t, http.StatusOK, 0)
+	fast, slow := newTarget(server.URL+"/fast"), newTarget(server.URL+"/slow")
+	fast.interval = 10 * time.Millisecond
+	slow.interval = time.Hour
+	m := newMonitor([]target{fast, slow})
+	m.report = func(res checkResult) {}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	summary := m.run(ctx)
+	if summary.byId[fast.id].checks < 5 || summary.byId[slow.id].checks != 1 {
+		t.Errorf("Test Case 1: Expected many fast checks and 1 slow check. Got %s", summary.toString())
+	}
+
+	// TEST CASE 2: 10k targets
+	// ------------------------
+	targets := make([]target, 10_000)
+	for i := range targets {
+		targets[i] = newTarget(fmt.Sprintf("%s/%d", server.URL, i))
+	}
+	m = newMonitor(targets)
+	m.interval = 50 * time.Millisecond
+	m.workers = 8
+	peak := 0
+	baseline := runtime.NumGoroutine()
+	m.report = func(res checkResult) { peak = max(peak, runtime.NumGoroutine()) }
+	ctx, cancel = context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	summary = m.run(ctx)
+	// Each worker may hold a client and a server connection, with a few goroutines each
+	if summary.checks == 0 || peak-baseline > 8*m.workers {
+		t.Errorf("Test Case 2: Expected at most %d more goroutines. Got %d more for %d checks", 8*m.workers, peak-baseline, summary.checks)
+	}
+}
@@ -0,0 +1,169 @@
+/**
+ * @file: Describes the state of a target over time: Up or down after enough checks in a row,
+ * and flapping when it bounces between the two too often.
+ *
+ * Only a change of state raises an alert:
+ *   - A target is up from its first success, and down from its first downAfter failures in a row
+ *   - up -> down only after downAfter failed checks in a row (each after its retries)
+ *   - down -> up only after upAfter successful checks in a row
+ *   - A target that changes state flapChanges times within flapWindow is flapping:
+ *     It raises one alert, then stays quiet until it settles down.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"fmt"
+	"time"
+)
+
+// Constants
+// *********
+
+// State of a target.
+type targetStatus string
+
+const (
+	// Not enough checks yet
+	statusUnknown targetStatus = "unknown"
+	// Responding as expected (degraded counts as up)
+	statusUp targetStatus = "up"
+	// Failing
+	statusDown targetStatus = "down"
+	// Bouncing between up and down: Its changes are not alerted
+	statusFlapping targetStatus = "flapping"
+	// Not a state: An alert about a target whose state stays the same, such as a certificate close to expiry
+	statusWarning targetStatus = "warning"
+)
+
+// Defaults of an alert policy.
+const (
+	defaultDownAfter   = 3
+	defaultUpAfter     = 2
+	defaultFlapWindow  = 10 * time.Minute
+	defaultFlapChanges = 4
+)
+
+// Type Declaration
+// ****************
+
+// An alertPolicy tells when a target changes state, and when it is flapping.
+type alertPolicy struct {
+	downAfter  int
+	upAfter    int
+	flapWindow time.Duration
+	// Changes within flapWindow to count as flapping: 0 to never flap
+	flapChanges int
+}
+
+// A targetState follows the checks of one target.
+type targetState struct {
+	// State as alerted: up, down or flapping
+	status targetStatus
+	// State from the checks only: up or down
+	health    targetStatus
+	failures  int
+	successes int
+	// Times of the recent changes of health, within the flap window
+	changes []time.Time
+}
+
+// A stateChange is an alert: A target changed state.
+type stateChange struct {
+	target target
+	from   targetStatus
+	to     targetStatus
+	at     time.Time
+	// Result of the check that made the change
+	result checkResult
+	// What a warning is about: Empty for a change of state
+	warning string
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newAlertPolicy()
+// Initializes the default policy: Down after 3 failures, up after 2 successes, flapping at 4 changes in 10 minutes.
+func newAlertPolicy() alertPolicy {
+	return alertPolicy{downAfter: defaultDownAfter, upAfter: defaultUpAfter, flapWindow: defaultFlapWindow, flapChanges: defaultFlapChanges}
+}
+
+// newTargetState()
+// Initializes the state of a target that has not been checked yet.
+func newTargetState() *targetState {
+	return &targetState{status: statusUnknown, health: statusUnknown}
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// targetState.observe()
+// Receiver Function that counts a result with the target's policy.
+// Returns the change of state, if the result made one.
+func (s *targetState) observe(res checkResult) (stateChange, bool) {
+	policy := res.target.alerting
+	if res.verdict == verdictDown {
+		s.failures, s.successes = s.failures+1, 0
+	} else {
+		s.failures, s.successes = 0, s.successes+1
+	}
+
+	// Health: The first success is enough to know a target is up, but any later change needs a streak
+	health := s.health
+	switch {
+	case health != statusUp && s.successes > 0 && (health == statusUnknown || s.successes >= policy.upAfter):
+		health = statusUp
+	case health != statusDown && s.failures >= policy.downAfter:
+		health = statusDown
+	}
+	if health != s.health && s.health != statusUnknown {
+		s.changes = append(s.changes, res.checkedAt)
+	}
+	s.health = health
+
+	// Flapping: Too many changes within the window. It stops once half as many remain.
+	for len(s.changes) > 0 && res.checkedAt.Sub(s.changes[0]) > policy.flapWindow {
+		s.changes = s.changes[1:]
+	}
+	status := s.health
+	if policy.flapChanges > 0 {
+		flapping := len(s.changes) >= policy.flapChanges
+		if s.status == statusFlapping && len(s.changes) > policy.flapChanges/2 {
+			flapping = true
+		}
+		if flapping {
+			status = statusFlapping
+		}
+	}
+
+	if status == s.status || status == statusUnknown {
+		return stateChange{}, false
+	}
+	// A target up from the start is no news
+	if s.status == statusUnknown && status == statusUp {
+		s.status = status
+		return stateChange{}, false
+	}
+	change := stateChange{target: res.target, from: s.status, to: status, at: res.checkedAt, result: res}
+	s.status = status
+	return change, true
+}
+
+// stateChange.toString()
+// Receiver Function to describe the alert on one line.
+func (c stateChange) toString() string {
+	switch c.to {
+	case statusDown:
+		return fmt.Sprintf("ALERT: %s is down (was %s): %s", c.target.id, c.from, c.result.toString())
+	case statusFlapping:
+		return fmt.Sprintf("ALERT: %s is flapping: Alerts are paused until it settles down", c.target.id)
+	case statusWarning:
+		return "WARNING: " + c.warning
+	}
+	return fmt.Sprintf("ALERT: %s is up (was %s)", c.target.id, c.from)
+}
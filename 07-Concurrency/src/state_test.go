@@ -0,0 +1,81 @@
+/**
+ * @file: Unit tests for the state of a target: Thresholds and flap detection
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test Helpers
+// ************
+
+// observeAll feeds the state with one result per verdict, a minute apart,
+// and returns the changes it made as "from>to" strings.
+func observeAll(s *targetState, tg target, start time.Time, verdicts string) []string {
+	var changes []string
+	for i, v := range strings.Split(verdicts, " ") {
+		res := checkResult{target: tg, checkedAt: start.Add(time.Duration(i) * time.Minute), verdict: verdictUp}
+		if v == "D" {
+			res.verdict = verdictDown
+		}
+		if change, changed := s.observe(res); changed {
+			changes = append(changes, fmt.Sprintf("%s>%s", change.from, change.to))
+		}
+	}
+	return changes
+}
+
+// Test Cases for targetState.observe()
+// ************************************
+//   - A target up from the start should not raise an alert
+//   - up -> down only after downAfter failures in a row, down -> up only after upAfter successes
+//   - A target down from the start should raise an alert
+//   - A flapping target should raise one alert, then stay quiet until it settles down
+
+func Test_targetStateObserve(t *testing.T) {
+	tg := newTarget("https://go.dev")
+	tg.alerting = alertPolicy{downAfter: 3, upAfter: 2, flapWindow: 10 * time.Minute, flapChanges: 4}
+	start := time.Now()
+
+	// TEST CASE 1: Up from the start
+	// ------------------------------
+	if changes := observeAll(newTargetState(), tg, start, "U U U"); len(changes) != 0 {
+		t.Errorf("Test Case 1: Expected no alert. Got %v", changes)
+	}
+
+	// TEST CASE 2: Thresholds
+	// -----------------------
+	changes := observeAll(newTargetState(), tg, start, "U D D U D D D U D U U")
+	if fmt.Sprint(changes) != "[up>down down>up]" {
+		t.Errorf("Test Case 2: Expected down after 3 failures, up after 2 successes. Got %v", changes)
+	}
+
+	// TEST CASE 3: Down from the start
+	// --------------------------------
+	if changes := observeAll(newTargetState(), tg, start, "D D D D"); fmt.Sprint(changes) != "[unknown>down]" {
+		t.Errorf("Test Case 3: Expected one alert. Got %v", changes)
+	}
+
+	// TEST CASE 4: Flapping
+	// ---------------------
+	// 4 changes in 10 minutes, then some more: One flapping alert
+	s := newTargetState()
+	bouncing := "U D D D U U D D D U U D D D U U"
+	if changes := observeAll(s, tg, start, bouncing); fmt.Sprint(changes) != "[up>down down>up up>down down>flapping]" {
+		t.Errorf("Test Case 4: Expected 3 alerts, then flapping. Got %v", changes)
+	}
+	// Up for good: Once the changes leave the window, one alert that it settled down
+	later := start.Add(time.Duration(len(strings.Split(bouncing, " "))) * time.Minute)
+	if changes := observeAll(s, tg, later, strings.Repeat("U ", 15)+"U"); fmt.Sprint(changes) != "[flapping>up]" {
+		t.Errorf("Test Case 4: Expected one alert once settled. Got %v", changes)
+	}
+}
@@ -0,0 +1,237 @@
+/**
+ * @file: Describes the status board: The recent history of each target, its uptime and its incidents,
+ * as shown by the status page and the JSON API.
+ *
+ * The board keeps the last checks of each target in memory. An incident opens when a target goes down
+ * or starts flapping, and closes when it is up again.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Constants
+// *********
+
+// Defaults of the status board.
+const (
+	// Checks kept per target
+	defaultHistorySize = 120
+	// Incidents kept per target
+	defaultIncidentsKept = 20
+)
+
+// Type Declaration
+// ****************
+
+// A statusBoard holds the recent history of every target. It is safe for concurrent use.
+type statusBoard struct {
+	mu          sync.Mutex
+	targets     map[string]*targetBoard
+	historySize int
+}
+
+// A targetBoard holds the recent history of one target.
+type targetBoard struct {
+	target    target
+	status    targetStatus
+	checks    int
+	upChecks  int
+	samples   []statusSample
+	incidents []incident
+}
+
+// A statusSample is one check, as shown by the API.
+type statusSample struct {
+	At         time.Time `json:"at"`
+	LatencyMs  float64   `json:"latency_ms"`
+	Verdict    verdict   `json:"verdict"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	// What a TLS check found about the certificate
+	TLS *tlsInfo `json:"tls,omitempty"`
+}
+
+// An incident is a time a target was down or flapping. It is open until the target is up again.
+type incident struct {
+	TargetID string       `json:"target_id"`
+	State    targetStatus `json:"state"`
+	Start    time.Time    `json:"start"`
+	End      *time.Time   `json:"end,omitempty"`
+	// What the check that opened it found
+	Cause string `json:"cause"`
+}
+
+// A targetView is the current status of a target, as shown by the API.
+type targetView struct {
+	ID            string        `json:"id"`
+	URL           string        `json:"url"`
+	Tags          []string      `json:"tags,omitempty"`
+	State         targetStatus  `json:"state"`
+	UptimePercent float64       `json:"uptime_percent"`
+	Checks        int           `json:"checks"`
+	LastCheck     *statusSample `json:"last_check,omitempty"`
+	OpenIncident  *incident     `json:"open_incident,omitempty"`
+	// Recent checks, oldest first: Left out of the list of targets
+	Samples []statusSample `json:"-"`
+}
+
+// A targetHistory is the recent history of a target, as shown by the API.
+type targetHistory struct {
+	ID        string         `json:"id"`
+	Samples   []statusSample `json:"samples"`
+	Incidents []incident     `json:"incidents"`
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newStatusBoard()
+// Initializes an empty board, keeping the default number of checks per target.
+func newStatusBoard() *statusBoard {
+	return &statusBoard{targets: map[string]*targetBoard{}, historySize: defaultHistorySize}
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// statusBoard.observe()
+// Receiver Function that records a check, and the state of its target after it.
+func (b *statusBoard) observe(res checkResult, status targetStatus) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	tb, found := b.targets[res.target.id]
+	if !found {
+		tb = &targetBoard{}
+		b.targets[res.target.id] = tb
+	}
+	tb.target, tb.status = res.target, status
+	tb.checks++
+	if res.verdict != verdictDown {
+		tb.upChecks++
+	}
+
+	s := statusSample{
+		At:         res.checkedAt,
+		LatencyMs:  float64(res.latency.Microseconds()) / 1000,
+		Verdict:    res.verdict,
+		StatusCode: res.statusCode,
+		TLS:        res.tls,
+	}
+	if res.err != nil {
+		s.Error = res.err.Error()
+	}
+	tb.samples = append(tb.samples, s)
+	if extra := len(tb.samples) - b.historySize; extra > 0 {
+		tb.samples = slices.Delete(tb.samples, 0, extra)
+	}
+}
+
+// statusBoard.change()
+// Receiver Function that opens or closes the incident of a target on a change of state.
+func (b *statusBoard) change(c stateChange) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	tb, found := b.targets[c.target.id]
+	if !found {
+		return
+	}
+	open := len(tb.incidents) > 0 && tb.incidents[len(tb.incidents)-1].End == nil
+	switch {
+	case c.to == statusUp && open:
+		end := c.at
+		tb.incidents[len(tb.incidents)-1].End = &end
+	case c.to != statusUp && !open:
+		tb.incidents = append(tb.incidents, incident{TargetID: c.target.id, State: c.to, Start: c.at, Cause: c.result.toString()})
+		if extra := len(tb.incidents) - defaultIncidentsKept; extra > 0 {
+			tb.incidents = slices.Delete(tb.incidents, 0, extra)
+		}
+	case c.to != statusUp && open:
+		// Down then flapping, or the other way round: Still the same incident
+		tb.incidents[len(tb.incidents)-1].State = c.to
+	}
+}
+
+// statusBoard.retain()
+// Receiver Function that forgets the targets that are no longer monitored.
+func (b *statusBoard) retain(keep func(id string) bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for id := range b.targets {
+		if !keep(id) {
+			delete(b.targets, id)
+		}
+	}
+}
+
+// statusBoard.targetViews()
+// Receiver Function that returns the current status of every target, sorted by id.
+func (b *statusBoard) targetViews() []targetView {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	views := make([]targetView, 0, len(b.targets))
+	for _, tb := range b.targets {
+		views = append(views, tb.view())
+	}
+	slices.SortFunc(views, func(x, y targetView) int { return strings.Compare(x.ID, y.ID) })
+	return views
+}
+
+// statusBoard.history()
+// Receiver Function that returns the recent history of a target. Returns false if the board does not know it.
+func (b *statusBoard) history(id string) (targetHistory, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	tb, found := b.targets[id]
+	if !found {
+		return targetHistory{}, false
+	}
+	return targetHistory{ID: id, Samples: slices.Clone(tb.samples), Incidents: slices.Clone(tb.incidents)}, true
+}
+
+// statusBoard.incidents()
+// Receiver Function that returns the most recent incidents of every target, newest first.
+func (b *statusBoard) incidents(limit int) []incident {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var all []incident
+	for _, tb := range b.targets {
+		all = append(all, tb.incidents...)
+	}
+	slices.SortFunc(all, func(x, y incident) int { return y.Start.Compare(x.Start) })
+	return all[:min(limit, len(all))]
+}
+
+// targetBoard.view()
+// Receiver Function that returns the current status of the target. The board must be locked.
+func (tb *targetBoard) view() targetView {
+	v := targetView{
+		ID:      tb.target.id,
+		URL:     tb.target.url,
+		Tags:    tb.target.tags,
+		State:   tb.status,
+		Checks:  tb.checks,
+		Samples: slices.Clone(tb.samples),
+	}
+	if tb.checks > 0 {
+		v.UptimePercent = 100 * float64(tb.upChecks) / float64(tb.checks)
+	}
+	if len(tb.samples) > 0 {
+		last := tb.samples[len(tb.samples)-1]
+		v.LastCheck = &last
+	}
+	if n := len(tb.incidents); n > 0 && tb.incidents[n-1].End == nil {
+		open := tb.incidents[n-1]
+		v.OpenIncident = &open
+	}
+	return v
+}
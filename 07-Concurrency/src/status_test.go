@@ -0,0 +1,70 @@
+/**
+ * @file: Unit tests for the status board: History, uptime and incidents
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"testing"
+	"time"
+)
+
+// Test Cases for statusBoard
+// **************************
+//   - Each check should count in the uptime, and only the last ones should be kept
+//   - An incident should open on down, stay open while flapping, and close on up
+//   - Removed targets should be forgotten
+
+func Test_statusBoard(t *testing.T) {
+	api := newTarget("https://api.example.com")
+	api.id = "api"
+	start := time.Now()
+	check := func(i int, v verdict) checkResult {
+		return checkResult{target: api, checkedAt: start.Add(time.Duration(i) * time.Minute), latency: time.Duration(i) * time.Millisecond, verdict: v}
+	}
+
+	// TEST CASE 1: Uptime and history
+	// -------------------------------
+	b := newStatusBoard()
+	b.historySize = 3
+	for i, v := range []verdict{verdictUp, verdictDegraded, verdictDown, verdictUp} {
+		b.observe(check(i, v), statusUp)
+	}
+	views := b.targetViews()
+	if len(views) != 1 || views[0].UptimePercent != 75 || views[0].Checks != 4 || views[0].State != statusUp {
+		t.Fatalf("Test Case 1: Expected 75%% uptime over 4 checks. Got %+v", views)
+	}
+	history, found := b.history("api")
+	if !found || len(history.Samples) != 3 || history.Samples[0].Verdict != verdictDegraded || views[0].LastCheck.LatencyMs != 3 {
+		t.Errorf("Test Case 1: Expected the last 3 checks. Got %+v", history.Samples)
+	}
+
+	// TEST CASE 2: Incidents
+	// ----------------------
+	down := stateChange{target: api, from: statusUp, to: statusDown, at: start.Add(10 * time.Minute), result: check(10, verdictDown)}
+	b.change(down)
+	b.change(stateChange{target: api, from: statusDown, to: statusFlapping, at: start.Add(20 * time.Minute)})
+	if views := b.targetViews(); views[0].OpenIncident == nil || views[0].OpenIncident.State != statusFlapping {
+		t.Errorf("Test Case 2: Expected one open incident, now flapping. Got %+v", views[0].OpenIncident)
+	}
+	b.change(stateChange{target: api, from: statusFlapping, to: statusUp, at: start.Add(40 * time.Minute)})
+	incidents := b.incidents(10)
+	if len(incidents) != 1 || incidents[0].End == nil || incidents[0].End.Sub(incidents[0].Start) != 30*time.Minute ||
+		incidents[0].Cause != down.result.toString() {
+		t.Errorf("Test Case 2: Expected one closed incident of 30 minutes. Got %+v", incidents)
+	}
+	if views := b.targetViews(); views[0].OpenIncident != nil {
+		t.Errorf("Test Case 2: Expected no open incident. Got %+v", views[0].OpenIncident)
+	}
+
+	// TEST CASE 3: Removed targets
+	// ----------------------------
+	b.retain(func(id string) bool { return id != "api" })
+	if _, found := b.history("api"); found || len(b.targetViews()) != 0 {
+		t.Errorf("Test Case 3: Expected the target to be forgotten")
+	}
+}
@@ -0,0 +1,336 @@
+/**
+ * @file: Describes the TLS check mode: Instead of an HTTP request, a TLS handshake with the host of the URL,
+ * and a look at the certificate it presents.
+ *
+ * The check records the protocol version, and the expiry, subject, issuer and SANs of the certificate.
+ *   - Down: The handshake failed, the certificate expired (or is not valid yet), its chain does not lead
+ *     to a trusted root, or it is not valid for the host name
+ *   - Degraded: The certificate expires within the largest warning threshold, or the protocol is older
+ *     than the minimum version. A server that still accepts an older one, with a second handshake capped
+ *     below the minimum, is degraded too
+ *
+ * The handshake itself trusts any certificate: The chain and the host name are checked afterwards,
+ * so that a bad certificate is described rather than only refused.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Constants
+// *********
+
+// Modes of a check.
+type checkMode string
+
+const (
+	// An HTTP request, judged by its status and latency
+	modeHTTP checkMode = "http"
+	// A TLS handshake, judged by the certificate and the protocol version
+	modeTLS checkMode = "tls"
+)
+
+// Defaults of a TLS check.
+var defaultWarnDays = []int{30, 14, 7}
+
+const defaultMinTLSVersion = "1.2"
+
+// Protocol versions of TLS, as written in the config file.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Type Declaration
+// ****************
+
+// A tlsPolicy tells what a TLS check expects from the certificate and the protocol.
+type tlsPolicy struct {
+	// Days before the expiry at which to warn: Degraded from the largest
+	warnDays []int
+	// Oldest acceptable protocol version, such as "1.2"
+	minVersion string
+	// PEM file of the roots that vouch for the chain: Empty for the roots of the system
+	caFile string
+}
+
+// A tlsInfo is what a TLS check found. Its fields are exported for the JSON API.
+type tlsInfo struct {
+	Version    string    `json:"version"`
+	Subject    string    `json:"subject"`
+	Issuer     string    `json:"issuer"`
+	SANs       []string  `json:"sans"`
+	NotBefore  time.Time `json:"not_before"`
+	NotAfter   time.Time `json:"not_after"`
+	DaysLeft   int       `json:"days_left"`
+	ChainValid bool      `json:"chain_valid"`
+	HostnameOK bool      `json:"hostname_ok"`
+	// What makes the target down, and what makes it degraded
+	Problems []string `json:"problems,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// An expiryWarner warns once for each threshold a certificate crosses on its way to expiry.
+type expiryWarner struct {
+	// Last threshold warned of, per target
+	warned map[string]int
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newTLSPolicy()
+// Initializes the default policy: Warnings 30, 14 and 7 days before expiry, and TLS 1.2 at least.
+func newTLSPolicy() tlsPolicy {
+	return tlsPolicy{warnDays: defaultWarnDays, minVersion: defaultMinTLSVersion}
+}
+
+// newExpiryWarner()
+// Initializes a warner that has not warned of anything yet.
+func newExpiryWarner() *expiryWarner {
+	return &expiryWarner{warned: map[string]int{}}
+}
+
+// Receiver Functions (Type Methods)
+// *********************************
+
+// tlsPolicy.inspect()
+// Receiver Function that describes the state of a handshake with host: Its certificate and protocol
+// against the policy, as of now.
+func (p tlsPolicy) inspect(state tls.ConnectionState, host string, roots *x509.CertPool, now time.Time) *tlsInfo {
+	info := &tlsInfo{Version: tls.VersionName(state.Version)}
+	if len(state.PeerCertificates) == 0 {
+		info.Problems = append(info.Problems, "no certificate")
+		return info
+	}
+	leaf := state.PeerCertificates[0]
+	info.Subject, info.Issuer = leaf.Subject.String(), leaf.Issuer.String()
+	info.SANs = append(slices.Clone(leaf.DNSNames), ipStrings(leaf.IPAddresses)...)
+	info.NotBefore, info.NotAfter = leaf.NotBefore, leaf.NotAfter
+	info.DaysLeft = int(leaf.NotAfter.Sub(now).Hours() / 24)
+
+	// Expiry
+	switch {
+	case now.After(leaf.NotAfter):
+		info.Problems = append(info.Problems, fmt.Sprintf("certificate expired on %s", leaf.NotAfter.Format(time.DateOnly)))
+	case now.Before(leaf.NotBefore):
+		info.Problems = append(info.Problems, fmt.Sprintf("certificate not valid before %s", leaf.NotBefore.Format(time.DateOnly)))
+	case len(p.warnDays) > 0 && info.DaysLeft < slices.Max(p.warnDays):
+		info.Warnings = append(info.Warnings, fmt.Sprintf("certificate expires in %d days", info.DaysLeft))
+	}
+
+	// Chain: Checked at the closest time the certificate is valid, so that an expired one is not reported twice
+	intermediates := x509.NewCertPool()
+	for _, c := range state.PeerCertificates[1:] {
+		intermediates.AddCert(c)
+	}
+	at := now
+	if now.After(leaf.NotAfter) {
+		at = leaf.NotAfter
+	} else if now.Before(leaf.NotBefore) {
+		at = leaf.NotBefore
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, CurrentTime: at}); err != nil {
+		info.Problems = append(info.Problems, fmt.Sprintf("invalid chain: %v", err))
+	} else {
+		info.ChainValid = true
+	}
+
+	// Host name
+	if err := leaf.VerifyHostname(host); err != nil {
+		info.Problems = append(info.Problems, fmt.Sprintf("hostname mismatch: not valid for %s", host))
+	} else {
+		info.HostnameOK = true
+	}
+
+	// Protocol
+	if state.Version < tlsVersions[p.minVersion] {
+		info.Warnings = append(info.Warnings, fmt.Sprintf("weak protocol %s, below TLS %s", info.Version, p.minVersion))
+	}
+	return info
+}
+
+// tlsPolicy.acceptsWeak()
+// Receiver Function that tells if the server at addr accepts a protocol older than the minimum version,
+// with a handshake capped just below it. Returns the version it accepted.
+func (p tlsPolicy) acceptsWeak(ctx context.Context, addr, host string) (string, bool) {
+	minVersion := tlsVersions[p.minVersion]
+	if minVersion <= tls.VersionTLS10 {
+		return "", false
+	}
+	conn, err := handshake(ctx, addr, host, minVersion-1)
+	if err != nil {
+		return "", false
+	}
+	defer conn.Close()
+	return tls.VersionName(conn.(*tls.Conn).ConnectionState().Version), true
+}
+
+// tlsPolicy.roots()
+// Receiver Function that loads the roots of the policy: nil for the roots of the system.
+func (p tlsPolicy) roots() (*x509.CertPool, error) {
+	if p.caFile == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(p.caFile)
+	if err != nil {
+		return nil, err
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificate in %s", p.caFile)
+	}
+	return roots, nil
+}
+
+// tlsInfo.toString()
+// Receiver Function to describe the certificate on one line, with its warnings.
+func (info *tlsInfo) toString() string {
+	s := fmt.Sprintf("%s, certificate of %q issued by %q, expires in %d days (%s)",
+		info.Version, info.Subject, info.Issuer, info.DaysLeft, info.NotAfter.Format(time.DateOnly))
+	if len(info.Warnings) > 0 {
+		s += ": " + strings.Join(info.Warnings, ", ")
+	}
+	return s
+}
+
+// expiryWarner.observe()
+// Receiver Function that tells if a result crosses a new warning threshold of its target, and returns the warning.
+// A renewed certificate starts over.
+func (w *expiryWarner) observe(res checkResult) (string, bool) {
+	info := res.tls
+	// Expired: The target is down, and alerts say so
+	if info == nil || info.NotAfter.IsZero() || info.DaysLeft < 0 {
+		return "", false
+	}
+	// The tightest threshold crossed
+	threshold := -1
+	for _, days := range res.target.tls.warnDays {
+		if info.DaysLeft < days && (threshold < 0 || days < threshold) {
+			threshold = days
+		}
+	}
+	if threshold < 0 {
+		delete(w.warned, res.target.id)
+		return "", false
+	}
+	if last, found := w.warned[res.target.id]; found && last <= threshold {
+		return "", false
+	}
+	w.warned[res.target.id] = threshold
+	return fmt.Sprintf("The certificate of %s expires in %d days, on %s (under %d days)",
+		res.target.id, info.DaysLeft, info.NotAfter.Format(time.DateOnly), threshold), true
+}
+
+// expiryWarner.retain()
+// Receiver Function that forgets the targets that are no longer monitored.
+func (w *expiryWarner) retain(keep func(id string) bool) {
+	for id := range w.warned {
+		if !keep(id) {
+			delete(w.warned, id)
+		}
+	}
+}
+
+// Helper Functions
+// ****************
+
+// checkCertificate()
+// Checks the certificate of the host of a URL with a TLS handshake, and judges it with the target's TLS policy.
+// The check gives up when ctx is done, or after the target's timeout.
+func checkCertificate(ctx context.Context, t target) checkResult {
+	res := checkResult{target: t, checkedAt: time.Now(), attempts: 1}
+	if t.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+	}
+
+	u, err := url.Parse(t.url)
+	var roots *x509.CertPool
+	if err == nil {
+		roots, err = t.tls.roots()
+	}
+	var addr string
+	var negotiated uint16
+	if err == nil {
+		port := u.Port()
+		if port == "" {
+			port = "443"
+		}
+		addr = net.JoinHostPort(u.Hostname(), port)
+		// Any certificate and any version pass the handshake: They are judged afterwards
+		var conn net.Conn
+		conn, err = handshake(ctx, addr, u.Hostname(), tls.VersionTLS13)
+		if err == nil {
+			state := conn.(*tls.Conn).ConnectionState()
+			negotiated = state.Version
+			res.tls = t.tls.inspect(state, u.Hostname(), roots, time.Now())
+			conn.Close()
+		}
+	}
+	res.latency = time.Since(res.checkedAt)
+
+	// The server may prefer a recent protocol, and still accept a weak one: Ask for one below the minimum
+	if err == nil && negotiated >= tlsVersions[t.tls.minVersion] {
+		if weak, accepted := t.tls.acceptsWeak(ctx, addr, u.Hostname()); accepted {
+			res.tls.Warnings = append(res.tls.Warnings, fmt.Sprintf("accepts weak protocol %s, below TLS %s", weak, t.tls.minVersion))
+		}
+	}
+
+	// Error Handling: A bad certificate is a TLS error
+	if err == nil && len(res.tls.Problems) > 0 {
+		err = errors.New(strings.Join(res.tls.Problems, "; "))
+		res.errClass = errorTLS
+	} else if err != nil {
+		res.errClass = classifyError(err)
+	}
+	res.err = err
+	switch {
+	case err != nil:
+		res.verdict = verdictDown
+	case len(res.tls.Warnings) > 0 || (t.rule.degradedLatency > 0 && res.latency > t.rule.degradedLatency):
+		res.verdict = verdictDegraded
+	default:
+		res.verdict = verdictUp
+	}
+	return res
+}
+
+// handshake()
+// Opens a TLS connection to addr, from TLS 1.0 up to maxVersion. Any certificate passes: It is judged afterwards.
+func handshake(ctx context.Context, addr, host string, maxVersion uint16) (net.Conn, error) {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: 5 * time.Second},
+		Config:    &tls.Config{ServerName: host, InsecureSkipVerify: true, MinVersion: tls.VersionTLS10, MaxVersion: maxVersion},
+	}
+	return dialer.DialContext(ctx, "tcp", addr)
+}
+
+// ipStrings()
+// Returns the IP addresses as text.
+func ipStrings(ips []net.IP) []string {
+	s := make([]string, len(ips))
+	for i, ip := range ips {
+		s[i] = ip.String()
+	}
+	return s
+}
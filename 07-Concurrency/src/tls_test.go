@@ -0,0 +1,264 @@
+/**
+ * @file: Unit tests for the TLS check mode, against httptest TLS servers with generated certificates
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test Helpers
+// ************
+
+// A testCA issues the certificates of the tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	// PEM file of the certificate, for ca_file
+	file string
+}
+
+// newTestCA generates a certificate authority, and writes its certificate to a PEM file.
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().AddDate(-1, 0, 0),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	file := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, file: file}
+}
+
+// issue issues a server certificate for the hosts (names or IP addresses), valid from notBefore to notAfter.
+func (ca *testCA) issue(t *testing.T, hosts []string, notBefore, notAfter time.Time) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: hosts[0]},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// newCertServer starts a TLS server with the certificate, up to the TLS version.
+func newCertServer(t *testing.T, cert tls.Certificate, maxVersion uint16) *httptest.Server {
+	t.Helper()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS10, MaxVersion: maxVersion}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTLSTarget returns a TLS target of the server, trusting the roots in caFile (empty for the system roots).
+func newTLSTarget(url, caFile string) target {
+	tg := newTarget(url)
+	tg.mode = modeTLS
+	tg.tls.caFile = caFile
+	return tg
+}
+
+// Test Cases for checkCertificate()
+// *********************************
+//   - A valid certificate should be up, and described: Version, subject, issuer, SANs, expiry
+//   - A certificate close to its expiry should be degraded, and an expired one down
+//   - A hostname mismatch or an untrusted chain should be down
+//   - A weak protocol version should be degraded, even when the server prefers a recent one
+//   - A server that does not speak TLS should be down
+
+func Test_checkCertificate(t *testing.T) {
+	ctx := context.Background()
+	ca := newTestCA(t)
+	now := time.Now()
+
+	// TEST CASE 1: Valid certificate
+	// ------------------------------
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	caFile := filepath.Join(t.TempDir(), "server.pem")
+	os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o644)
+	res := checkTarget(ctx, nil, newTLSTarget(server.URL, caFile))
+	info := res.tls
+	if res.verdict != verdictUp || res.err != nil || info == nil {
+		t.Fatalf("Test Case 1: Expected up. Got %s", res.toString())
+	}
+	if info.Version != "TLS 1.3" || !info.ChainValid || !info.HostnameOK || !slices.Contains(info.SANs, "127.0.0.1") ||
+		!slices.Contains(info.SANs, "example.com") || info.DaysLeft < 365 || !strings.Contains(info.Issuer, "Acme Co") {
+		t.Errorf("Test Case 1: Expected the certificate of httptest, described. Got %+v", info)
+	}
+
+	// TEST CASE 2: Close to expiry, or expired
+	// ----------------------------------------
+	soon := newCertServer(t, ca.issue(t, []string{"127.0.0.1"}, now.Add(-time.Hour), now.Add(10*24*time.Hour+time.Hour)), tls.VersionTLS13)
+	res = checkTarget(ctx, nil, newTLSTarget(soon.URL, ca.file))
+	if res.verdict != verdictDegraded || res.tls.DaysLeft != 10 || !strings.Contains(res.toString(), "certificate expires in 10 days") {
+		t.Errorf("Test Case 2: Expected degraded, 10 days before expiry. Got %s", res.toString())
+	}
+	expired := newCertServer(t, ca.issue(t, []string{"127.0.0.1"}, now.AddDate(0, -3, 0), now.Add(-24*time.Hour)), tls.VersionTLS13)
+	res = checkTarget(ctx, nil, newTLSTarget(expired.URL, ca.file))
+	if res.verdict != verdictDown || res.errClass != errorTLS || !strings.Contains(res.err.Error(), "expired") || !res.tls.ChainValid {
+		t.Errorf("Test Case 2: Expected down for an expired certificate, with a valid chain. Got %s (%+v)", res.toString(), res.tls)
+	}
+
+	// TEST CASE 3: Hostname mismatch and untrusted chain
+	// --------------------------------------------------
+	other := newCertServer(t, ca.issue(t, []string{"other.example"}, now.Add(-time.Hour), now.AddDate(1, 0, 0)), tls.VersionTLS13)
+	res = checkTarget(ctx, nil, newTLSTarget(other.URL, ca.file))
+	if res.verdict != verdictDown || res.tls.HostnameOK || !res.tls.ChainValid || !strings.Contains(res.err.Error(), "hostname mismatch") {
+		t.Errorf("Test Case 3: Expected down for a hostname mismatch. Got %s (%+v)", res.toString(), res.tls)
+	}
+	res = checkTarget(ctx, nil, newTLSTarget(soon.URL, ""))
+	if res.verdict != verdictDown || res.tls.ChainValid || !strings.Contains(res.err.Error(), "invalid chain") {
+		t.Errorf("Test Case 3: Expected down for a chain the system does not trust. Got %s (%+v)", res.toString(), res.tls)
+	}
+
+	// TEST CASE 4: Weak protocol
+	// --------------------------
+	old := newCertServer(t, ca.issue(t, []string{"127.0.0.1"}, now.Add(-time.Hour), now.AddDate(1, 0, 0)), tls.VersionTLS11)
+	res = checkTarget(ctx, nil, newTLSTarget(old.URL, ca.file))
+	if res.verdict != verdictDegraded || res.tls.Version != "TLS 1.1" || !strings.Contains(res.toString(), "weak protocol TLS 1.1") {
+		t.Errorf("Test Case 4: Expected degraded for TLS 1.1. Got %s", res.toString())
+	}
+	if len(res.tls.Warnings) != 1 {
+		t.Errorf("Test Case 4: Expected the weak protocol warned of once. Got %v", res.tls.Warnings)
+	}
+	// TLS 1.0 to 1.3: TLS 1.3 is negotiated, but TLS 1.1 is still accepted
+	both := newCertServer(t, ca.issue(t, []string{"127.0.0.1"}, now.Add(-time.Hour), now.AddDate(1, 0, 0)), tls.VersionTLS13)
+	res = checkTarget(ctx, nil, newTLSTarget(both.URL, ca.file))
+	if res.verdict != verdictDegraded || res.tls.Version != "TLS 1.3" || !strings.Contains(res.toString(), "accepts weak protocol TLS 1.1") {
+		t.Errorf("Test Case 4: Expected degraded for a server that accepts TLS 1.1. Got %s", res.toString())
+	}
+	lenient := newTLSTarget(both.URL, ca.file)
+	lenient.tls.minVersion = "1.0"
+	if res = checkTarget(ctx, nil, lenient); res.verdict != verdictUp {
+		t.Errorf("Test Case 4: Expected up with TLS 1.0 as the minimum. Got %s", res.toString())
+	}
+
+	// TEST CASE 5: No TLS
+	// -------------------
+	plain := newStatusServer(t, http.StatusOK, 0)
+	res = checkTarget(ctx, nil, newTLSTarget(strings.Replace(plain.URL, "http://", "https://", 1), ""))
+	if res.verdict != verdictDown || res.errClass != errorTLS || res.tls != nil {
+		t.Errorf("Test Case 5: Expected a TLS error. Got %s", res.toString())
+	}
+}
+
+// Test Cases for expiryWarner
+// ***************************
+//   - Each threshold should be warned of once, on the way to expiry
+//   - A renewed certificate should start over, and an expired one is left to the alerts
+//   - A warning should reach the notifiers that take warnings
+
+func Test_expiryWarner(t *testing.T) {
+	// TEST CASE 1: Once per threshold
+	// -------------------------------
+	w := newExpiryWarner()
+	tg := newTLSTarget("https://example.com", "")
+	notAfter := time.Now().AddDate(0, 1, 0)
+	var warned []int
+	for _, days := range []int{40, 29, 20, 13, 3, 2, 90, 25, -1} {
+		res := checkResult{target: tg, tls: &tlsInfo{DaysLeft: days, NotAfter: notAfter}}
+		if msg, warn := w.observe(res); warn {
+			if !strings.Contains(msg, tg.id) {
+				t.Errorf("Test Case 1: Expected the target in the warning. Got %q", msg)
+			}
+			warned = append(warned, days)
+		}
+	}
+	if want := []int{29, 13, 3, 25}; !slices.Equal(warned, want) {
+		t.Errorf("Test Case 1: Expected warnings at %v days. Got %v", want, warned)
+	}
+
+	// TEST CASE 2: Forget removed targets
+	// -----------------------------------
+	w.retain(func(id string) bool { return false })
+	if len(w.warned) != 0 {
+		t.Errorf("Test Case 2: Expected nothing left. Got %v", w.warned)
+	}
+
+	// TEST CASE 3: Sent to the notifiers
+	// ----------------------------------
+	ca := newTestCA(t)
+	now := time.Now()
+	soon := newCertServer(t, ca.issue(t, []string{"127.0.0.1"}, now.Add(-time.Hour), now.Add(10*24*time.Hour+time.Hour)), tls.VersionTLS13)
+	warnings, changes := &fakeNotifier{}, &fakeNotifier{}
+	warningChannel, changeChannel := newNotifyChannel("warnings", warnings), newNotifyChannel("changes", changes)
+	warningChannel.on, changeChannel.on = []targetStatus{statusWarning}, []targetStatus{statusDown, statusUp}
+	d := newDispatcher([]*notifyChannel{warningChannel, changeChannel})
+	d.start()
+	m := newMonitor([]target{newTLSTarget(soon.URL, ca.file)})
+	m.interval = 10 * time.Millisecond
+	m.report = func(res checkResult) {}
+	m.alert = d.dispatch
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	m.run(ctx)
+	d.stop(time.Second)
+	if len(warnings.messages) != 1 || warnings.messages[0].alert.To != statusWarning ||
+		!strings.Contains(warnings.messages[0].body, "expires in 10 days") {
+		t.Fatalf("Test Case 3: Expected 1 warning of the expiry. Got %+v", warnings.messages)
+	}
+	if subject := warnings.messages[0].subject; subject != "[warning] "+soon.URL+" has a warning" {
+		t.Errorf("Test Case 3: Expected the default subject of a warning. Got %q", subject)
+	}
+	if got := changes.subjects(); len(got) != 0 {
+		t.Errorf("Test Case 3: Expected no warning on the channel of the changes. Got %v", got)
+	}
+}
@@ -0,0 +1,249 @@
+/**
+ * @file: Describes the web interface of the monitor:
+ *   - GET /                          - Status page: The state, uptime, latency sparkline and incidents of each target
+ *   - GET /api/targets               - The current status of every target, in JSON
+ *   - GET /api/targets/{id}/history  - The recent checks and incidents of a target, in JSON
+ *   - GET /metrics                   - Prometheus metrics
+ *
+ * The status page is self-contained (no script, no external file): It can also be exported as a static file.
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Constants
+// *********
+
+// Size of a sparkline, in pixels.
+const (
+	sparklineWidth  = 240
+	sparklineHeight = 32
+)
+
+// Incidents listed on the status page.
+const pageIncidents = 20
+
+// Template of the status page.
+var statusPage = template.Must(template.New("status").Funcs(template.FuncMap{
+	"sparkline":   sparkline,
+	"downMarks":   downMarks,
+	"sparkWidth":  func() int { return sparklineWidth },
+	"sparkHeight": func() int { return sparklineHeight },
+	"markY":       func() int { return sparklineHeight - 2 },
+	"percent":     func(f float64) string { return fmt.Sprintf("%.2f%%", f) },
+	"latency":     formatLatency,
+	"when":        func(t time.Time) string { return t.Format("2006-01-02 15:04:05 MST") },
+	"lastsFor":    incidentDuration,
+	"operational": operational,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Status</title>
+<style>
+  body { font-family: system-ui, sans-serif; margin: 2em auto; max-width: 60em; color: #222; }
+  .banner { padding: 1em; border-radius: 6px; color: #fff; font-weight: bold; }
+  .ok { background: #2e7d32; } .ko { background: #c62828; }
+  table { border-collapse: collapse; width: 100%; margin: 1em 0; }
+  th, td { text-align: left; padding: .4em .6em; border-bottom: 1px solid #ddd; vertical-align: middle; }
+  .state { font-weight: bold; text-transform: uppercase; font-size: .85em; }
+  .up { color: #2e7d32; } .down { color: #c62828; } .flapping { color: #ef6c00; } .unknown { color: #777; }
+  .muted { color: #777; font-size: .85em; }
+</style>
+</head>
+<body>
+<h1>Status</h1>
+{{if operational .Targets}}<div class="banner ok">All systems operational</div>{{else}}<div class="banner ko">Some systems are having problems</div>{{end}}
+<table>
+<tr><th>Target</th><th>State</th><th>Uptime</th><th>Latency</th><th>Recent checks</th></tr>
+{{range .Targets}}<tr>
+  <td><a href="{{.URL}}">{{.ID}}</a>{{if .Tags}} <span class="muted">{{range $i, $tag := .Tags}}{{if $i}}, {{end}}{{$tag}}{{end}}</span>{{end}}</td>
+  <td class="state {{.State}}">{{.State}}</td>
+  <td>{{percent .UptimePercent}} <span class="muted">of {{.Checks}}</span></td>
+  <td>{{with .LastCheck}}{{latency .LatencyMs}}{{end}}</td>
+  <td><svg width="{{sparkWidth}}" height="{{sparkHeight}}" viewBox="0 0 {{sparkWidth}} {{sparkHeight}}" role="img" aria-label="latency of the recent checks">
+    <polyline fill="none" stroke="#1565c0" stroke-width="1.5" points="{{sparkline .Samples}}"/>
+    {{range downMarks .Samples}}<circle cx="{{.}}" cy="{{markY}}" r="2" fill="#c62828"/>{{end}}
+  </svg></td>
+</tr>
+{{end}}</table>
+<h2>Recent incidents</h2>
+{{if .Incidents}}<table>
+<tr><th>Target</th><th>State</th><th>Start</th><th>Duration</th><th>Cause</th></tr>
+{{range .Incidents}}<tr>
+  <td>{{.TargetID}}</td>
+  <td class="state {{.State}}">{{.State}}</td>
+  <td>{{when .Start}}</td>
+  <td>{{lastsFor . $.Generated}}{{if not .End}} (ongoing){{end}}</td>
+  <td class="muted">{{.Cause}}</td>
+</tr>
+{{end}}</table>{{else}}<p class="muted">No incident.</p>{{end}}
+<p class="muted">Generated at {{when .Generated}}</p>
+</body>
+</html>
+`))
+
+// Type Declaration
+// ****************
+
+// A statusPageData is what the status page shows.
+type statusPageData struct {
+	Targets   []targetView
+	Incidents []incident
+	Generated time.Time
+}
+
+// Initializer Function (Type Constructor)
+// ***************************************
+
+// newWebHandler()
+// Initializes the handler of the web interface: Status page, JSON API and metrics.
+func newWebHandler(board *statusBoard, metrics *monitorMetrics) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metrics)
+	mux.HandleFunc("GET /api/targets", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, board.targetViews())
+	})
+	mux.HandleFunc("GET /api/targets/{id}/history", func(w http.ResponseWriter, r *http.Request) {
+		history, found := board.history(r.PathValue("id"))
+		if !found {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "unknown target " + r.PathValue("id")})
+			return
+		}
+		writeJSON(w, http.StatusOK, history)
+	})
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := writeStatusPage(w, board, time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+	return mux
+}
+
+// Helper Functions
+// ****************
+
+// writeStatusPage()
+// Writes the status page of the board, as of now.
+func writeStatusPage(w io.Writer, board *statusBoard, now time.Time) error {
+	return statusPage.Execute(w, statusPageData{Targets: board.targetViews(), Incidents: board.incidents(pageIncidents), Generated: now})
+}
+
+// exportStatusPage()
+// Writes the status page of the board to a file, for publishing. The file is replaced at once:
+// A reader never sees half a page.
+func exportStatusPage(path string, board *statusBoard) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".status-*.html")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	err = writeStatusPage(tmp, board, time.Now())
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	// CreateTemp makes the file private: A published page should be readable
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// writeJSON()
+// Writes a value as indented JSON, with the status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+// sparkline()
+// Returns the points of a polyline of the latencies, oldest on the left, scaled to the slowest.
+func sparkline(samples []statusSample) string {
+	if len(samples) == 0 {
+		return ""
+	}
+	slowest := 0.0
+	for _, s := range samples {
+		slowest = max(slowest, s.LatencyMs)
+	}
+	points := make([]string, len(samples))
+	for i, s := range samples {
+		y := float64(sparklineHeight - 1)
+		if slowest > 0 {
+			y -= s.LatencyMs / slowest * float64(sparklineHeight-2)
+		}
+		points[i] = fmt.Sprintf("%.1f,%.1f", sparkX(i, len(samples)), y)
+	}
+	return strings.Join(points, " ")
+}
+
+// downMarks()
+// Returns the x of the checks that were down, to mark them under the sparkline.
+func downMarks(samples []statusSample) []string {
+	var marks []string
+	for i, s := range samples {
+		if s.Verdict == verdictDown {
+			marks = append(marks, fmt.Sprintf("%.1f", sparkX(i, len(samples))))
+		}
+	}
+	return marks
+}
+
+// sparkX()
+// Returns the x of the i-th of n points: Spread over the width, or in the middle alone.
+func sparkX(i, n int) float64 {
+	if n < 2 {
+		return sparklineWidth / 2
+	}
+	return float64(i) * float64(sparklineWidth-1) / float64(n-1)
+}
+
+// formatLatency()
+// Formats a latency in milliseconds as a duration, such as 125ms or 1.5s.
+func formatLatency(ms float64) string {
+	return time.Duration(ms * float64(time.Millisecond)).Round(time.Millisecond).String()
+}
+
+// incidentDuration()
+// Returns how long an incident lasted, or has lasted so far.
+func incidentDuration(inc incident, now time.Time) string {
+	end := now
+	if inc.End != nil {
+		end = *inc.End
+	}
+	return end.Sub(inc.Start).Round(time.Second).String()
+}
+
+// operational()
+// Tells if no target is down or flapping.
+func operational(targets []targetView) bool {
+	for _, t := range targets {
+		if t.State == statusDown || t.State == statusFlapping {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+/**
+ * @file: Unit tests for the web interface: Status page, JSON API and static export
+ */
+
+// Package
+// *******
+package main
+
+// Imports
+// *******
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test Helpers
+// ************
+
+// get fetches a path of the server, and returns its status, content type and body.
+func get(t *testing.T, server *httptest.Server, path string) (int, string, string) {
+	t.Helper()
+	resp, err := http.Get(server.URL + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, resp.Header.Get("Content-Type"), string(body)
+}
+
+// Test Cases for the web interface
+// ********************************
+//   - /api/targets should list the status of every target
+//   - /api/targets/{id}/history should return the checks and incidents of the target, or a 404
+//   - The status page should show every target with its sparkline, and the incidents
+//   - The static export should write the same page to a file
+
+func Test_webHandler(t *testing.T) {
+	b := newStatusBoard()
+	start := time.Now()
+	api, web := newTarget("https://api.example.com"), newTarget("https://web.example.com")
+	api.id, web.id, web.tags = "api", "web", []string{"prod"}
+	for i := range 5 {
+		at := start.Add(time.Duration(i) * time.Minute)
+		b.observe(checkResult{target: api, checkedAt: at, latency: time.Duration(10+i) * time.Millisecond, verdict: verdictUp}, statusUp)
+		b.observe(checkResult{target: web, checkedAt: at, statusCode: 503, verdict: verdictDown}, statusDown)
+	}
+	b.change(stateChange{target: web, from: statusUp, to: statusDown, at: start,
+		result: checkResult{target: web, statusCode: 503, verdict: verdictDown}})
+	server := httptest.NewServer(newWebHandler(b, newMonitorMetrics()))
+	t.Cleanup(server.Close)
+
+	// TEST CASE 1: Targets
+	// --------------------
+	status, contentType, body := get(t, server, "/api/targets")
+	var targets []map[string]any
+	if err := json.Unmarshal([]byte(body), &targets); err != nil || status != http.StatusOK || contentType != "application/json" {
+		t.Fatalf("Test Case 1: Expected a JSON list. Got %d %s %v: %s", status, contentType, err, body)
+	}
+	if len(targets) != 2 || targets[0]["id"] != "api" || targets[0]["state"] != "up" || targets[0]["uptime_percent"] != 100.0 ||
+		targets[1]["state"] != "down" || targets[1]["open_incident"] == nil || targets[0]["samples"] != nil {
+		t.Errorf("Test Case 1: Expected api up and web down with an incident. Got %s", body)
+	}
+
+	// TEST CASE 2: History
+	// --------------------
+	status, _, body = get(t, server, "/api/targets/web/history")
+	var history targetHistory
+	if err := json.Unmarshal([]byte(body), &history); err != nil || status != http.StatusOK ||
+		len(history.Samples) != 5 || history.Samples[0].StatusCode != 503 || len(history.Incidents) != 1 {
+		t.Errorf("Test Case 2: Expected 5 checks and 1 incident. Got %d %v: %s", status, err, body)
+	}
+	if status, _, _ := get(t, server, "/api/targets/nope/history"); status != http.StatusNotFound {
+		t.Errorf("Test Case 2: Expected a 404 for an unknown target. Got %d", status)
+	}
+
+	// TEST CASE 3: Status page
+	// ------------------------
+	status, contentType, body = get(t, server, "/")
+	for _, want := range []string{"Some systems are having problems", `<a href="https://api.example.com">api</a>`,
+		`class="state down">down`, "<polyline", "<circle", "100.00%", "(ongoing)", "status 503"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Test Case 3: Expected %q in the page. Got:\n%s", want, body)
+		}
+	}
+	if status != http.StatusOK || !strings.HasPrefix(contentType, "text/html") {
+		t.Errorf("Test Case 3: Expected an HTML page. Got %d %s", status, contentType)
+	}
+	if status, _, _ := get(t, server, "/nope"); status != http.StatusNotFound {
+		t.Errorf("Test Case 3: Expected a 404 for an unknown page. Got %d", status)
+	}
+
+	// TEST CASE 4: Static export
+	// --------------------------
+	path := filepath.Join(t.TempDir(), "status.html")
+	if err := exportStatusPage(path, b); err != nil {
+		t.Fatalf("Test Case 4: Expected the page to be exported. Got %v", err)
+	}
+	exported, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(exported), "<!DOCTYPE html>") || !strings.Contains(string(exported), `class="state down">down`) {
+		t.Errorf("Test Case 4: Expected the status page in the file. Got:\n%s", exported)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("Test Case 4: Expected no temporary file left. Got %d files", len(entries))
+	}
+}